@@ -55,6 +55,12 @@ var (
 	ItemStyle = lipgloss.NewStyle().
 			PaddingLeft(4)
 
+	// MatchStyle highlights characters matched by a search query
+	MatchStyle = lipgloss.NewStyle().
+			Foreground(Warning).
+			Bold(true).
+			Underline(true)
+
 	// Method Styles
 	MethodGET = lipgloss.NewStyle().
 			Foreground(Success).
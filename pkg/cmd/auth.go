@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ksysoev/tapi/pkg/environment"
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+// parseCredentials builds credentials for the spec's security schemes from
+// --auth values. "scheme=value" sets the API key or token of a scheme, or
+// "username:password" for basic auth, and "scheme.field=value" sets one of
+// the fields the scheme takes, like "oauth.client_id=app". Values may
+// reference environment variables from vars as {{name}}.
+func parseCredentials(spec *openapi.Spec, auth []string, vars map[string]string) (map[string]request.Credential, error) {
+	values := make(map[string]map[string]string)
+
+	for _, a := range auth {
+		key, value, ok := strings.Cut(a, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid credential %q, expected scheme=value or scheme.field=value", a)
+		}
+		value = environment.Interpolate(value, vars)
+
+		name, field := key, ""
+		if _, ok := spec.SecuritySchemes[name]; !ok {
+			if i := strings.LastIndex(key, "."); i >= 0 {
+				name, field = key[:i], key[i+1:]
+			}
+		}
+
+		scheme, ok := spec.SecuritySchemes[name]
+		if !ok && len(spec.SecuritySchemes) == 0 {
+			return nil, fmt.Errorf("unknown security scheme %q, the spec doesn't declare any", name)
+		}
+		if !ok {
+			return nil, fmt.Errorf("unknown security scheme %q, the spec declares: %s", name, strings.Join(schemeNames(spec), ", "))
+		}
+
+		if values[name] == nil {
+			values[name] = make(map[string]string)
+		}
+
+		fields := request.CredentialFields(scheme)
+		switch {
+		case field != "":
+			if !slices.Contains(fields, field) {
+				return nil, fmt.Errorf("security scheme %q has no field %q, use one of: %s", name, field, strings.Join(fields, ", "))
+			}
+			values[name][field] = value
+		case slices.Equal(fields, []string{"username", "password"}):
+			values[name]["username"], values[name]["password"], _ = strings.Cut(value, ":")
+		default:
+			values[name][fields[0]] = value
+		}
+	}
+
+	creds := make(map[string]request.Credential, len(values))
+	for name, schemeValues := range values {
+		cred, err := request.NewCredential(spec.SecuritySchemes[name], schemeValues)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credentials for %s: %w", name, err)
+		}
+		if cred != nil {
+			creds[name] = cred
+		}
+	}
+
+	return creds, nil
+}
+
+func schemeNames(spec *openapi.Spec) []string {
+	names := make([]string, 0, len(spec.SecuritySchemes))
+	for name := range spec.SecuritySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/oauth"
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+func TestParseCredentials(t *testing.T) {
+	spec := &openapi.Spec{SecuritySchemes: map[string]openapi.SecurityScheme{
+		"api_key": {Type: "apiKey", In: "header", ParamName: "X-API-Key"},
+		"basic":   {Type: "http", Scheme: "basic"},
+		"bearer":  {Type: "http", Scheme: "bearer"},
+		"oauth": {Type: "oauth2", Flows: &openapi.OAuthFlows{
+			ClientCredentials: &openapi.OAuthFlow{TokenURL: "https://auth.example.com/token"},
+		}},
+	}}
+
+	tests := []struct {
+		name    string
+		auth    []string
+		want    map[string]request.Credential
+		wantErr string
+	}{
+		{
+			name: "shorthand values",
+			auth: []string{"api_key={{key}}", "basic=user:p:w", "bearer=tok"},
+			want: map[string]request.Credential{
+				"api_key": request.APIKey{Name: "X-API-Key", In: "header", Value: "secret"},
+				"basic":   request.BasicAuth{Username: "user", Password: "p:w"},
+				"bearer":  request.HTTPAuth{Scheme: "Bearer", Token: "tok"},
+			},
+		},
+		{
+			name: "fields",
+			auth: []string{"basic.username=user", "basic.password=pass", "oauth.token=tok"},
+			want: map[string]request.Credential{
+				"basic": request.BasicAuth{Username: "user", Password: "pass"},
+				"oauth": request.HTTPAuth{Scheme: "Bearer", Token: "tok"},
+			},
+		},
+		{name: "missing value", auth: []string{"bearer"}, wantErr: `invalid credential "bearer"`},
+		{name: "unknown scheme", auth: []string{"jwt=tok"}, wantErr: `unknown security scheme "jwt", the spec declares: api_key, basic, bearer, oauth`},
+		{name: "unknown field", auth: []string{"basic.token=tok"}, wantErr: `security scheme "basic" has no field "token", use one of: username, password`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCredentials(spec, tt.auth, map[string]string{"key": "secret"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseCredentials() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCredentials() error = %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseCredentials() = %#v, want %#v", got, tt.want)
+			}
+			for name, cred := range tt.want {
+				if got[name] != cred {
+					t.Errorf("credential %s = %#v, want %#v", name, got[name], cred)
+				}
+			}
+		})
+	}
+
+	creds, err := parseCredentials(spec, []string{"oauth.client_id=app", "oauth.client_secret=s"}, nil)
+	if err != nil {
+		t.Fatalf("parseCredentials() error = %v", err)
+	}
+	if _, ok := creds["oauth"].(*oauth.Manager); !ok {
+		t.Errorf("parseCredentials() = %#v, want an OAuth2 client", creds)
+	}
+}
+
+const securedSpec = `openapi: 3.0.3
+info: {title: Secured, version: "1.0"}
+security:
+  - api_key: []
+components:
+  securitySchemes:
+    api_key: {type: apiKey, in: query, name: api_key}
+    bearer: {type: http, scheme: bearer}
+paths:
+  /me:
+    get:
+      operationId: getMe
+      security:
+        - bearer: []
+      responses:
+        '200': {description: ok}
+  /items:
+    get:
+      operationId: listItems
+      responses:
+        '200': {description: ok}
+`
+
+func TestRunCallAuth(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/me":
+			if r.Header.Get("Authorization") != "Bearer tok" || r.URL.RawQuery != "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		case "/items":
+			if r.URL.Query().Get("api_key") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	spec := writeSpec(t, "secured.yaml", securedSpec)
+
+	for _, operation := range []string{"getMe", "listItems"} {
+		opts := callOptions{filePath: spec, server: server.URL, operation: operation, auth: []string{"bearer=tok", "api_key=secret"}, output: outputRaw}
+		if err := runCall(context.Background(), opts, strings.NewReader(""), io.Discard, io.Discard); err != nil {
+			t.Errorf("runCall(%s) error = %v", operation, err)
+		}
+	}
+
+	opts := callOptions{filePath: spec, server: server.URL, operation: "getMe", output: outputRaw}
+	if err := runCall(context.Background(), opts, strings.NewReader(""), io.Discard, io.Discard); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("runCall() without --auth error = %v, want 401", err)
+	}
+}
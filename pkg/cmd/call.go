@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ksysoev/tapi/pkg/curl"
+	"github.com/ksysoev/tapi/pkg/environment"
+	"github.com/ksysoev/tapi/pkg/formatter"
+	"github.com/ksysoev/tapi/pkg/history"
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+const (
+	outputPretty = "pretty"
+	outputRaw    = "raw"
+	outputJSON   = "json"
+)
+
+type callOptions struct {
+	filePath    string
+	url         string
+	server      string
+	serverVars  []string
+	env         string
+	operation   string
+	fromCurl    string
+	params      []string
+	headers     []string
+	data        string
+	contentType string
+	auth        []string
+	output      string
+}
+
+type callResult struct {
+	StatusCode int                 `json:"statusCode"`
+	Status     string              `json:"status"`
+	Headers    map[string][]string `json:"headers"`
+	Body       json.RawMessage     `json:"body"`
+}
+
+func runCall(ctx context.Context, opts callOptions, stdin io.Reader, stdout, stderr io.Writer) error {
+	switch opts.output {
+	case outputPretty, outputRaw, outputJSON:
+	default:
+		return fmt.Errorf("unsupported output format %q, use pretty, raw or json", opts.output)
+	}
+
+	spec, err := loadSpec(opts.filePath, opts.url)
+	if err != nil {
+		return err
+	}
+
+	vars, err := loadEnvironment(opts.env)
+	if err != nil {
+		return err
+	}
+
+	path, op, opts, err := resolveCallOperation(spec, opts, vars, stdin, stderr)
+	if err != nil {
+		return err
+	}
+
+	req, err := buildCallRequest(spec, path, op, opts, vars, stdin)
+	if err != nil {
+		return err
+	}
+
+	resp := request.Do(ctx, req)
+	saveHistory(spec, op, opts, resp, stderr)
+
+	if resp.Error != nil {
+		return resp.Error
+	}
+
+	if err := writeResponse(resp, opts.output, stdout, stderr); err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("request failed with status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// resolveCallOperation finds the operation to call. With --from-curl it's
+// taken from the curl command, whose parameters, headers and body become
+// defaults for the flags, and whose host is used unless --server or the
+// environment's baseUrl say otherwise.
+func resolveCallOperation(spec *openapi.Spec, opts callOptions, vars map[string]string, stdin io.Reader, stderr io.Writer) (*openapi.Path, *openapi.Operation, callOptions, error) {
+	if opts.fromCurl == "" {
+		path, op, err := spec.FindOperation(opts.operation)
+		return path, op, opts, err
+	}
+
+	cmdline, err := readBody(opts.fromCurl, stdin)
+	if err != nil {
+		return nil, nil, opts, err
+	}
+
+	imported, err := curl.Import(spec, cmdline)
+	if err != nil {
+		return nil, nil, opts, fmt.Errorf("failed to import curl command: %w", err)
+	}
+
+	for _, ignored := range imported.Ignored {
+		_, _ = fmt.Fprintf(stderr, "warning: ignored %s, %s %s doesn't declare it\n",
+			ignored, imported.Operation.Method, imported.Path.Path)
+	}
+
+	if opts.server == "" && vars[environment.BaseURLVar] == "" {
+		opts.server = imported.Server
+	}
+
+	params := make([]string, 0, len(imported.Operation.Parameters))
+	for _, param := range imported.Operation.Parameters {
+		if value, ok := imported.Value(param); ok {
+			params = append(params, imported.Operation.ParamKey(param)+"="+value)
+		}
+	}
+	// Flags come last so they override the values from the command.
+	opts.params = append(params, opts.params...)
+
+	opts.headers = append(joinPairs(imported.Headers, ": "), opts.headers...)
+
+	if opts.contentType == "" {
+		opts.contentType = imported.ContentType
+	}
+
+	if opts.data == "" {
+		opts.data = imported.Body
+	}
+
+	return imported.Path, imported.Operation, opts, nil
+}
+
+// buildCallRequest turns the flags into a request. Values may reference
+// environment variables from vars as {{name}}. The body is sent as the first
+// media type the operation declares unless the content type is set.
+func buildCallRequest(spec *openapi.Spec, path *openapi.Path, op *openapi.Operation, opts callOptions, vars map[string]string, stdin io.Reader) (request.Request, error) {
+	server := opts.server
+	if server == "" {
+		server = vars[environment.BaseURLVar]
+	}
+	if servers := spec.ServersFor(op); server == "" && len(servers) > 0 {
+		vars, err := parsePairs(opts.serverVars, "server variable")
+		if err != nil {
+			return request.Request{}, err
+		}
+		server = servers[0].ResolveURL(vars)
+	}
+
+	if server == "" {
+		return request.Request{}, fmt.Errorf("spec doesn't declare any servers, use --server")
+	}
+
+	req := request.Request{
+		BaseURL: environment.Interpolate(server, vars),
+		Path:    path.Path,
+		Method:  op.Method,
+	}
+
+	values, err := parsePairs(opts.params, "parameter")
+	if err != nil {
+		return request.Request{}, err
+	}
+
+	for _, param := range op.Parameters {
+		value, key, ok := op.ParamValue(values, param)
+		if !ok {
+			if param.Required {
+				return request.Request{}, fmt.Errorf("missing required %s parameter %q", param.In, param.Name)
+			}
+			continue
+		}
+
+		delete(values, key)
+		req.SetParam(param, environment.Interpolate(value, vars))
+	}
+
+	if len(values) > 0 {
+		unknown := make([]string, 0, len(values))
+		for name := range values {
+			for _, param := range op.Parameters {
+				if param.Name == name && op.ParamKey(param) != name {
+					return request.Request{}, fmt.Errorf("parameter %q is declared in several locations, use %s", name, op.ParamKey(param))
+				}
+			}
+			unknown = append(unknown, name)
+		}
+		sort.Strings(unknown)
+
+		return request.Request{}, fmt.Errorf("unknown parameters for %s %s: %s", op.Method, path.Path, strings.Join(unknown, ", "))
+	}
+
+	for _, h := range opts.headers {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok {
+			return request.Request{}, fmt.Errorf("invalid header %q, expected \"Name: value\"", h)
+		}
+
+		if req.Headers == nil {
+			req.Headers = make(map[string]string)
+		}
+		req.Headers[strings.TrimSpace(name)] = environment.Interpolate(strings.TrimSpace(value), vars)
+	}
+
+	body, err := readBody(opts.data, stdin)
+	if err != nil {
+		return request.Request{}, err
+	}
+	req.Body = environment.Interpolate(body, vars)
+
+	req.ContentType = opts.contentType
+	if req.ContentType == "" && op.RequestBody != nil && len(op.RequestBody.Content) > 0 {
+		req.ContentType = openapi.SortedContentTypes(op.RequestBody.Content)[0]
+	}
+
+	creds, err := parseCredentials(spec, opts.auth, vars)
+	if err != nil {
+		return request.Request{}, err
+	}
+	req.Credentials = request.ResolveCredentials(op.Security, creds)
+
+	return req, nil
+}
+
+// loadEnvironment returns the variables of the named environment from the
+// user config, or nil when no environment is selected.
+func loadEnvironment(name string) (map[string]string, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	cfg, err := environment.LoadDefault()
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.Get(name)
+}
+
+// saveHistory records the call in the request history. Failing to do so
+// only warns, the request itself went through.
+func saveHistory(spec *openapi.Spec, op *openapi.Operation, opts callOptions, resp request.ResponseMsg, stderr io.Writer) {
+	store, err := history.OpenDefault()
+	if err == nil {
+		entry := history.NewEntry(resp)
+		entry.Spec = spec.Title
+		entry.OperationID = op.OperationID
+
+		// Flags were validated while building the request.
+		values, _ := parsePairs(opts.params, "parameter")
+		for _, param := range op.Parameters {
+			if value, _, ok := op.ParamValue(values, param); ok {
+				entry.Inputs = append(entry.Inputs, history.Input{Name: param.Name, In: param.In, Value: value})
+			}
+		}
+
+		entry.Redact(spec.SecuritySchemes)
+
+		err = store.Add(entry)
+	}
+
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "warning: failed to save history: %v\n", err)
+	}
+}
+
+// parsePairs parses repeated name=value flags.
+func parsePairs(pairs []string, kind string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		name, value, ok := strings.Cut(p, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid %s %q, expected name=value", kind, p)
+		}
+		values[name] = value
+	}
+
+	return values, nil
+}
+
+// readBody resolves the --data value the way curl does: "@file" reads a
+// file and "@-" reads stdin.
+func readBody(data string, stdin io.Reader) (string, error) {
+	switch {
+	case data == "@-":
+		body, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read body from stdin: %w", err)
+		}
+		return string(body), nil
+	case strings.HasPrefix(data, "@"):
+		body, err := os.ReadFile(data[1:])
+		if err != nil {
+			return "", fmt.Errorf("failed to read body file: %w", err)
+		}
+		return string(body), nil
+	default:
+		return data, nil
+	}
+}
+
+func writeResponse(resp request.ResponseMsg, output string, stdout, stderr io.Writer) error {
+	switch output {
+	case outputRaw:
+		_, err := io.WriteString(stdout, resp.Body)
+		return err
+	case outputJSON:
+		body := json.RawMessage(resp.Body)
+		if !json.Valid(body) {
+			encoded, err := json.Marshal(resp.Body)
+			if err != nil {
+				return fmt.Errorf("failed to encode response body: %w", err)
+			}
+			body = encoded
+		}
+
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(callResult{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Headers:    resp.Headers,
+			Body:       body,
+		})
+	default:
+		_, _ = fmt.Fprintln(stderr, resp.Status)
+		_, err := fmt.Fprintln(stdout, formatter.DetectAndFormat(resp.Body, resp.Headers.Get("Content-Type")))
+		return err
+	}
+}
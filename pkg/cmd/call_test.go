@@ -0,0 +1,489 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/history"
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+func newCallTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	// Keep the request history out of the user's data directory.
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/pet/10":
+			if got := r.Header.Get("X-Trace"); got != "abc" {
+				t.Errorf("X-Trace header = %q, want abc", got)
+			}
+			_, _ = w.Write([]byte(`{"id":10,"name":"doggie"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/pet":
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestRunCall(t *testing.T) {
+	server := newCallTestServer(t)
+
+	bodyFile := filepath.Join(t.TempDir(), "pet.json")
+	if err := os.WriteFile(bodyFile, []byte(`{"name":"from-file"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     callOptions
+		stdin    string
+		wantOut  string
+		wantErr  string
+		wantJSON bool
+	}{
+		{
+			name:    "by operationId with path param and header",
+			opts:    callOptions{operation: "getPetById", params: []string{"petId=10"}, headers: []string{"X-Trace: abc"}, output: outputRaw},
+			wantOut: `{"id":10,"name":"doggie"}`,
+		},
+		{
+			name:    "by method and path with inline body",
+			opts:    callOptions{operation: "POST /pet", data: `{"name":"inline"}`, output: outputRaw},
+			wantOut: `{"name":"inline"}`,
+		},
+		{
+			name:    "body from file",
+			opts:    callOptions{operation: "addPet", data: "@" + bodyFile, output: outputRaw},
+			wantOut: `{"name":"from-file"}`,
+		},
+		{
+			name:    "body from stdin",
+			opts:    callOptions{operation: "addPet", data: "@-", output: outputRaw},
+			stdin:   `{"name":"from-stdin"}`,
+			wantOut: `{"name":"from-stdin"}`,
+		},
+		{
+			name:     "json output",
+			opts:     callOptions{operation: "getPetById", params: []string{"petId=10"}, headers: []string{"X-Trace: abc"}, output: outputJSON},
+			wantJSON: true,
+		},
+		{
+			name:    "HTTP error fails the call",
+			opts:    callOptions{operation: "deletePet", params: []string{"petId=1"}, output: outputRaw},
+			wantOut: `{"message":"not found"}`,
+			wantErr: "404",
+		},
+		{
+			name:    "missing required parameter",
+			opts:    callOptions{operation: "getPetById", output: outputRaw},
+			wantErr: `missing required path parameter "petId"`,
+		},
+		{
+			name:    "unknown parameter",
+			opts:    callOptions{operation: "getPetById", params: []string{"petId=1", "foo=bar"}, output: outputRaw},
+			wantErr: "unknown parameters for GET /pet/{petId}: foo",
+		},
+		{
+			name:    "unknown operation",
+			opts:    callOptions{operation: "nope", output: outputRaw},
+			wantErr: `operation "nope" not found`,
+		},
+		{
+			name:    "invalid output",
+			opts:    callOptions{operation: "getPetById", output: "xml"},
+			wantErr: "unsupported output format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.filePath = "../../example-petstore.yaml"
+			tt.opts.server = server.URL
+
+			var stdout, stderr bytes.Buffer
+			err := runCall(context.Background(), tt.opts, strings.NewReader(tt.stdin), &stdout, &stderr)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tt.wantOut != "" && stdout.String() != tt.wantOut {
+				t.Errorf("output = %q, want %q", stdout.String(), tt.wantOut)
+			}
+
+			if tt.wantJSON {
+				var result callResult
+				if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+					t.Fatalf("output is not JSON: %v", err)
+				}
+
+				var pet struct {
+					Name string `json:"name"`
+				}
+				if err := json.Unmarshal(result.Body, &pet); err != nil {
+					t.Fatalf("body is not embedded as JSON: %v", err)
+				}
+
+				if result.StatusCode != http.StatusOK || pet.Name != "doggie" {
+					t.Errorf("unexpected result: %+v", result)
+				}
+			}
+		})
+	}
+}
+
+func TestCallCommandValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		errMsg string
+	}{
+		{name: "no operation", args: []string{"call", "--file", "../../example-petstore.yaml"}, errMsg: "accepts between 1 and 2 arg(s)"},
+		{name: "no spec", args: []string{"call", "getPetById"}, errMsg: "either --file or --url must be specified"},
+		{name: "operation with --from-curl", args: []string{"call", "--from-curl", "curl http://localhost/pet/1", "getPetById"}, errMsg: "the operation is taken from --from-curl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := InitCommand(BuildInfo{Version: "1.0.0", AppName: "tapi"})
+			cmd.SetArgs(tt.args)
+
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			cmd.SetErr(buf)
+
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestBuildCallRequestServer(t *testing.T) {
+	spec := &openapi.Spec{
+		Servers: []openapi.Server{{
+			URL:       "https://{env}.example.com",
+			Variables: map[string]openapi.ServerVariable{"env": {Default: "prod"}},
+		}},
+	}
+	path := &openapi.Path{Path: "/pets"}
+	override := &openapi.Operation{Method: "GET", Servers: []openapi.Server{{URL: "https://pets.example.com"}}}
+
+	tests := []struct {
+		name     string
+		op       *openapi.Operation
+		opts     callOptions
+		expected string
+		wantErr  bool
+	}{
+		{name: "variable default", op: &openapi.Operation{Method: "GET"}, expected: "https://prod.example.com"},
+		{name: "variable from flag", op: &openapi.Operation{Method: "GET"}, opts: callOptions{serverVars: []string{"env=staging"}}, expected: "https://staging.example.com"},
+		{name: "operation override", op: override, expected: "https://pets.example.com"},
+		{name: "--server wins", op: override, opts: callOptions{server: "http://localhost:8080"}, expected: "http://localhost:8080"},
+		{name: "invalid variable", op: &openapi.Operation{Method: "GET"}, opts: callOptions{serverVars: []string{"env"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := buildCallRequest(spec, path, tt.op, tt.opts, nil, strings.NewReader(""))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if req.BaseURL != tt.expected {
+				t.Errorf("BaseURL = %q, want %q", req.BaseURL, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRunCallEnvironment(t *testing.T) {
+	server := newCallTestServer(t)
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	config := "environments:\n  dev:\n    baseUrl: " + server.URL + "\n    petId: \"10\"\n    trace: abc\n"
+	if err := os.MkdirAll(filepath.Join(dir, "tapi"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tapi", "config.yaml"), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := callOptions{
+		filePath:  "../../example-petstore.yaml",
+		env:       "dev",
+		operation: "getPetById",
+		params:    []string{"petId={{petId}}"},
+		headers:   []string{"X-Trace: {{ trace }}"},
+		output:    outputRaw,
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := runCall(context.Background(), opts, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("runCall() error = %v", err)
+	}
+
+	if stdout.String() != `{"id":10,"name":"doggie"}` {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+
+	opts.env = "prod"
+	if err := runCall(context.Background(), opts, strings.NewReader(""), &stdout, &stderr); err == nil || !strings.Contains(err.Error(), `environment "prod" not found`) {
+		t.Errorf("runCall() error = %v, want unknown environment", err)
+	}
+}
+
+func TestRunCallSavesHistory(t *testing.T) {
+	server := newCallTestServer(t)
+
+	opts := callOptions{
+		filePath:  "../../example-petstore.yaml",
+		server:    server.URL,
+		operation: "getPetById",
+		params:    []string{"petId=10"},
+		headers:   []string{"X-Trace: abc"},
+		output:    outputRaw,
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := runCall(context.Background(), opts, strings.NewReader(""), &stdout, &stderr); err != nil {
+		t.Fatalf("runCall() error = %v", err)
+	}
+
+	store, err := history.OpenDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := store.List()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("List() = %v, %v", entries, err)
+	}
+
+	e := entries[0]
+	if e.OperationID != "getPetById" || e.URL != server.URL+"/pet/10" || e.StatusCode != http.StatusOK {
+		t.Errorf("entry = %+v", e)
+	}
+
+	if value, _ := e.Input("petId", "path"); value != "10" {
+		t.Errorf("inputs = %+v", e.Inputs)
+	}
+
+	if strings.Contains(stderr.String(), "warning") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestRunCallFromCurl(t *testing.T) {
+	server := newCallTestServer(t)
+
+	tests := []struct {
+		name       string
+		opts       callOptions
+		stdin      string
+		wantOut    string
+		wantStderr string
+	}{
+		{
+			name:       "operation and parameters from the command",
+			opts:       callOptions{fromCurl: `curl '` + server.URL + `/pet/10?debug=1' -H 'X-Trace: abc'`},
+			wantOut:    `{"id":10,"name":"doggie"}`,
+			wantStderr: "warning: ignored query parameter debug, GET /pet/{petId} doesn't declare it",
+		},
+		{
+			name:    "flags override the command",
+			opts:    callOptions{fromCurl: `curl ` + server.URL + `/pet/1 -H 'X-Trace: abc'`, params: []string{"petId=10"}},
+			wantOut: `{"id":10,"name":"doggie"}`,
+		},
+		{
+			name:    "command with body from stdin",
+			opts:    callOptions{fromCurl: "@-"},
+			stdin:   "curl -X POST \\\n  " + server.URL + "/pet \\\n  --data-raw '{\"name\":\"curl\"}'",
+			wantOut: `{"name":"curl"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.filePath = "../../example-petstore.yaml"
+			tt.opts.output = outputRaw
+
+			var stdout, stderr bytes.Buffer
+
+			if err := runCall(context.Background(), tt.opts, strings.NewReader(tt.stdin), &stdout, &stderr); err != nil {
+				t.Fatalf("runCall() error = %v", err)
+			}
+
+			if stdout.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.wantOut)
+			}
+
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("stderr = %q, want %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+const formSpec = `openapi: 3.0.3
+info: {title: Login, version: "1"}
+paths:
+  /login:
+    post:
+      operationId: login
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema: {type: object}
+          application/xml:
+            schema: {type: object}
+      responses:
+        '200': {description: ok}
+`
+
+// newContentTypeServer echoes the Content-Type and body it receives.
+func newContentTypeServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(r.Header.Get("Content-Type") + " " + string(body)))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestRunCallContentType(t *testing.T) {
+	server := newContentTypeServer(t)
+	spec := writeSpec(t, "login.yaml", formSpec)
+
+	tests := []struct {
+		name    string
+		opts    callOptions
+		wantOut string
+	}{
+		{
+			name:    "first declared media type",
+			opts:    callOptions{operation: "login", data: "user=alice&pass=x"},
+			wantOut: "application/x-www-form-urlencoded user=alice&pass=x",
+		},
+		{
+			name:    "content type flag",
+			opts:    callOptions{operation: "login", data: "<login/>", contentType: "application/xml"},
+			wantOut: "application/xml <login/>",
+		},
+		{
+			name:    "content type from curl",
+			opts:    callOptions{fromCurl: `curl ` + server.URL + `/login -H 'Content-Type: application/xml' -d '<login/>'`},
+			wantOut: "application/xml <login/>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.filePath = spec
+			tt.opts.server = server.URL
+			tt.opts.output = outputRaw
+
+			var stdout, stderr bytes.Buffer
+
+			if err := runCall(context.Background(), tt.opts, strings.NewReader(""), &stdout, &stderr); err != nil {
+				t.Fatalf("runCall() error = %v", err)
+			}
+
+			if stdout.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestBuildCallRequestSharedParamNames(t *testing.T) {
+	spec := &openapi.Spec{Servers: []openapi.Server{{URL: "https://api.example.com"}}}
+	path := &openapi.Path{Path: "/pets/{id}"}
+	op := &openapi.Operation{Method: "GET", Parameters: []openapi.Parameter{
+		{Name: "id", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}},
+		{Name: "id", In: "query", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "limit", In: "query", Schema: &openapi.Schema{Type: "integer"}},
+	}}
+
+	tests := []struct {
+		name    string
+		params  []string
+		wantURL string
+		wantErr string
+	}{
+		{
+			name:    "values by location",
+			params:  []string{"path:id=1", "query:id=2", "limit=5"},
+			wantURL: "https://api.example.com/pets/1?id=2&limit=5",
+		},
+		{
+			name:    "location of an unshared name",
+			params:  []string{"path:id=1", "query:limit=5"},
+			wantURL: "https://api.example.com/pets/1?limit=5",
+		},
+		{
+			name:    "shared name without location",
+			params:  []string{"path:id=1", "id=2"},
+			wantErr: `parameter "id" is declared in several locations, use path:id`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := buildCallRequest(spec, path, op, callOptions{params: tt.params}, nil, strings.NewReader(""))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("buildCallRequest() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("buildCallRequest() error = %v", err)
+			}
+
+			if got := req.URL(); got != tt.wantURL {
+				t.Errorf("URL() = %q, want %q", got, tt.wantURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/ksysoev/tapi/pkg/collection"
+	"github.com/ksysoev/tapi/pkg/environment"
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+const outputText = "text"
+
+type collectionRunOptions struct {
+	filePath       string
+	url            string
+	collectionPath string
+	server         string
+	serverVars     []string
+	env            string
+	output         string
+}
+
+type collectionResult struct {
+	Name       string `json:"name"`
+	Operation  string `json:"operation"`
+	Method     string `json:"method,omitempty"`
+	URL        string `json:"url,omitempty"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Status     string `json:"status,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+	Passed     bool   `json:"passed"`
+	Error      string `json:"error,omitempty"`
+}
+
+// runCollection sends the saved requests of a collection one after another,
+// or only the named ones, and reports the outcome of each. A request passes
+// when it gets a response with a status below 400.
+func runCollection(ctx context.Context, opts collectionRunOptions, names []string, stdout, stderr io.Writer) error {
+	switch opts.output {
+	case outputText, outputJSON:
+	default:
+		return fmt.Errorf("unsupported output format %q, use text or json", opts.output)
+	}
+
+	spec, err := loadSpec(opts.filePath, opts.url)
+	if err != nil {
+		return err
+	}
+
+	path := opts.collectionPath
+	if path == "" {
+		if path, err = collection.DefaultPath(opts.filePath, spec.Title); err != nil {
+			return err
+		}
+	}
+
+	c, err := collection.Load(path)
+	if err != nil {
+		return err
+	}
+
+	saved := c.Requests
+	if len(names) > 0 {
+		saved = make([]collection.Request, 0, len(names))
+		for _, name := range names {
+			r, err := c.Find(name)
+			if err != nil {
+				return err
+			}
+			saved = append(saved, *r)
+		}
+	}
+
+	if len(saved) == 0 {
+		return fmt.Errorf("collection %s has no requests", path)
+	}
+
+	vars, err := loadEnvironment(opts.env)
+	if err != nil {
+		return err
+	}
+
+	results := make([]collectionResult, 0, len(saved))
+	for _, r := range saved {
+		results = append(results, runSavedRequest(ctx, spec, r, opts, vars, stderr))
+	}
+
+	if err := writeCollectionResults(results, opts.output, stdout); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Passed {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d requests failed", failed, len(results))
+	}
+
+	return nil
+}
+
+func runSavedRequest(ctx context.Context, spec *openapi.Spec, saved collection.Request, opts collectionRunOptions, vars map[string]string, stderr io.Writer) collectionResult {
+	result := collectionResult{Name: saved.Name, Operation: saved.Operation}
+
+	path, op, err := spec.FindOperation(saved.Operation)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	call := callOptions{
+		server:      opts.server,
+		serverVars:  opts.serverVars,
+		params:      joinPairs(saved.Params, "="),
+		headers:     joinPairs(saved.Headers, ": "),
+		contentType: saved.ContentType,
+	}
+
+	req, err := buildCallRequest(spec, path, op, call, vars, strings.NewReader(""))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	req.Body = environment.Interpolate(saved.Body, vars)
+
+	resp := request.Do(ctx, req)
+	saveHistory(spec, op, call, resp, stderr)
+
+	result.Method = req.Method
+	result.URL = req.URL()
+	result.DurationMs = resp.Duration.Milliseconds()
+
+	if resp.Error != nil {
+		result.Error = resp.Error.Error()
+		return result
+	}
+
+	result.StatusCode = resp.StatusCode
+	result.Status = resp.Status
+	result.Passed = resp.StatusCode < 400
+
+	return result
+}
+
+// joinPairs turns a map back into the flag form buildCallRequest parses, in
+// a stable order.
+func joinPairs(values map[string]string, sep string) []string {
+	pairs := make([]string, 0, len(values))
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		pairs = append(pairs, name+sep+values[name])
+	}
+
+	return pairs
+}
+
+func writeCollectionResults(results []collectionResult, output string, stdout io.Writer) error {
+	if output == outputJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+
+	lines := make([]resultLine, 0, len(results))
+	for _, r := range results {
+		lines = append(lines, resultLine{
+			passed:     r.Passed,
+			name:       r.Name,
+			target:     r.Method + " " + r.URL,
+			outcome:    outcome(r.Status, r.Error),
+			durationMs: r.DurationMs,
+		})
+	}
+
+	return writeResultLines(lines, stdout)
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/collection"
+	"github.com/ksysoev/tapi/pkg/history"
+)
+
+func writeTestCollection(t *testing.T, requests ...collection.Request) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "petstore.collection.yaml")
+	c := &collection.Collection{Requests: requests}
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestRunCollection(t *testing.T) {
+	server := newCallTestServer(t)
+
+	path := writeTestCollection(t,
+		collection.Request{Name: "get pet", Operation: "getPetById", Params: map[string]string{"petId": "10"}, Headers: map[string]string{"X-Trace": "abc"}},
+		collection.Request{Name: "add pet", Operation: "POST /pet", Body: `{"name":"rex"}`, ContentType: "application/json"},
+		collection.Request{Name: "delete pet", Operation: "deletePet", Params: map[string]string{"petId": "1"}},
+		collection.Request{Name: "gone", Operation: "removedOperation"},
+	)
+
+	opts := collectionRunOptions{
+		filePath:       "../../example-petstore.yaml",
+		collectionPath: path,
+		server:         server.URL,
+		output:         outputText,
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := runCollection(context.Background(), opts, nil, &stdout, &stderr)
+	if err == nil || err.Error() != "2 of 4 requests failed" {
+		t.Errorf("runCollection() error = %v", err)
+	}
+
+	out := stdout.String()
+	for _, want := range []string{
+		"✓ get pet", "GET " + server.URL + "/pet/10", "200 OK",
+		"✓ add pet", "201 Created",
+		"✗ delete pet", "404 Not Found",
+		"✗ gone", `operation "removedOperation" not found`,
+		"2 passed, 2 failed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	stdout.Reset()
+	opts.output = outputJSON
+	if err := runCollection(context.Background(), opts, []string{"get pet", "add pet"}, &stdout, &stderr); err != nil {
+		t.Fatalf("runCollection() with names error = %v", err)
+	}
+
+	var results []collectionResult
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, stdout.String())
+	}
+
+	if len(results) != 2 || results[0].Name != "get pet" || results[1].StatusCode != 201 || !results[1].Passed {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func TestRunCollectionContentType(t *testing.T) {
+	server := newContentTypeServer(t)
+
+	path := writeTestCollection(t,
+		collection.Request{Name: "login", Operation: "login", Body: "user=alice"},
+	)
+
+	opts := collectionRunOptions{
+		filePath:       writeSpec(t, "login.yaml", formSpec),
+		collectionPath: path,
+		server:         server.URL,
+		output:         outputText,
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := runCollection(context.Background(), opts, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("runCollection() error = %v", err)
+	}
+
+	store, err := history.OpenDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 || entries[0].ResponseBody != "application/x-www-form-urlencoded user=alice" {
+		t.Errorf("history = %+v, want the body sent as a form", entries)
+	}
+}
+
+func TestRunCollectionErrors(t *testing.T) {
+	newCallTestServer(t)
+
+	empty := writeTestCollection(t)
+	withRequest := writeTestCollection(t, collection.Request{Name: "get pet", Operation: "getPetById"})
+
+	tests := []struct {
+		name    string
+		opts    collectionRunOptions
+		names   []string
+		wantErr string
+	}{
+		{name: "empty collection", opts: collectionRunOptions{collectionPath: empty, output: outputText}, wantErr: "has no requests"},
+		{name: "unknown request", opts: collectionRunOptions{collectionPath: withRequest, output: outputText}, names: []string{"nope"}, wantErr: `request "nope" not found`},
+		{name: "invalid output", opts: collectionRunOptions{collectionPath: withRequest, output: "xml"}, wantErr: "unsupported output format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.filePath = "../../example-petstore.yaml"
+
+			var stdout, stderr bytes.Buffer
+			err := runCollection(context.Background(), tt.opts, tt.names, &stdout, &stderr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("runCollection() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunCollectionDefaultPath(t *testing.T) {
+	server := newCallTestServer(t)
+
+	dir := t.TempDir()
+	data, err := os.ReadFile("../../example-petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	specPath := filepath.Join(dir, "petstore.yaml")
+	if err := os.WriteFile(specPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &collection.Collection{Requests: []collection.Request{{Name: "get pet", Operation: "getPetById", Params: map[string]string{"petId": "10"}, Headers: map[string]string{"X-Trace": "abc"}}}}
+	if err := c.Save(filepath.Join(dir, "petstore.collection.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	opts := collectionRunOptions{filePath: specPath, server: server.URL, output: outputText}
+	if err := runCollection(context.Background(), opts, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("runCollection() error = %v\n%s", err, stdout.String())
+	}
+}
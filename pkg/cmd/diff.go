@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ksysoev/tapi/pkg/diff"
+)
+
+const outputMarkdown = "markdown"
+
+// runDiff compares two versions of a spec, given as files or URLs, and fails
+// when the new one breaks clients of the old one.
+func runDiff(base, revision, output string, stdout io.Writer) error {
+	switch output {
+	case outputText, outputMarkdown, outputJSON:
+	default:
+		return fmt.Errorf("unsupported output format %q, use text, markdown or json", output)
+	}
+
+	baseSpec, err := loadSpec(specSource(base))
+	if err != nil {
+		return err
+	}
+
+	revisionSpec, err := loadSpec(specSource(revision))
+	if err != nil {
+		return err
+	}
+
+	changes := diff.Compare(baseSpec, revisionSpec)
+
+	if err := writeChanges(changes, output, stdout); err != nil {
+		return err
+	}
+
+	if n := diff.Breaking(changes); n > 0 {
+		return fmt.Errorf("%d breaking changes", n)
+	}
+
+	return nil
+}
+
+// specSource tells a URL from a file path, for commands that take specs as
+// arguments rather than --file and --url.
+func specSource(ref string) (string, string) {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return "", ref
+	}
+
+	return ref, ""
+}
+
+func writeChanges(changes []diff.Change, output string, stdout io.Writer) error {
+	breaking := diff.Breaking(changes)
+
+	switch output {
+	case outputJSON:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		if changes == nil {
+			changes = []diff.Change{}
+		}
+
+		return enc.Encode(struct {
+			Breaking    int           `json:"breaking"`
+			NonBreaking int           `json:"nonBreaking"`
+			Changes     []diff.Change `json:"changes"`
+		}{breaking, len(changes) - breaking, changes})
+	case outputMarkdown:
+		return writeChangesMarkdown(changes, stdout)
+	}
+
+	for _, c := range changes {
+		mark := "•"
+		if c.Breaking {
+			mark = "✗"
+		}
+
+		_, _ = fmt.Fprintf(stdout, "%s %s: %s\n", mark, changeSubject(c), c.Message)
+	}
+
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(stdout, "No changes")
+		return err
+	}
+
+	_, err := fmt.Fprintf(stdout, "\n%d breaking, %d non-breaking changes\n", breaking, len(changes)-breaking)
+
+	return err
+}
+
+func changeSubject(c diff.Change) string {
+	return strings.Join(strings.Fields(strings.Join([]string{c.Operation, c.Location, c.Path}, " ")), " ")
+}
+
+// writeChangesMarkdown writes the changes as tables for a pull request
+// comment, breaking ones first.
+func writeChangesMarkdown(changes []diff.Change, stdout io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("## API changes\n\n")
+
+	if len(changes) == 0 {
+		b.WriteString("No changes.\n")
+	}
+
+	for _, section := range []struct {
+		title    string
+		breaking bool
+	}{
+		{"Breaking changes", true},
+		{"Non-breaking changes", false},
+	} {
+		var rows []diff.Change
+		for _, c := range changes {
+			if c.Breaking == section.breaking {
+				rows = append(rows, c)
+			}
+		}
+
+		if len(rows) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "### %s (%d)\n\n", section.title, len(rows))
+		b.WriteString("| Operation | Location | Change |\n")
+		b.WriteString("| --- | --- | --- |\n")
+
+		for _, c := range rows {
+			location := c.Location
+			if c.Path != "" {
+				location += " `" + c.Path + "`"
+			}
+
+			fmt.Fprintf(&b, "| `%s` | %s | %s |\n", c.Operation, markdownCell(location), markdownCell(c.Message))
+		}
+
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(stdout, b.String())
+
+	return err
+}
+
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
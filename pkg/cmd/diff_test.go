@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeDiffSpec(t *testing.T, name, required string) string {
+	t.Helper()
+
+	data := `openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          required: ` + required + `
+          schema: {type: integer}
+      responses:
+        '200': {description: ok}
+`
+
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func TestRunDiff(t *testing.T) {
+	optional := writeDiffSpec(t, "old.yaml", "false")
+	required := writeDiffSpec(t, "new.yaml", "true")
+
+	tests := []struct {
+		name     string
+		base     string
+		revision string
+		output   string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name:     "breaking text",
+			base:     optional,
+			revision: required,
+			output:   outputText,
+			want:     []string{"✗ GET /pets query parameter limit: became required", "1 breaking, 0 non-breaking changes"},
+			wantErr:  "1 breaking changes",
+		},
+		{
+			name:     "non-breaking markdown",
+			base:     required,
+			revision: optional,
+			output:   outputMarkdown,
+			want:     []string{"### Non-breaking changes (1)", "| `GET /pets` | query parameter limit | became optional |"},
+		},
+		{
+			name:     "no changes",
+			base:     optional,
+			revision: optional,
+			output:   outputText,
+			want:     []string{"No changes"},
+		},
+		{
+			name:     "unsupported output",
+			base:     optional,
+			revision: required,
+			output:   "html",
+			wantErr:  `unsupported output format "html"`,
+		},
+		{
+			name:     "missing file",
+			base:     optional,
+			revision: filepath.Join(t.TempDir(), "missing.yaml"),
+			output:   outputText,
+			wantErr:  "failed to load OpenAPI spec",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			err := runDiff(tt.base, tt.revision, tt.output, &stdout)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("runDiff() error = %v, want %q", err, tt.wantErr)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunDiffJSON(t *testing.T) {
+	var stdout bytes.Buffer
+
+	_ = runDiff(writeDiffSpec(t, "old.yaml", "false"), writeDiffSpec(t, "new.yaml", "true"), outputJSON, &stdout)
+
+	var report struct {
+		Breaking    int `json:"breaking"`
+		NonBreaking int `json:"nonBreaking"`
+		Changes     []struct {
+			Operation string `json:"operation"`
+			Location  string `json:"location"`
+			Kind      string `json:"kind"`
+			Breaking  bool   `json:"breaking"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("output isn't JSON: %v\n%s", err, stdout.String())
+	}
+
+	if report.Breaking != 1 || report.NonBreaking != 0 || len(report.Changes) != 1 || report.Changes[0].Location != "query parameter limit" || report.Changes[0].Kind != "changed" || !report.Changes[0].Breaking {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestSpecSource(t *testing.T) {
+	if file, url := specSource("https://example.com/openapi.yaml"); file != "" || url != "https://example.com/openapi.yaml" {
+		t.Errorf("specSource(url) = %q, %q", file, url)
+	}
+
+	if file, url := specSource("./openapi.yaml"); file != "./openapi.yaml" || url != "" {
+		t.Errorf("specSource(file) = %q, %q", file, url)
+	}
+}
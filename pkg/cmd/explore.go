@@ -5,25 +5,69 @@ import (
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/pkg/collection"
+	"github.com/ksysoev/tapi/pkg/environment"
+	"github.com/ksysoev/tapi/pkg/history"
 	"github.com/ksysoev/tapi/pkg/openapi"
 	"github.com/ksysoev/tapi/pkg/tui"
 )
 
-func runExplore(ctx context.Context, filePath, url string) error {
-	var spec *openapi.Spec
-	var err error
+type exploreOptions struct {
+	filePath       string
+	url            string
+	server         string
+	env            string
+	collectionPath string
+	mock           bool
+}
 
-	if filePath != "" {
-		spec, err = openapi.LoadFromFile(filePath)
-	} else {
-		spec, err = openapi.LoadFromURL(url)
+func runExplore(ctx context.Context, opts exploreOptions) error {
+	spec, err := loadSpec(opts.filePath, opts.url)
+	if err != nil {
+		return err
 	}
 
+	cfg, err := environment.LoadDefault()
 	if err != nil {
-		return fmt.Errorf("failed to load OpenAPI spec: %w", err)
+		return err
 	}
 
-	model := tui.NewModel(spec)
+	if opts.env != "" {
+		if _, err := cfg.Get(opts.env); err != nil {
+			return err
+		}
+	}
+
+	store, err := history.OpenDefault()
+	if err != nil {
+		return err
+	}
+
+	collectionPath := opts.collectionPath
+	if collectionPath == "" {
+		if collectionPath, err = collection.DefaultPath(opts.filePath, spec.Title); err != nil {
+			return err
+		}
+	}
+
+	tuiOpts := []tui.Option{
+		tui.WithEnvironments(cfg, opts.env),
+		tui.WithHistory(store),
+		tui.WithCollection(collectionPath),
+	}
+	if opts.mock {
+		if opts.server != "" {
+			return fmt.Errorf("only one of --server or --mock can be specified")
+		}
+		if opts.server, err = startMock(ctx, spec); err != nil {
+			return err
+		}
+	}
+	if opts.server != "" {
+		tuiOpts = append(tuiOpts, tui.WithServer(opts.server))
+	}
+
+	model := tui.NewModel(spec, tuiOpts...)
 	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
 
 	if _, err := p.Run(); err != nil {
@@ -32,3 +76,20 @@ func runExplore(ctx context.Context, filePath, url string) error {
 
 	return nil
 }
+
+func loadSpec(filePath, url string) (*openapi.Spec, error) {
+	var spec *openapi.Spec
+	var err error
+
+	if filePath != "" {
+		spec, err = openapi.LoadFromFile(filePath)
+	} else {
+		spec, err = openapi.LoadFromURL(url)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ksysoev/tapi/pkg/lint"
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+type lintOptions struct {
+	filePath string
+	url      string
+	config   string
+	output   string
+}
+
+// runLint checks a spec against the lint rules and fails when any issue is
+// an error.
+func runLint(opts lintOptions, stdout io.Writer) error {
+	switch opts.output {
+	case outputText, outputJSON:
+	default:
+		return fmt.Errorf("unsupported output format %q, use text or json", opts.output)
+	}
+
+	cfg, err := lint.LoadConfig(opts.config)
+	if err != nil {
+		return err
+	}
+
+	data, name, err := readSpec(opts.filePath, opts.url)
+	if err != nil {
+		return err
+	}
+
+	source, err := openapi.ParseSource(data)
+	if err != nil {
+		return fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+
+	// Bad examples are reported by a rule, with their location.
+	spec, err := openapi.LoadFromData(data, openapi.WithoutExampleValidation())
+	if err != nil {
+		return fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+
+	issues := lint.Lint(spec, source, cfg)
+
+	if err := writeIssues(issues, name, opts.output, stdout); err != nil {
+		return err
+	}
+
+	if n := lint.Count(issues)[lint.Error]; n > 0 {
+		return fmt.Errorf("%d lint errors", n)
+	}
+
+	return nil
+}
+
+// readSpec returns the contents of a spec file or URL with the name to show
+// in locations.
+func readSpec(filePath, url string) ([]byte, string, error) {
+	if filePath != "" {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read file: %w", err)
+		}
+		return data, filePath, nil
+	}
+
+	data, _, err := openapi.Fetch(url)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return data, url, nil
+}
+
+func writeIssues(issues []lint.Issue, name, output string, stdout io.Writer) error {
+	counts := lint.Count(issues)
+
+	if output == outputJSON {
+		type fileIssue struct {
+			File string `json:"file"`
+			lint.Issue
+		}
+
+		report := struct {
+			Errors   int         `json:"errors"`
+			Warnings int         `json:"warnings"`
+			Infos    int         `json:"infos"`
+			Issues   []fileIssue `json:"issues"`
+		}{counts[lint.Error], counts[lint.Warning], counts[lint.Info], []fileIssue{}}
+
+		for _, issue := range issues {
+			report.Issues = append(report.Issues, fileIssue{File: name, Issue: issue})
+		}
+
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(report)
+	}
+
+	for _, issue := range issues {
+		_, _ = fmt.Fprintf(stdout, "%s:%s\n", name, issue)
+	}
+
+	if len(issues) == 0 {
+		_, err := fmt.Fprintln(stdout, "No issues")
+		return err
+	}
+
+	_, err := fmt.Fprintf(stdout, "\n%d errors, %d warnings, %d info\n", counts[lint.Error], counts[lint.Warning], counts[lint.Info])
+
+	return err
+}
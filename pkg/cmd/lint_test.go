@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const lintCmdSpec = `openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      parameters:
+        - name: limit
+          in: query
+          description: Page size
+          schema: {type: integer}
+          example: ten
+      responses:
+        '200': {description: ok}
+        '400': {description: invalid}
+`
+
+func TestRunLint(t *testing.T) {
+	dir := t.TempDir()
+
+	specFile := filepath.Join(dir, "spec.yaml")
+	if err := os.WriteFile(specFile, []byte(lintCmdSpec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	relaxed := filepath.Join(dir, "lint.yaml")
+	if err := os.WriteFile(relaxed, []byte("rules:\n  example-schema: warning\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    lintOptions
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "errors fail",
+			opts:    lintOptions{filePath: specFile, output: outputText},
+			want:    []string{specFile + ":13:11: error example-schema: example doesn't match the schema: $: value must be an integer", "1 errors, 0 warnings, 0 info"},
+			wantErr: "1 lint errors",
+		},
+		{
+			name: "config lowers severity",
+			opts: lintOptions{filePath: specFile, config: relaxed, output: outputText},
+			want: []string{"warning example-schema", "0 errors, 1 warnings, 0 info"},
+		},
+		{
+			name:    "unsupported output",
+			opts:    lintOptions{filePath: specFile, output: "sarif"},
+			wantErr: `unsupported output format "sarif"`,
+		},
+		{
+			name:    "missing config",
+			opts:    lintOptions{filePath: specFile, config: filepath.Join(dir, "missing.yaml"), output: outputText},
+			wantErr: "failed to read lint config",
+		},
+		{
+			name:    "missing spec",
+			opts:    lintOptions{filePath: filepath.Join(dir, "missing.yaml"), output: outputText},
+			wantErr: "failed to read file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			err := runLint(tt.opts, &stdout)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("runLint() error = %v, want %q", err, tt.wantErr)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunLintJSON(t *testing.T) {
+	specFile := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(specFile, []byte(lintCmdSpec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+
+	_ = runLint(lintOptions{filePath: specFile, output: outputJSON}, &stdout)
+
+	var report struct {
+		Errors int `json:"errors"`
+		Issues []struct {
+			File     string `json:"file"`
+			Rule     string `json:"rule"`
+			Severity string `json:"severity"`
+			Pointer  string `json:"pointer"`
+			Line     int    `json:"line"`
+			Column   int    `json:"column"`
+		} `json:"issues"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("output isn't JSON: %v\n%s", err, stdout.String())
+	}
+
+	if report.Errors != 1 || len(report.Issues) != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	issue := report.Issues[0]
+	if issue.File != specFile || issue.Rule != "example-schema" || issue.Severity != "error" || issue.Pointer != "/paths/~1pets/get/parameters/0/example" || issue.Line != 13 || issue.Column != 11 {
+		t.Errorf("issue = %+v", issue)
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ksysoev/tapi/pkg/mock"
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+type mockOptions struct {
+	filePath string
+	url      string
+	host     string
+	port     int
+	quiet    bool
+}
+
+func runMock(ctx context.Context, opts mockOptions, stdout io.Writer) error {
+	spec, err := loadSpec(opts.filePath, opts.url)
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(opts.host, strconv.Itoa(opts.port)))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	var handlerOpts []mock.Option
+	if !opts.quiet {
+		handlerOpts = append(handlerOpts, mock.WithLog(stdout))
+	}
+
+	_, _ = fmt.Fprintf(stdout, "Mocking %s v%s on http://%s, %d operations\n", spec.Title, spec.Version, ln.Addr(), countOperations(spec))
+
+	return serveMock(ctx, ln, mock.New(spec, handlerOpts...))
+}
+
+// serveMock serves handler on ln until ctx is done.
+func serveMock(ctx context.Context, ln net.Listener, handler http.Handler) error {
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve mock: %w", err)
+	}
+
+	return nil
+}
+
+// startMock serves the mock of spec on a free local port in the background,
+// for the TUI to send its requests to. It returns the mock's base URL.
+func startMock(ctx context.Context, spec *openapi.Spec) (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("failed to start mock: %w", err)
+	}
+
+	go func() {
+		_ = serveMock(ctx, ln, mock.New(spec))
+	}()
+
+	return "http://" + ln.Addr().String(), nil
+}
+
+func countOperations(spec *openapi.Spec) int {
+	n := 0
+	for _, p := range spec.Paths {
+		n += len(p.Operations)
+	}
+
+	return n
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+func TestStartMock(t *testing.T) {
+	spec, err := openapi.LoadFromFile("../../example-petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	baseURL, err := startMock(ctx, spec)
+	if err != nil {
+		t.Fatalf("startMock() error = %v", err)
+	}
+
+	resp, err := http.Get(baseURL + "/pet/10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"name"`) {
+		t.Errorf("GET /pet/10 = %d %s, want an example pet", resp.StatusCode, body)
+	}
+
+	resp, err = http.Get(baseURL + "/pet/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("GET /pet/abc = %d, want 400 for an invalid petId", resp.StatusCode)
+	}
+}
+
+func TestMockCommandFlags(t *testing.T) {
+	rootCmd := InitCommand(BuildInfo{Version: "1.0.0", AppName: "tapi"})
+
+	mockCmd, _, err := rootCmd.Find([]string{"mock"})
+	if err != nil {
+		t.Fatalf("Failed to find mock command: %v", err)
+	}
+
+	for _, flagName := range []string{"file", "url", "host", "port", "quiet"} {
+		if mockCmd.Flags().Lookup(flagName) == nil {
+			t.Errorf("Expected flag '%s' to exist", flagName)
+		}
+	}
+
+	if err := mockCmd.RunE(mockCmd, nil); err == nil || !strings.Contains(err.Error(), "--file or --url") {
+		t.Errorf("RunE() without a spec error = %v", err)
+	}
+}
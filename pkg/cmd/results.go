@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// resultLine is one request of a run, as shown in the text report of
+// "collection run" and "test".
+type resultLine struct {
+	passed bool
+	name   string
+	// target is where the request went, like the URL.
+	target string
+	// outcome is the status, or the error when there is no response.
+	outcome    string
+	durationMs int64
+	// details are listed under the line, like the ways a response breaks
+	// the spec.
+	details []string
+}
+
+// writeResultLines prints one aligned line per request, followed by the
+// number of passed and failed ones.
+func writeResultLines(lines []resultLine, stdout io.Writer) error {
+	w := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+
+	passed := 0
+	for _, l := range lines {
+		mark := "✗"
+		if l.passed {
+			mark = "✓"
+			passed++
+		}
+
+		_, _ = fmt.Fprintf(w, "%s %s\t%s\t%s\t%dms\n", mark, l.name, l.target, l.outcome, l.durationMs)
+		for _, detail := range l.details {
+			_, _ = fmt.Fprintf(w, "    %s\n", detail)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(stdout, "\n%d passed, %d failed\n", passed, len(lines)-passed)
+
+	return err
+}
+
+// outcome is the status of a response, or the error when there is none.
+func outcome(status, err string) string {
+	if err != "" {
+		return err
+	}
+
+	return status
+}
@@ -2,7 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/ksysoev/tapi/pkg/lint"
+	"github.com/ksysoev/tapi/pkg/snippet"
 	"github.com/spf13/cobra"
 )
 
@@ -21,55 +24,350 @@ func InitCommand(info BuildInfo) *cobra.Command {
 
 	rootCmd.AddCommand(newExploreCommand())
 	rootCmd.AddCommand(newValidateCommand())
+	rootCmd.AddCommand(newCallCommand())
+	rootCmd.AddCommand(newCollectionCommand())
+	rootCmd.AddCommand(newSnippetCommand())
+	rootCmd.AddCommand(newMockCommand())
+	rootCmd.AddCommand(newTestCommand())
+	rootCmd.AddCommand(newDiffCommand())
+	rootCmd.AddCommand(newLintCommand())
 
 	return rootCmd
 }
 
 func newExploreCommand() *cobra.Command {
-	var (
-		filePath string
-		url      string
-	)
+	opts := exploreOptions{}
 
 	cmd := &cobra.Command{
 		Use:   "explore",
 		Short: "Explore OpenAPI specification in interactive TUI",
 		Long:  `Launch an interactive terminal UI to browse and test API endpoints defined in an OpenAPI specification.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if filePath == "" && url == "" {
-				return fmt.Errorf("either --file or --url must be specified")
+			if err := checkSpecFlags(opts.filePath, opts.url); err != nil {
+				return err
 			}
-			if filePath != "" && url != "" {
-				return fmt.Errorf("only one of --file or --url can be specified")
+
+			return runExplore(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.filePath, "file", "f", "", "Path to local OpenAPI specification file")
+	cmd.Flags().StringVarP(&opts.url, "url", "u", "", "URL to remote OpenAPI specification")
+	cmd.Flags().StringVar(&opts.server, "server", "", "Base URL to send requests to instead of the servers in the spec")
+	cmd.Flags().StringVarP(&opts.env, "env", "e", "", "Environment from the config file to start with")
+	cmd.Flags().StringVar(&opts.collectionPath, "collection", "", "Collection file for saved requests, defaults to <spec>.collection.yaml next to the spec")
+	cmd.Flags().BoolVar(&opts.mock, "mock", false, "Send requests to a mock server of the spec started in the background")
+
+	return cmd
+}
+
+func newMockCommand() *cobra.Command {
+	opts := mockOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "mock",
+		Short: "Serve a mock of the API from its OpenAPI specification",
+		Long: `Serve every operation of an OpenAPI specification on a local port, as a
+stand-in for a backend that doesn't exist yet. Requests are routed by path
+template under any base path and checked against the spec, invalid ones get a
+400 listing what's wrong. Responses use the declared examples, or bodies
+generated from the schema, of the first success status. Clients pick another
+response with the Prefer header, e.g. "Prefer: code=404, example=notFound".`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkSpecFlags(opts.filePath, opts.url); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			return runMock(cmd.Context(), opts, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.filePath, "file", "f", "", "Path to local OpenAPI specification file")
+	cmd.Flags().StringVarP(&opts.url, "url", "u", "", "URL to remote OpenAPI specification")
+	cmd.Flags().StringVar(&opts.host, "host", "127.0.0.1", "Address to listen on")
+	cmd.Flags().IntVarP(&opts.port, "port", "p", 4010, "Port to listen on")
+	cmd.Flags().BoolVarP(&opts.quiet, "quiet", "q", false, "Don't log requests")
+
+	return cmd
+}
+
+func newTestCommand() *cobra.Command {
+	opts := testOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Contract test a running service against its OpenAPI specification",
+		Long: `Call every operation of an OpenAPI specification, or those selected with
+--tag and --operation, with the required parameters and body taken from the
+declared examples or generated from the schemas. Each response must have a
+declared status, Content-Type and headers and a body matching the schema;
+server errors always fail. Credentials for secured operations are given with
+--auth, e.g. --auth bearer=TOKEN. The command exits with a non-zero status
+when any operation fails.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkSpecFlags(opts.filePath, opts.url); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			return runTests(cmd.Context(), opts, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.filePath, "file", "f", "", "Path to local OpenAPI specification file")
+	cmd.Flags().StringVarP(&opts.url, "url", "u", "", "URL to remote OpenAPI specification")
+	cmd.Flags().StringVar(&opts.server, "server", "", "Base URL of the service, defaults to the first server in the spec")
+	cmd.Flags().StringArrayVar(&opts.serverVars, "server-var", nil, "Server variable as name=value, can be repeated")
+	cmd.Flags().StringVarP(&opts.env, "env", "e", "", "Environment from the config file to take {{variables}} from")
+	cmd.Flags().StringArrayVarP(&opts.tags, "tag", "t", nil, "Only test operations with this tag, can be repeated")
+	cmd.Flags().StringArrayVar(&opts.operations, "operation", nil, "Only test this operation, by operationId or \"METHOD /path\", can be repeated")
+	cmd.Flags().StringArrayVarP(&opts.headers, "header", "H", nil, "Extra header for every request as \"Name: value\", can be repeated")
+	cmd.Flags().StringArrayVarP(&opts.auth, "auth", "a", nil, "Credential for a security scheme as scheme=value, or scheme.field=value, e.g. basic=user:pass or oauth.client_id=app, can be repeated")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", outputText, "Output format: text, json or junit")
+
+	return cmd
+}
+
+func newDiffCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "diff <old spec> <new spec>",
+		Short: "Compare two versions of an OpenAPI specification",
+		Long: `Compare two versions of an OpenAPI specification, each a file or URL, and
+list the operations, parameters, request bodies and response schemas that were
+added, removed or changed. Changes that break existing clients, like a new
+required parameter, a removed enum value or a removed response property, are
+marked as breaking and make the command exit with a non-zero status.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			return runDiff(args[0], args[1], output, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", outputText, "Output format: text, markdown or json")
+
+	return cmd
+}
+
+func newLintCommand() *cobra.Command {
+	opts := lintOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check an OpenAPI specification against style rules",
+		Long: `Check an OpenAPI specification against style rules beyond validity: missing
+operationIds and descriptions, paths that aren't kebab-case, unused components,
+operations without 4xx responses, undeclared or undocumented security, and
+examples that don't match their schemas. Each issue is reported at its file
+and line.
+
+Rules are set to error, warning, info or off in a config file, .tapi-lint.yaml
+in the working directory unless --config is given:
+
+  rules:
+    path-kebab-case: error
+    parameter-description: off
+
+The command exits with a non-zero status when any issue is an error.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkSpecFlags(opts.filePath, opts.url); err != nil {
+				return err
 			}
 
-			return runExplore(cmd.Context(), filePath, url)
+			cmd.SilenceUsage = true
+
+			return runLint(opts, cmd.OutOrStdout())
 		},
 	}
 
-	cmd.Flags().StringVarP(&filePath, "file", "f", "", "Path to local OpenAPI specification file")
-	cmd.Flags().StringVarP(&url, "url", "u", "", "URL to remote OpenAPI specification")
+	cmd.Flags().StringVarP(&opts.filePath, "file", "f", "", "Path to local OpenAPI specification file")
+	cmd.Flags().StringVarP(&opts.url, "url", "u", "", "URL to remote OpenAPI specification")
+	cmd.Flags().StringVarP(&opts.config, "config", "c", "", "Lint config file, defaults to "+lint.ConfigFile+" in the working directory")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", outputText, "Output format: text or json")
 
 	return cmd
 }
 
 func newValidateCommand() *cobra.Command {
-	var filePath string
+	opts := validateOptions{}
 
 	cmd := &cobra.Command{
-		Use:   "validate",
+		Use:   "validate [file...]",
 		Short: "Validate OpenAPI specification",
-		Long:  `Validate an OpenAPI specification file for correctness.`,
+		Long: `Validate OpenAPI specification files and URLs for correctness. Every problem
+is reported with its line and column in the YAML or JSON source, as text, a
+JSON report, or SARIF for editors and CI annotations.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.files = append(opts.files, args...)
+
+			if len(opts.files) == 0 && len(opts.urls) == 0 {
+				return errNoSpecs
+			}
+
+			cmd.SilenceUsage = true
+
+			return runValidate(opts, cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&opts.files, "file", "f", nil, "Path to local OpenAPI specification file, can be repeated")
+	cmd.Flags().StringArrayVarP(&opts.urls, "url", "u", nil, "URL to remote OpenAPI specification, can be repeated")
+	cmd.Flags().StringVar(&opts.format, "format", outputText, "Output format: text, json or sarif")
+
+	return cmd
+}
+
+func newCallCommand() *cobra.Command {
+	opts := callOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "call <operationId | METHOD /path>",
+		Short: "Send a single API request and print the response",
+		Long: `Send a request to an operation from an OpenAPI specification without the TUI.
+The operation is selected by its operationId or by method and path template,
+e.g. "GET /pets/{petId}", or taken from a curl command with --from-curl, which
+also fills in its parameters, headers and body. Credentials for the
+operation's security schemes are given with --auth, e.g. --auth bearer=TOKEN.
+The command exits with a non-zero status when the response has an HTTP error
+status.`,
+		Args: operationArgs(&opts),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkSpecFlags(opts.filePath, opts.url); err != nil {
+				return err
+			}
+
+			opts.operation = strings.Join(args, " ")
+			cmd.SilenceUsage = true
+
+			return runCall(cmd.Context(), opts, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+
+	addRequestFlags(cmd, &opts)
+	cmd.Flags().StringVarP(&opts.output, "output", "o", outputPretty, "Output format: pretty, raw or json")
+
+	return cmd
+}
+
+func newSnippetCommand() *cobra.Command {
+	opts := callOptions{}
+
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "snippet <operationId | METHOD /path>",
+		Short: "Print the request an operation would send as curl, HTTPie or code",
+		Long: `Build a request exactly like "call" does, with the same flags, and print it
+instead of sending it: as a curl or HTTPie command, or as Go (net/http),
+Python (requests) or JavaScript (fetch) code.`,
+		Args: operationArgs(&opts),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkSpecFlags(opts.filePath, opts.url); err != nil {
+				return err
+			}
+
+			opts.operation = strings.Join(args, " ")
+			cmd.SilenceUsage = true
+
+			return runSnippet(opts, format, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+
+	addRequestFlags(cmd, &opts)
+	cmd.Flags().StringVarP(&format, "format", "F", snippet.FormatCurl, "Snippet format: "+strings.Join(snippet.Formats, ", "))
+
+	return cmd
+}
+
+// addRequestFlags adds the flags that describe a single request, shared by
+// call and snippet.
+func addRequestFlags(cmd *cobra.Command, opts *callOptions) {
+	cmd.Flags().StringVarP(&opts.filePath, "file", "f", "", "Path to local OpenAPI specification file")
+	cmd.Flags().StringVarP(&opts.url, "url", "u", "", "URL to remote OpenAPI specification")
+	cmd.Flags().StringVar(&opts.server, "server", "", "Base URL to send the request to, defaults to the first server in the spec")
+	cmd.Flags().StringArrayVar(&opts.serverVars, "server-var", nil, "Server variable as name=value, can be repeated")
+	cmd.Flags().StringVarP(&opts.env, "env", "e", "", "Environment from the config file to take {{variables}} from")
+	cmd.Flags().StringArrayVarP(&opts.params, "param", "p", nil, "Parameter as name=value, can be repeated")
+	cmd.Flags().StringArrayVarP(&opts.headers, "header", "H", nil, "Extra header as \"Name: value\", can be repeated")
+	cmd.Flags().StringVarP(&opts.data, "data", "d", "", "Request body, @file to read it from a file or @- to read stdin")
+	cmd.Flags().StringVar(&opts.contentType, "content-type", "", "Media type of the body, defaults to the first one the operation declares")
+	cmd.Flags().StringVar(&opts.fromCurl, "from-curl", "", "Take the operation, parameters and body from a curl command, @file or @- to read it")
+	cmd.Flags().StringArrayVarP(&opts.auth, "auth", "a", nil, "Credential for a security scheme as scheme=value, or scheme.field=value, e.g. basic=user:pass or oauth.client_id=app, can be repeated")
+}
+
+// checkSpecFlags makes sure the spec is given with exactly one of --file and
+// --url.
+func checkSpecFlags(filePath, url string) error {
+	if filePath == "" && url == "" {
+		return fmt.Errorf("either --file or --url must be specified")
+	}
+	if filePath != "" && url != "" {
+		return fmt.Errorf("only one of --file or --url can be specified")
+	}
+
+	return nil
+}
+
+// operationArgs expects the operation as arguments, unless it comes from a
+// curl command.
+func operationArgs(opts *callOptions) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if opts.fromCurl == "" {
+			return cobra.RangeArgs(1, 2)(cmd, args)
+		}
+		if len(args) > 0 {
+			return fmt.Errorf("the operation is taken from --from-curl, don't pass it as well")
+		}
+		return nil
+	}
+}
+
+func newCollectionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "collection",
+		Short: "Work with collections of saved requests",
+	}
+
+	cmd.AddCommand(newCollectionRunCommand())
+
+	return cmd
+}
+
+func newCollectionRunCommand() *cobra.Command {
+	opts := collectionRunOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "run [request names...]",
+		Short: "Send the saved requests of a collection and report the results",
+		Long: `Send every request saved in a collection, or only the named ones, in order.
+The command exits with a non-zero status when any request fails or gets an
+HTTP error status.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if filePath == "" {
-				return fmt.Errorf("--file must be specified")
+			if err := checkSpecFlags(opts.filePath, opts.url); err != nil {
+				return err
 			}
-			return runValidate(filePath)
+
+			cmd.SilenceUsage = true
+
+			return runCollection(cmd.Context(), opts, args, cmd.OutOrStdout(), cmd.ErrOrStderr())
 		},
 	}
 
-	cmd.Flags().StringVarP(&filePath, "file", "f", "", "Path to OpenAPI specification file (required)")
-	_ = cmd.MarkFlagRequired("file")
+	cmd.Flags().StringVarP(&opts.filePath, "file", "f", "", "Path to local OpenAPI specification file")
+	cmd.Flags().StringVarP(&opts.url, "url", "u", "", "URL to remote OpenAPI specification")
+	cmd.Flags().StringVarP(&opts.collectionPath, "collection", "c", "", "Collection file, defaults to <spec>.collection.yaml next to the spec")
+	cmd.Flags().StringVar(&opts.server, "server", "", "Base URL to send the requests to, defaults to the first server in the spec")
+	cmd.Flags().StringArrayVar(&opts.serverVars, "server-var", nil, "Server variable as name=value, can be repeated")
+	cmd.Flags().StringVarP(&opts.env, "env", "e", "", "Environment from the config file to take {{variables}} from")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", outputText, "Output format: text or json")
 
 	return cmd
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"io"
 	"strings"
 	"testing"
 )
@@ -30,7 +31,7 @@ func TestInitCommand(t *testing.T) {
 		t.Error("Expected command to have subcommands")
 	}
 
-	expectedCommands := []string{"explore", "validate"}
+	expectedCommands := []string{"explore", "validate", "call", "collection", "snippet", "mock", "test", "diff", "lint"}
 	for _, cmdName := range expectedCommands {
 		if _, _, err := cmd.Find([]string{cmdName}); err != nil {
 			t.Errorf("Expected to find subcommand '%s'", cmdName)
@@ -150,7 +151,7 @@ func TestValidateCommandValidation(t *testing.T) {
 			name:    "no file specified",
 			args:    []string{"validate"},
 			wantErr: true,
-			errMsg:  "required flag(s)",
+			errMsg:  "either a file, --file or --url must be specified",
 		},
 		{
 			name:    "valid file specified",
@@ -162,6 +163,11 @@ func TestValidateCommandValidation(t *testing.T) {
 			args:    []string{"validate", "--file", "non-existent.yaml"},
 			wantErr: true,
 		},
+		{
+			name:    "files as arguments",
+			args:    []string{"validate", "../../example-petstore.yaml", "--file", "../../example-petstore.yaml"},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -222,7 +228,12 @@ func TestRunValidate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := runValidate(tt.filePath)
+			opts := validateOptions{format: outputText}
+			if tt.filePath != "" {
+				opts.files = []string{tt.filePath}
+			}
+
+			err := runValidate(opts, io.Discard)
 
 			if tt.wantErr && err == nil {
 				t.Error("Expected error but got none")
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// SARIF 2.1.0 is the static analysis format code hosts and editors read to
+// annotate files with problems. Only the parts tapi fills in are modelled.
+
+const sarifRuleID = "openapi-validation"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+func writeValidationSARIF(results []validation, stdout io.Writer) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "tapi",
+			InformationURI: "https://github.com/ksysoev/tapi",
+			Rules: []sarifRule{{
+				ID:               sarifRuleID,
+				ShortDescription: sarifMessage{Text: "The document is a valid OpenAPI specification"},
+			}},
+		}},
+		Results: []sarifResult{},
+	}
+
+	for _, r := range results {
+		for _, p := range r.Problems {
+			location := sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: r.File}}
+			if p.Line > 0 {
+				location.Region = &sarifRegion{StartLine: p.Line, StartColumn: p.Column}
+			}
+
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    sarifRuleID,
+				Level:     "error",
+				Message:   sarifMessage{Text: p.Message},
+				Locations: []sarifLocation{{PhysicalLocation: location}},
+			})
+		}
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
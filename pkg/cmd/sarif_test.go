@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+func TestWriteValidationSARIF(t *testing.T) {
+	results := []validation{
+		{File: "ok.yaml", Valid: true},
+		{File: "bad.yaml", Problems: []openapi.Problem{
+			{Message: "invalid example", Pointer: "/paths/~1pets", Location: openapi.Location{Line: 8, Column: 11}},
+			{Message: "failed to read file"},
+		}},
+	}
+
+	var stdout bytes.Buffer
+	if err := writeValidationSARIF(results, &stdout); err != nil {
+		t.Fatal(err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(stdout.Bytes(), &log); err != nil {
+		t.Fatalf("output isn't JSON: %v\n%s", err, stdout.String())
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || log.Runs[0].Tool.Driver.Name != "tapi" {
+		t.Fatalf("log = %+v", log)
+	}
+
+	got := log.Runs[0].Results
+	if len(got) != 2 {
+		t.Fatalf("results = %+v", got)
+	}
+
+	first := got[0]
+	if first.RuleID != sarifRuleID || first.Level != "error" || first.Message.Text != "invalid example" {
+		t.Errorf("result = %+v", first)
+	}
+
+	location := first.Locations[0].PhysicalLocation
+	if location.ArtifactLocation.URI != "bad.yaml" || location.Region == nil || location.Region.StartLine != 8 || location.Region.StartColumn != 11 {
+		t.Errorf("location = %+v", location)
+	}
+
+	if region := got[1].Locations[0].PhysicalLocation.Region; region != nil {
+		t.Errorf("region of a problem without a line = %+v, want none", region)
+	}
+}
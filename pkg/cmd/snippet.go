@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/ksysoev/tapi/pkg/snippet"
+)
+
+// runSnippet builds the request call would send and prints it in format
+// instead of sending it.
+func runSnippet(opts callOptions, format string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if !slices.Contains(snippet.Formats, format) {
+		return fmt.Errorf("unsupported snippet format %q, use %s", format, strings.Join(snippet.Formats, ", "))
+	}
+
+	spec, err := loadSpec(opts.filePath, opts.url)
+	if err != nil {
+		return err
+	}
+
+	vars, err := loadEnvironment(opts.env)
+	if err != nil {
+		return err
+	}
+
+	path, op, opts, err := resolveCallOperation(spec, opts, vars, stdin, stderr)
+	if err != nil {
+		return err
+	}
+
+	req, err := buildCallRequest(spec, path, op, opts, vars, stdin)
+	if err != nil {
+		return err
+	}
+
+	text, err := snippet.Render(format, req)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, strings.TrimSuffix(text, "\n"))
+
+	return err
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunSnippet(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    callOptions
+		format  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "curl with path param and header",
+			opts:   callOptions{operation: "getPetById", params: []string{"petId=10"}, headers: []string{"X-Trace: abc"}},
+			format: "curl",
+			want:   "curl 'http://example.com/pet/10' \\\n  -H 'Accept: application/json' \\\n  -H 'X-Trace: abc'\n",
+		},
+		{
+			name:   "httpie with body",
+			opts:   callOptions{operation: "addPet", data: `{"name":"rex"}`},
+			format: "httpie",
+			want:   `http --raw '{"name":"rex"}' POST 'http://example.com/pet'`,
+		},
+		{
+			name:   "go",
+			opts:   callOptions{operation: "getPetById", params: []string{"petId=10"}},
+			format: "go",
+			want:   `http.NewRequest("GET", "http://example.com/pet/10", nil)`,
+		},
+		{
+			name:    "unsupported format",
+			opts:    callOptions{operation: "getPetById", params: []string{"petId=10"}},
+			format:  "wget",
+			wantErr: `unsupported snippet format "wget"`,
+		},
+		{
+			name:    "missing required parameter",
+			opts:    callOptions{operation: "getPetById"},
+			format:  "curl",
+			wantErr: `missing required path parameter "petId"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.filePath = "../../example-petstore.yaml"
+			tt.opts.server = "http://example.com"
+
+			var stdout, stderr bytes.Buffer
+
+			err := runSnippet(tt.opts, tt.format, strings.NewReader(""), &stdout, &stderr)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("runSnippet() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("runSnippet() error = %v", err)
+			}
+
+			if !strings.Contains(stdout.String(), tt.want) {
+				t.Errorf("runSnippet() output =\n%s\nwant it to contain\n%s", stdout.String(), tt.want)
+			}
+		})
+	}
+}
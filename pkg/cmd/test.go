@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ksysoev/tapi/pkg/contract"
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+const outputJUnit = "junit"
+
+type testOptions struct {
+	filePath   string
+	url        string
+	server     string
+	serverVars []string
+	env        string
+	tags       []string
+	operations []string
+	headers    []string
+	auth       []string
+	output     string
+}
+
+// runTests calls the selected operations of the spec with example data and
+// checks every response against it. The command fails when any operation
+// couldn't be called or got a response that breaks the spec.
+func runTests(ctx context.Context, opts testOptions, stdout io.Writer) error {
+	switch opts.output {
+	case outputText, outputJSON, outputJUnit:
+	default:
+		return fmt.Errorf("unsupported output format %q, use text, json or junit", opts.output)
+	}
+
+	spec, err := loadSpec(opts.filePath, opts.url)
+	if err != nil {
+		return err
+	}
+
+	cases, err := contract.Select(spec, opts.tags, opts.operations)
+	if err != nil {
+		return err
+	}
+
+	vars, err := loadEnvironment(opts.env)
+	if err != nil {
+		return err
+	}
+
+	// Parsed once so an OAuth2 token is fetched once for the whole run.
+	creds, err := parseCredentials(spec, opts.auth, vars)
+	if err != nil {
+		return err
+	}
+
+	results := make([]contract.Result, 0, len(cases))
+	for _, c := range cases {
+		results = append(results, runTestCase(ctx, spec, c, opts, vars, creds))
+	}
+
+	if err := writeTestResults(spec, results, opts.output, stdout); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Passed {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d operations failed", failed, len(results))
+	}
+
+	return nil
+}
+
+func runTestCase(ctx context.Context, spec *openapi.Spec, c contract.Case, opts testOptions, vars map[string]string, creds map[string]request.Credential) contract.Result {
+	result := contract.Result{Operation: c.String(), OperationID: c.Operation.OperationID}
+
+	call := callOptions{
+		server:     opts.server,
+		serverVars: opts.serverVars,
+		params:     joinPairs(contract.ExampleParams(c.Operation), "="),
+		headers:    opts.headers,
+	}
+
+	req, err := buildCallRequest(spec, c.Path, c.Operation, call, vars, strings.NewReader(""))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if req.Body, req.ContentType, err = contract.ExampleBody(c.Operation); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	req.Credentials = request.ResolveCredentials(c.Operation.Security, creds)
+
+	resp := request.Do(ctx, req)
+
+	result.URL = req.URL()
+	result.DurationMs = resp.Duration.Milliseconds()
+
+	if resp.Error != nil {
+		result.Error = resp.Error.Error()
+		return result
+	}
+
+	result.StatusCode = resp.StatusCode
+	result.Status = resp.Status
+	result.Failures = contract.Check(spec, c.Path.Path, resp)
+	result.Passed = len(result.Failures) == 0
+
+	return result
+}
+
+func writeTestResults(spec *openapi.Spec, results []contract.Result, output string, stdout io.Writer) error {
+	switch output {
+	case outputJSON:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	case outputJUnit:
+		return contract.WriteJUnit(stdout, spec.Title, results)
+	}
+
+	lines := make([]resultLine, 0, len(results))
+	for _, r := range results {
+		lines = append(lines, resultLine{
+			passed:     r.Passed,
+			name:       r.Operation,
+			target:     r.URL,
+			outcome:    outcome(r.Status, r.Error),
+			durationMs: r.DurationMs,
+			details:    r.Failures,
+		})
+	}
+
+	return writeResultLines(lines, stdout)
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/contract"
+	"github.com/ksysoev/tapi/pkg/mock"
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+func TestRunTests(t *testing.T) {
+	spec, err := openapi.LoadFromFile("../../example-petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockServer := httptest.NewServer(mock.New(spec))
+	defer mockServer.Close()
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer abc" {
+			t.Errorf("Authorization = %q, want the extra header", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"ten"}`))
+	}))
+	defer broken.Close()
+
+	tests := []struct {
+		name    string
+		opts    testOptions
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "mock passes",
+			opts: testOptions{server: mockServer.URL, output: outputText},
+			want: []string{"✓ GET /pet/{petId}", "6 passed, 0 failed"},
+		},
+		{
+			name:    "broken service text",
+			opts:    testOptions{server: broken.URL, operations: []string{"getPetById"}, headers: []string{"Authorization: Bearer abc"}, output: outputText},
+			want:    []string{"✗ GET /pet/{petId}", broken.URL + "/pet/0", "    $.id: value must be an integer", "0 passed, 1 failed"},
+			wantErr: "1 of 1 operations failed",
+		},
+		{
+			name:    "broken service junit",
+			opts:    testOptions{server: broken.URL, tags: []string{"store"}, headers: []string{"Authorization: Bearer abc"}, output: outputJUnit},
+			want:    []string{`<testsuite name="Pet Store API" tests="1" failures="1"`, `<testcase name="getInventory (GET /store/inventory)"`},
+			wantErr: "1 of 1 operations failed",
+		},
+		{
+			name:    "unsupported output",
+			opts:    testOptions{output: "xml"},
+			wantErr: `unsupported output format "xml"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.filePath = "../../example-petstore.yaml"
+
+			var stdout bytes.Buffer
+
+			err := runTests(context.Background(), tt.opts, &stdout)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("runTests() error = %v, want %q", err, tt.wantErr)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunTestsJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	var stdout bytes.Buffer
+
+	opts := testOptions{filePath: "../../example-petstore.yaml", server: server.URL, operations: []string{"POST /user"}, output: outputJSON}
+	if err := runTests(context.Background(), opts, &stdout); err == nil {
+		t.Fatal("runTests() should fail on a server error")
+	}
+
+	var results []contract.Result
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		t.Fatalf("output isn't JSON: %v\n%s", err, stdout.String())
+	}
+
+	if len(results) != 1 || results[0].OperationID != "createUser" || results[0].StatusCode != 500 || results[0].Passed || results[0].Failures[0] != "server error 500 Internal Server Error" {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func TestRunTestsAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorized := r.Header.Get("Authorization") == "Bearer tok"
+		if r.URL.Path == "/items" {
+			authorized = r.URL.Query().Get("api_key") == "secret"
+		}
+		if !authorized {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	spec := writeSpec(t, "secured.yaml", securedSpec)
+
+	var out bytes.Buffer
+	opts := testOptions{filePath: spec, server: server.URL, auth: []string{"bearer=tok", "api_key=secret"}, output: outputText}
+	if err := runTests(context.Background(), opts, &out); err != nil {
+		t.Fatalf("runTests() error = %v\n%s", err, out.String())
+	}
+
+	opts.auth = []string{"jwt=tok"}
+	if err := runTests(context.Background(), opts, &out); err == nil || !strings.Contains(err.Error(), `unknown security scheme "jwt"`) {
+		t.Errorf("runTests() error = %v, want the unknown scheme", err)
+	}
+}
@@ -1,21 +1,153 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 
 	"github.com/ksysoev/tapi/pkg/openapi"
 )
 
-func runValidate(filePath string) error {
-	spec, err := openapi.LoadFromFile(filePath)
+const outputSARIF = "sarif"
+
+var errNoSpecs = errors.New("either a file, --file or --url must be specified")
+
+type validateOptions struct {
+	files  []string
+	urls   []string
+	format string
+}
+
+// validation is the outcome of validating one spec.
+type validation struct {
+	File     string            `json:"file"`
+	Valid    bool              `json:"valid"`
+	OpenAPI  string            `json:"openapi,omitempty"`
+	Title    string            `json:"title,omitempty"`
+	Version  string            `json:"version,omitempty"`
+	Problems []openapi.Problem `json:"problems"`
+	// operations is shown for valid specs.
+	operations int
+}
+
+// runValidate checks every spec, reporting all the problems of each with
+// their locations, and fails when any spec is invalid.
+func runValidate(opts validateOptions, stdout io.Writer) error {
+	switch opts.format {
+	case outputText, outputJSON, outputSARIF:
+	default:
+		return fmt.Errorf("unsupported format %q, use text, json or sarif", opts.format)
+	}
+
+	if len(opts.files) == 0 && len(opts.urls) == 0 {
+		return errNoSpecs
+	}
+
+	var results []validation
+
+	for _, file := range opts.files {
+		results = append(results, validateSpec(file, ""))
+	}
+	for _, url := range opts.urls {
+		results = append(results, validateSpec("", url))
+	}
+
+	var err error
+	switch opts.format {
+	case outputJSON:
+		err = writeValidationJSON(results, stdout)
+	case outputSARIF:
+		err = writeValidationSARIF(results, stdout)
+	default:
+		err = writeValidationText(results, stdout)
+	}
 	if err != nil {
-		return fmt.Errorf("validation failed: %w", err)
+		return err
 	}
 
-	fmt.Printf("✓ OpenAPI specification is valid\n")
-	fmt.Printf("  Title: %s\n", spec.Title)
-	fmt.Printf("  Version: %s\n", spec.Version)
-	fmt.Printf("  Endpoints: %d\n", len(spec.Paths))
+	invalid := 0
+	for _, r := range results {
+		if !r.Valid {
+			invalid++
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("validation failed: %d of %d specs are invalid", invalid, len(results))
+	}
 
 	return nil
 }
+
+func validateSpec(filePath, url string) validation {
+	name := filePath
+	if name == "" {
+		name = url
+	}
+
+	result := validation{File: name, Problems: []openapi.Problem{}}
+
+	data, _, err := readSpec(filePath, url)
+	if err != nil {
+		result.Problems = append(result.Problems, openapi.Problem{Message: err.Error()})
+		return result
+	}
+
+	if problems := openapi.ValidateDocument(data); len(problems) > 0 {
+		result.Problems = problems
+		return result
+	}
+
+	spec, err := openapi.LoadFromData(data)
+	if err != nil {
+		result.Problems = append(result.Problems, openapi.Problem{Message: err.Error()})
+		return result
+	}
+
+	result.Valid = true
+	result.OpenAPI = spec.OpenAPIVersion
+	result.Title = spec.Title
+	result.Version = spec.Version
+	result.operations = countOperations(spec)
+
+	return result
+}
+
+func writeValidationText(results []validation, stdout io.Writer) error {
+	for _, r := range results {
+		if r.Valid {
+			_, _ = fmt.Fprintf(stdout, "✓ %s: valid OpenAPI %s specification\n", r.File, r.OpenAPI)
+			_, _ = fmt.Fprintf(stdout, "  Title: %s\n", r.Title)
+			_, _ = fmt.Fprintf(stdout, "  Version: %s\n", r.Version)
+			_, _ = fmt.Fprintf(stdout, "  Operations: %d\n", r.operations)
+			continue
+		}
+
+		_, _ = fmt.Fprintf(stdout, "✗ %s: %d problems\n", r.File, len(r.Problems))
+		for _, p := range r.Problems {
+			if p.Line == 0 {
+				_, _ = fmt.Fprintf(stdout, "  %s: %s\n", r.File, p)
+				continue
+			}
+			_, _ = fmt.Fprintf(stdout, "  %s:%s\n", r.File, p)
+		}
+	}
+
+	return nil
+}
+
+func writeValidationJSON(results []validation, stdout io.Writer) error {
+	valid := true
+	for _, r := range results {
+		valid = valid && r.Valid
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(struct {
+		Valid bool         `json:"valid"`
+		Specs []validation `json:"specs"`
+	}{valid, results})
+}
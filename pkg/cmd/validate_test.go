@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const invalidSpec = `openapi: 3.0.3
+info:
+  title: Pets
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: integer}
+          example: ten
+      responses:
+        '200': {description: ok}
+`
+
+func writeSpec(t *testing.T, name, data string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func TestRunValidateFormats(t *testing.T) {
+	invalid := writeSpec(t, "invalid.yaml", invalidSpec)
+	valid := "../../example-petstore.yaml"
+
+	tests := []struct {
+		name    string
+		opts    validateOptions
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "valid text",
+			opts: validateOptions{files: []string{valid}, format: outputText},
+			want: []string{"✓ ../../example-petstore.yaml: valid OpenAPI 3.0.3 specification", "Title: Pet Store API", "Operations: 6"},
+		},
+		{
+			name: "every problem with its location",
+			opts: validateOptions{files: []string{valid, invalid}, format: outputText},
+			want: []string{
+				"✓ ../../example-petstore.yaml",
+				"✗ " + invalid + ": 2 problems",
+				invalid + ":2:1: value of version must be a non-empty string",
+				invalid + ":8:11: invalid example: value must be an integer",
+			},
+			wantErr: "validation failed: 1 of 2 specs are invalid",
+		},
+		{
+			name:    "unreadable file",
+			opts:    validateOptions{files: []string{"missing.yaml"}, format: outputText},
+			want:    []string{"✗ missing.yaml: 1 problems", "missing.yaml: failed to read file"},
+			wantErr: "validation failed",
+		},
+		{
+			name:    "unsupported format",
+			opts:    validateOptions{files: []string{valid}, format: "xml"},
+			wantErr: `unsupported format "xml"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+
+			err := runValidate(tt.opts, &stdout)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("runValidate() error = %v, want %q", err, tt.wantErr)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRunValidateJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(invalidSpec))
+	}))
+	defer server.Close()
+
+	var stdout bytes.Buffer
+
+	err := runValidate(validateOptions{files: []string{"../../example-petstore.yaml"}, urls: []string{server.URL + "/openapi.yaml"}, format: outputJSON}, &stdout)
+	if err == nil {
+		t.Fatal("Expected error for an invalid spec")
+	}
+
+	var report struct {
+		Valid bool `json:"valid"`
+		Specs []struct {
+			File     string `json:"file"`
+			Valid    bool   `json:"valid"`
+			Title    string `json:"title"`
+			Problems []struct {
+				Message string `json:"message"`
+				Pointer string `json:"pointer"`
+				Line    int    `json:"line"`
+				Column  int    `json:"column"`
+			} `json:"problems"`
+		} `json:"specs"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("output isn't JSON: %v\n%s", err, stdout.String())
+	}
+
+	if report.Valid || len(report.Specs) != 2 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	if spec := report.Specs[0]; !spec.Valid || spec.Title != "Pet Store API" || len(spec.Problems) != 0 {
+		t.Errorf("valid spec = %+v", spec)
+	}
+
+	spec := report.Specs[1]
+	if spec.Valid || spec.File != server.URL+"/openapi.yaml" || len(spec.Problems) != 2 {
+		t.Fatalf("invalid spec = %+v", spec)
+	}
+
+	if p := spec.Problems[1]; p.Pointer != "/paths/~1pets/get/parameters/0" || p.Line != 8 || p.Column != 11 {
+		t.Errorf("problem = %+v", p)
+	}
+}
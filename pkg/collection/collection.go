@@ -0,0 +1,134 @@
+// Package collection stores named requests for a spec so common scenarios
+// don't have to be typed in again. A collection is a YAML file kept next to
+// a local spec, or under the config directory for remote ones.
+package collection
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/oasdiff/yaml"
+)
+
+type Collection struct {
+	Requests []Request `json:"requests"`
+}
+
+// Request is a saved, filled-in request. Values may reference environment
+// variables as {{name}}. Params are keyed by parameter name, or by "in:name"
+// like "query:id" when the operation has parameters of the same name in
+// several locations.
+type Request struct {
+	Name string `json:"name"`
+	// Operation is an operationId or "METHOD /path".
+	Operation   string            `json:"operation"`
+	Params      map[string]string `json:"params,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        string            `json:"body,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+}
+
+// DefaultPath returns the collection file for a spec. A local spec keeps its
+// collection next to it, e.g. petstore.collection.yaml for petstore.yaml;
+// a remote spec uses tapi/collections/<title>.yaml under the config dir.
+func DefaultPath(specFile, specTitle string) (string, error) {
+	if specFile != "" {
+		base := strings.TrimSuffix(specFile, filepath.Ext(specFile))
+		return base + ".collection.yaml", nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+
+	return filepath.Join(dir, "tapi", "collections", slug(specTitle)+".yaml"), nil
+}
+
+func slug(s string) string {
+	var b strings.Builder
+	dash := false
+
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+
+	name := strings.TrimSuffix(b.String(), "-")
+	if name == "" {
+		return "default"
+	}
+
+	return name
+}
+
+// Load reads a collection file. A missing file is an empty collection.
+func Load(path string) (*Collection, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Collection{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read collection: %w", err)
+	}
+
+	var c Collection
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse collection %s: %w", path, err)
+	}
+
+	return &c, nil
+}
+
+// Save writes the collection to path, creating its directory when needed.
+func (c *Collection) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode collection: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create collection directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write collection: %w", err)
+	}
+
+	return nil
+}
+
+// Put adds r, replacing a saved request with the same name.
+func (c *Collection) Put(r Request) {
+	for i := range c.Requests {
+		if c.Requests[i].Name == r.Name {
+			c.Requests[i] = r
+			return
+		}
+	}
+
+	c.Requests = append(c.Requests, r)
+}
+
+// Find returns the saved request with the given name.
+func (c *Collection) Find(name string) (*Request, error) {
+	for i := range c.Requests {
+		if c.Requests[i].Name == name {
+			return &c.Requests[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("request %q not found in collection", name)
+}
@@ -0,0 +1,70 @@
+package collection
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDefaultPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	tests := []struct {
+		name      string
+		specFile  string
+		specTitle string
+		expected  string
+	}{
+		{name: "next to local spec", specFile: "specs/petstore.yaml", expected: "specs/petstore.collection.yaml"},
+		{name: "remote spec by title", specTitle: "Swagger Petstore - OpenAPI 3.0", expected: filepath.Join(dir, "tapi", "collections", "swagger-petstore-openapi-3-0.yaml")},
+		{name: "remote spec without title", specTitle: "  ", expected: filepath.Join(dir, "tapi", "collections", "default.yaml")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DefaultPath(tt.specFile, tt.specTitle)
+			if err != nil {
+				t.Fatalf("DefaultPath() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("DefaultPath() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "petstore.collection.yaml")
+
+	c, err := Load(path)
+	if err != nil || len(c.Requests) != 0 {
+		t.Fatalf("Load() of a missing file = %+v, %v", c, err)
+	}
+
+	c.Put(Request{Name: "get pet", Operation: "getPetById", Params: map[string]string{"petId": "1"}})
+	c.Put(Request{Name: "add pet", Operation: "POST /pet", Body: `{"name": "rex"}`, ContentType: "application/json"})
+	c.Put(Request{Name: "get pet", Operation: "getPetById", Params: map[string]string{"petId": "{{petId}}"}, Headers: map[string]string{"X-Trace": "abc"}})
+
+	if err := c.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(loaded, c) {
+		t.Errorf("Load() = %+v, want %+v", loaded, c)
+	}
+
+	r, err := loaded.Find("get pet")
+	if err != nil || r.Params["petId"] != "{{petId}}" {
+		t.Errorf("Find() = %+v, %v, want the replaced request", r, err)
+	}
+
+	if _, err := loaded.Find("nope"); err == nil {
+		t.Error("Find() should fail for unknown names")
+	}
+}
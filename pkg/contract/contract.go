@@ -0,0 +1,165 @@
+// Package contract smoke-tests a running service against its spec: every
+// operation is called with example data and the response checked against the
+// declared responses.
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+// Case is an operation to test.
+type Case struct {
+	Path      *openapi.Path
+	Operation *openapi.Operation
+}
+
+func (c Case) String() string {
+	return c.Operation.Method + " " + c.Path.Path
+}
+
+// Result is the outcome of testing an operation.
+type Result struct {
+	Operation   string `json:"operation"`
+	OperationID string `json:"operationId,omitempty"`
+	URL         string `json:"url,omitempty"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	Status      string `json:"status,omitempty"`
+	DurationMs  int64  `json:"durationMs"`
+	Passed      bool   `json:"passed"`
+	// Failures are the ways the response breaks the spec.
+	Failures []string `json:"failures,omitempty"`
+	// Error is set when the operation couldn't be called at all.
+	Error string `json:"error,omitempty"`
+}
+
+// Select returns the operations of spec to test: all of them, or those with
+// one of tags and those given by operationId or "METHOD /path" in operations.
+// Webhooks are left out, the service doesn't serve them. Operations are
+// sorted by path, then method.
+func Select(spec *openapi.Spec, tags, operations []string) ([]Case, error) {
+	picked := make(map[*openapi.Operation]bool, len(operations))
+	for _, ref := range operations {
+		_, op, err := spec.FindOperation(ref)
+		if err != nil {
+			return nil, err
+		}
+		picked[op] = true
+	}
+
+	all := len(tags) == 0 && len(operations) == 0
+
+	var cases []Case
+
+	for i := range spec.Paths {
+		for j := range spec.Paths[i].Operations {
+			op := &spec.Paths[i].Operations[j]
+
+			tagged := slices.ContainsFunc(op.Tags, func(tag string) bool { return slices.Contains(tags, tag) })
+
+			if all || tagged || picked[op] {
+				cases = append(cases, Case{Path: &spec.Paths[i], Operation: op})
+			}
+		}
+	}
+
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no operations to test")
+	}
+
+	sort.SliceStable(cases, func(i, j int) bool {
+		if cases[i].Path.Path != cases[j].Path.Path {
+			return cases[i].Path.Path < cases[j].Path.Path
+		}
+		return cases[i].Operation.Method < cases[j].Operation.Method
+	})
+
+	return cases, nil
+}
+
+// ExampleParams returns values for the required parameters of op in the form
+// request.Request.SetParam takes, from the declared examples or generated
+// from the schemas. Values are keyed by openapi.Operation.ParamKey.
+func ExampleParams(op *openapi.Operation) map[string]string {
+	values := make(map[string]string)
+
+	for _, param := range op.Parameters {
+		if !param.Required {
+			continue
+		}
+
+		example := param.Example
+		if example == nil {
+			example = openapi.GenerateExample(param.Schema, openapi.ExampleRequest)
+		}
+
+		values[op.ParamKey(param)] = paramValue(example)
+	}
+
+	return values
+}
+
+// paramValue renders an example as a parameter value, arrays and objects as
+// JSON which SetParam serializes in the parameter's style.
+func paramValue(example interface{}) string {
+	switch v := example.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// ExampleBody returns a body for op with its content type, from the declared
+// example of the preferred media type or generated from its schema. Both are
+// empty when op takes no body.
+func ExampleBody(op *openapi.Operation) (string, string, error) {
+	if op.RequestBody == nil || len(op.RequestBody.Content) == 0 {
+		return "", "", nil
+	}
+
+	contentType := openapi.SortedContentTypes(op.RequestBody.Content)[0]
+	media := op.RequestBody.Content[contentType]
+
+	example := media.Example
+	if example == nil {
+		example = openapi.GenerateExample(media.Schema, openapi.ExampleRequest)
+	}
+
+	body, err := openapi.FormatExample(contentType, example)
+	if err != nil {
+		return "", "", err
+	}
+
+	return body, contentType, nil
+}
+
+// Check lists the ways a response to the operation at path breaks the spec:
+// an undeclared status, Content-Type or header and a body that doesn't match
+// the schema. Server errors fail even when declared.
+func Check(spec *openapi.Spec, path string, resp request.ResponseMsg) []string {
+	var failures []string
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		failures = append(failures, fmt.Sprintf("server error %s", resp.Status))
+	}
+
+	violations, _ := spec.ValidateResponse(path, resp.Request.Method, resp.StatusCode, resp.Headers, []byte(resp.Body))
+	for _, v := range violations {
+		failures = append(failures, v.String())
+	}
+
+	return failures
+}
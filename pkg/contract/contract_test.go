@@ -0,0 +1,169 @@
+package contract
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+func loadPetstore(t *testing.T) *openapi.Spec {
+	t.Helper()
+
+	spec, err := openapi.LoadFromFile("../../example-petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return spec
+}
+
+func TestSelect(t *testing.T) {
+	spec := loadPetstore(t)
+
+	tests := []struct {
+		name       string
+		tags       []string
+		operations []string
+		expected   []string
+		wantErr    string
+	}{
+		{
+			name:     "all",
+			expected: []string{"POST /pet", "GET /pet/findByStatus", "DELETE /pet/{petId}", "GET /pet/{petId}", "GET /store/inventory", "POST /user"},
+		},
+		{
+			name:     "by tag",
+			tags:     []string{"store"},
+			expected: []string{"GET /store/inventory"},
+		},
+		{
+			name:       "by tag and operation",
+			tags:       []string{"store"},
+			operations: []string{"getPetById", "POST /user"},
+			expected:   []string{"GET /pet/{petId}", "GET /store/inventory", "POST /user"},
+		},
+		{
+			name:       "unknown operation",
+			operations: []string{"nope"},
+			wantErr:    `operation "nope" not found`,
+		},
+		{
+			name:    "nothing tagged",
+			tags:    []string{"nope"},
+			wantErr: "no operations to test",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cases, err := Select(spec, tt.tags, tt.operations)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Select() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Select() error = %v", err)
+			}
+
+			got := make([]string, 0, len(cases))
+			for _, c := range cases {
+				got = append(got, c.String())
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Select() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExampleParams(t *testing.T) {
+	op := &openapi.Operation{Parameters: []openapi.Parameter{
+		{Name: "petId", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer"}},
+		{Name: "status", In: "query", Required: true, Example: "sold", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "tags", In: "query", Required: true, Schema: &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string", Example: "cute"}}},
+		{Name: "limit", In: "query", Schema: &openapi.Schema{Type: "integer"}},
+	}}
+
+	expected := map[string]string{"petId": "0", "status": "sold", "tags": `["cute"]`}
+	if got := ExampleParams(op); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ExampleParams() = %v, want %v", got, expected)
+	}
+}
+
+func TestExampleParamsSharedNames(t *testing.T) {
+	op := &openapi.Operation{Parameters: []openapi.Parameter{
+		{Name: "id", In: "path", Required: true, Example: "1", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "id", In: "query", Required: true, Example: "2", Schema: &openapi.Schema{Type: "string"}},
+	}}
+
+	expected := map[string]string{"path:id": "1", "query:id": "2"}
+	if got := ExampleParams(op); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ExampleParams() = %v, want %v", got, expected)
+	}
+}
+
+func TestExampleBody(t *testing.T) {
+	op := &openapi.Operation{RequestBody: &openapi.RequestBody{Content: map[string]openapi.MediaType{
+		"application/xml": {Example: "<pet/>"},
+		"application/json": {Schema: &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{
+			"id":   {Type: "integer", ReadOnly: true},
+			"name": {Type: "string", Example: "rex"},
+		}}},
+	}}}
+
+	body, contentType, err := ExampleBody(op)
+	if err != nil {
+		t.Fatalf("ExampleBody() error = %v", err)
+	}
+
+	if contentType != "application/json" || body != "{\n  \"name\": \"rex\"\n}" {
+		t.Errorf("ExampleBody() = %q, %q", body, contentType)
+	}
+
+	if body, contentType, _ := ExampleBody(&openapi.Operation{}); body != "" || contentType != "" {
+		t.Errorf("ExampleBody() without a body = %q, %q", body, contentType)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	spec := loadPetstore(t)
+
+	sent := request.Request{Method: "GET", Path: "/pet/{petId}"}
+	jsonHeader := http.Header{"Content-Type": []string{"application/json"}}
+
+	tests := []struct {
+		name     string
+		resp     request.ResponseMsg
+		expected []string
+	}{
+		{
+			name: "matches",
+			resp: request.ResponseMsg{StatusCode: 200, Status: "200 OK", Headers: jsonHeader, Body: `{"id":1,"name":"rex"}`, Request: sent},
+		},
+		{
+			name:     "schema violation",
+			resp:     request.ResponseMsg{StatusCode: 200, Status: "200 OK", Headers: jsonHeader, Body: `{"id":"one"}`, Request: sent},
+			expected: []string{"$.id: value must be an integer"},
+		},
+		{
+			name:     "server error",
+			resp:     request.ResponseMsg{StatusCode: 500, Status: "500 Internal Server Error", Request: sent},
+			expected: []string{"server error 500 Internal Server Error", "status 500 is not declared for GET /pet/{petId}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Check(spec, "/pet/{petId}", tt.resp); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Check() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package contract
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Time     string      `xml:"time,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes results as a JUnit XML report with a single suite named
+// after the API, which CI servers show per operation. Operations that
+// couldn't be called are errors, responses that break the spec failures.
+func WriteJUnit(w io.Writer, suite string, results []Result) error {
+	s := junitSuite{Name: suite, Tests: len(results)}
+
+	var totalMs int64
+
+	for _, r := range results {
+		totalMs += r.DurationMs
+
+		c := junitCase{Name: r.Operation, ClassName: suite, Time: seconds(r.DurationMs)}
+		if r.OperationID != "" {
+			c.Name = r.OperationID + " (" + r.Operation + ")"
+		}
+
+		switch {
+		case r.Error != "":
+			c.Error = &junitMessage{Message: r.Error, Text: r.Error}
+			s.Errors++
+		case !r.Passed:
+			c.Failure = &junitMessage{
+				Message: fmt.Sprintf("%s: %d problems with the response", r.Status, len(r.Failures)),
+				Text:    strings.Join(r.Failures, "\n"),
+			}
+			s.Failures++
+		}
+
+		s.Cases = append(s.Cases, c)
+	}
+
+	s.Time = seconds(totalMs)
+
+	report := junitSuites{
+		Name:     suite,
+		Tests:    s.Tests,
+		Failures: s.Failures,
+		Errors:   s.Errors,
+		Time:     s.Time,
+		Suites:   []junitSuite{s},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+func seconds(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
@@ -0,0 +1,43 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	results := []Result{
+		{Operation: "GET /pets", OperationID: "listPets", Status: "200 OK", DurationMs: 12, Passed: true},
+		{Operation: "GET /pets/{petId}", Status: "200 OK", DurationMs: 8, Failures: []string{"$.id: value must be an integer", "$.name: property \"name\" is missing"}},
+		{Operation: "POST /pets", Error: "connection refused"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, "Pets", results); err != nil {
+		t.Fatalf("WriteJUnit() error = %v", err)
+	}
+
+	var report junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("report isn't valid XML: %v\n%s", err, buf.String())
+	}
+
+	if report.Tests != 3 || report.Failures != 1 || report.Errors != 1 || report.Time != "0.020" {
+		t.Errorf("report totals = %+v", report)
+	}
+
+	cases := report.Suites[0].Cases
+
+	if cases[0].Name != "listPets (GET /pets)" || cases[0].Failure != nil || cases[0].Error != nil {
+		t.Errorf("passed case = %+v", cases[0])
+	}
+
+	if f := cases[1].Failure; f == nil || f.Message != "200 OK: 2 problems with the response" || f.Text != "$.id: value must be an integer\n$.name: property \"name\" is missing" {
+		t.Errorf("failed case = %+v", cases[1].Failure)
+	}
+
+	if e := cases[2].Error; e == nil || e.Message != "connection refused" {
+		t.Errorf("error case = %+v", cases[2].Error)
+	}
+}
@@ -0,0 +1,411 @@
+// Package curl parses curl command lines, as found in bug reports or copied
+// from browser dev tools, and maps them onto operations of a spec.
+package curl
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Header is a single header as passed with -H, in command line order.
+type Header struct {
+	Name  string
+	Value string
+}
+
+// Command is the request described by a curl command line.
+type Command struct {
+	Method  string
+	URL     string
+	Headers []Header
+	Body    string
+	Cookies map[string]string
+}
+
+// Header returns the value of the first header with the given name.
+func (c *Command) Header(name string) (string, bool) {
+	for _, h := range c.Headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// valueFlags are flags that take a value which doesn't matter for the
+// request, they're skipped along with it. Any other unknown flag is assumed
+// to be a switch like --compressed or -k.
+var valueFlags = map[string]bool{
+	"-o": true, "--output": true, "-w": true, "--write-out": true,
+	"-m": true, "--max-time": true, "--connect-timeout": true,
+	"-x": true, "--proxy": true, "-U": true, "--proxy-user": true,
+	"--cacert": true, "--capath": true, "-E": true, "--cert": true, "--key": true,
+	"-c": true, "--cookie-jar": true, "-K": true, "--config": true,
+	"-r": true, "--range": true, "--resolve": true, "--connect-to": true,
+	"--retry": true, "--retry-delay": true, "--max-redirs": true,
+	"--limit-rate": true, "--interface": true, "-D": true, "--dump-header": true,
+	"--trace": true, "--trace-ascii": true, "-y": true, "-Y": true,
+}
+
+// Parse parses a curl command line. Quoting follows POSIX shells, including
+// $'...' strings and backslash line continuations. The method defaults to
+// GET, or POST when a body is given, as curl does.
+func Parse(cmdline string) (*Command, error) {
+	args, err := splitWords(cmdline)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) == 0 || args[0] != "curl" {
+		return nil, fmt.Errorf("not a curl command, it should start with \"curl\"")
+	}
+
+	c := &Command{}
+
+	var (
+		data    []string
+		getData bool
+		head    bool
+	)
+
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			if c.URL != "" {
+				return nil, fmt.Errorf("unexpected argument %q, only one URL is supported", arg)
+			}
+			c.URL = arg
+			continue
+		}
+
+		name, value, hasValue := arg, "", false
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue = strings.Cut(arg, "=")
+		case len(arg) > 2:
+			// Short flags may be grouped, "-sSL", or carry their value, "-XPOST".
+			name, value, hasValue = splitShortFlags(arg)
+		}
+
+		takesValue := flagTakesValue(name)
+		if takesValue && !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("flag %s needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch name {
+		case "-X", "--request":
+			c.Method = strings.ToUpper(value)
+		case "--url":
+			c.URL = value
+		case "-H", "--header":
+			headerName, headerValue, ok := strings.Cut(value, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid header %q, expected \"Name: value\"", value)
+			}
+			c.Headers = append(c.Headers, Header{Name: strings.TrimSpace(headerName), Value: strings.TrimSpace(headerValue)})
+		case "-A", "--user-agent":
+			c.Headers = append(c.Headers, Header{Name: "User-Agent", Value: value})
+		case "-e", "--referer":
+			c.Headers = append(c.Headers, Header{Name: "Referer", Value: value})
+		case "-u", "--user":
+			c.Headers = append(c.Headers, Header{Name: "Authorization", Value: "Basic " + base64.StdEncoding.EncodeToString([]byte(value))})
+		case "--oauth2-bearer":
+			c.Headers = append(c.Headers, Header{Name: "Authorization", Value: "Bearer " + value})
+		case "-b", "--cookie":
+			// Without "=" the value names a cookie file, which isn't sent as is.
+			if strings.Contains(value, "=") {
+				c.addCookies(value)
+			}
+		case "-d", "--data", "--data-raw", "--data-binary", "--data-ascii":
+			data = append(data, value)
+		case "--data-urlencode":
+			encoded, err := urlencodeData(value)
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, encoded)
+		case "--json":
+			data = append(data, value)
+			c.Headers = append(c.Headers,
+				Header{Name: "Content-Type", Value: "application/json"},
+				Header{Name: "Accept", Value: "application/json"})
+		case "-F", "--form", "--form-string":
+			return nil, fmt.Errorf("multipart form data (%s) isn't supported", name)
+		case "-G", "--get":
+			getData = true
+		case "-I", "--head":
+			head = true
+		}
+	}
+
+	if c.URL == "" {
+		return nil, fmt.Errorf("curl command has no URL")
+	}
+
+	if !strings.Contains(c.URL, "://") {
+		c.URL = "http://" + c.URL
+	}
+
+	body := strings.Join(data, "&")
+
+	switch {
+	case getData && body != "":
+		sep := "?"
+		if strings.Contains(c.URL, "?") {
+			sep = "&"
+		}
+		c.URL += sep + body
+	case body != "":
+		c.Body = body
+	}
+
+	if c.Method == "" {
+		switch {
+		case head:
+			c.Method = http.MethodHead
+		case c.Body != "":
+			c.Method = http.MethodPost
+		default:
+			c.Method = http.MethodGet
+		}
+	}
+
+	return c, nil
+}
+
+func (c *Command) addCookies(value string) {
+	if c.Cookies == nil {
+		c.Cookies = make(map[string]string)
+	}
+
+	for _, pair := range strings.Split(value, ";") {
+		name, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && name != "" {
+			c.Cookies[name] = v
+		}
+	}
+}
+
+// urlencodeData encodes a --data-urlencode value the way curl does: the
+// content of "content" and "=content" is encoded as a whole, and of
+// "name=content" only the part after "=". Reading content from a file,
+// "@file" or "name@file", isn't supported.
+func urlencodeData(value string) (string, error) {
+	name, content := "", value
+	if i := strings.IndexAny(value, "=@"); i >= 0 {
+		if value[i] == '@' {
+			return "", fmt.Errorf("reading --data-urlencode content from a file (%s) isn't supported", value)
+		}
+		name, content = value[:i], value[i+1:]
+	}
+
+	// curl escapes spaces as %20 rather than "+".
+	encoded := strings.ReplaceAll(url.QueryEscape(content), "+", "%20")
+	if name == "" {
+		return encoded, nil
+	}
+
+	return name + "=" + encoded, nil
+}
+
+func flagTakesValue(name string) bool {
+	switch name {
+	case "-X", "--request", "--url", "-H", "--header", "-A", "--user-agent",
+		"-e", "--referer", "-u", "--user", "--oauth2-bearer", "-b", "--cookie",
+		"-d", "--data", "--data-raw", "--data-binary", "--data-ascii", "--data-urlencode",
+		"--json", "-F", "--form", "--form-string":
+		return true
+	}
+
+	return valueFlags[name]
+}
+
+// splitShortFlags splits grouped short flags, returning the last one along
+// with its attached value. Switches in front of it are dropped as they don't
+// change the request, except -G and -I which are returned on their own.
+func splitShortFlags(arg string) (string, string, bool) {
+	for i := 1; i < len(arg); i++ {
+		name := "-" + arg[i:i+1]
+		if flagTakesValue(name) {
+			if i+1 < len(arg) {
+				return name, arg[i+1:], true
+			}
+			return name, "", false
+		}
+	}
+
+	for _, name := range []string{"-G", "-I"} {
+		if strings.Contains(arg[1:], name[1:]) {
+			return name, "", false
+		}
+	}
+
+	return arg, "", false
+}
+
+// splitWords splits a command line into words the way a POSIX shell does.
+func splitWords(s string) ([]string, error) {
+	var (
+		words  []string
+		word   strings.Builder
+		inWord bool
+	)
+
+	flush := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+
+	runes := []rune(s)
+	n := len(runes)
+
+	for i := 0; i < n; i++ {
+		r := runes[i]
+
+		switch {
+		case r == '\\':
+			if i+1 < n {
+				i++
+				// A backslash before a line break continues the line.
+				if runes[i] == '\n' || runes[i] == '\r' {
+					if runes[i] == '\r' && i+1 < n && runes[i+1] == '\n' {
+						i++
+					}
+					continue
+				}
+				word.WriteRune(runes[i])
+				inWord = true
+			}
+		case r == '\'':
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(string(runes[i+1 : end]))
+			inWord = true
+			i = end
+		case r == '$' && i+1 < n && runes[i+1] == '\'':
+			value, end, err := ansiCString(runes, i+2)
+			if err != nil {
+				return nil, err
+			}
+			word.WriteString(value)
+			inWord = true
+			i = end
+		case r == '"':
+			i++
+			for ; i < n && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < n && strings.ContainsRune("$`\"\\\n", runes[i+1]) {
+					i++
+					if runes[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteRune(runes[i])
+			}
+			if i >= n {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			flush()
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	flush()
+
+	return words, nil
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// ansiCString reads a $'...' string starting after the opening quote and
+// returns its value and the index of the closing quote.
+func ansiCString(runes []rune, from int) (string, int, error) {
+	var b strings.Builder
+
+	for i := from; i < len(runes); i++ {
+		r := runes[i]
+
+		if r == '\'' {
+			return b.String(), i, nil
+		}
+
+		if r != '\\' || i+1 >= len(runes) {
+			b.WriteRune(r)
+			continue
+		}
+
+		i++
+		switch runes[i] {
+		case 'n':
+			b.WriteRune('\n')
+		case 't':
+			b.WriteRune('\t')
+		case 'r':
+			b.WriteRune('\r')
+		case 'u', 'x':
+			size := 4
+			if runes[i] == 'x' {
+				size = 2
+			}
+
+			var code rune
+			j := i + 1
+			for ; j < len(runes) && j <= i+size && isHex(runes[j]); j++ {
+				code = code*16 + hexValue(runes[j])
+			}
+
+			if j == i+1 {
+				b.WriteRune('\\')
+				b.WriteRune(runes[i])
+				continue
+			}
+
+			b.WriteRune(code)
+			i = j - 1
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated $' quote")
+}
+
+func isHex(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'a' && r <= 'f' || r >= 'A' && r <= 'F'
+}
+
+func hexValue(r rune) rune {
+	switch {
+	case r >= 'a':
+		return r - 'a' + 10
+	case r >= 'A':
+		return r - 'A' + 10
+	default:
+		return r - '0'
+	}
+}
@@ -0,0 +1,106 @@
+package curl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmdline string
+		want    Command
+		wantErr string
+	}{
+		{
+			name:    "plain GET",
+			cmdline: "curl https://api.example.com/pets",
+			want:    Command{Method: "GET", URL: "https://api.example.com/pets"},
+		},
+		{
+			name: "multi-line with headers and body",
+			cmdline: `curl -X PUT 'https://api.example.com/pets/1' \
+  -H 'Content-Type: application/json' \
+  -H "X-Trace: a \"b\"" \
+  --data-raw '{"name": "it'\''s"}'`,
+			want: Command{
+				Method:  "PUT",
+				URL:     "https://api.example.com/pets/1",
+				Headers: []Header{{Name: "Content-Type", Value: "application/json"}, {Name: "X-Trace", Value: `a "b"`}},
+				Body:    `{"name": "it's"}`,
+			},
+		},
+		{
+			name:    "data implies POST",
+			cmdline: `curl api.example.com/pets -d 'name=rex' -d 'age=3' --compressed -sSL`,
+			want:    Command{Method: "POST", URL: "http://api.example.com/pets", Body: "name=rex&age=3"},
+		},
+		{
+			name:    "-G sends data in the query",
+			cmdline: `curl -G 'https://api.example.com/pets?limit=1' -d tag=dog`,
+			want:    Command{Method: "GET", URL: "https://api.example.com/pets?limit=1&tag=dog"},
+		},
+		{
+			name:    "user, cookie and attached values",
+			cmdline: `curl -XDELETE -u admin:secret -b 'session=abc; theme=dark' --url=https://api.example.com/pets/1`,
+			want: Command{
+				Method:  "DELETE",
+				URL:     "https://api.example.com/pets/1",
+				Headers: []Header{{Name: "Authorization", Value: "Basic YWRtaW46c2VjcmV0"}},
+				Cookies: map[string]string{"session": "abc", "theme": "dark"},
+			},
+		},
+		{
+			name:    "ANSI-C quoting from browser dev tools",
+			cmdline: `curl 'https://api.example.com/pets' --data-raw $'{"note":"line\nbreak é"}' -o out.json`,
+			want:    Command{Method: "POST", URL: "https://api.example.com/pets", Body: "{\"note\":\"line\nbreak é\"}"},
+		},
+		{
+			name:    "json flag",
+			cmdline: `curl --json '{"a":1}' https://api.example.com/pets`,
+			want: Command{
+				Method:  "POST",
+				URL:     "https://api.example.com/pets",
+				Headers: []Header{{Name: "Content-Type", Value: "application/json"}, {Name: "Accept", Value: "application/json"}},
+				Body:    `{"a":1}`,
+			},
+		},
+		{
+			name:    "urlencoded data",
+			cmdline: `curl https://api.example.com/search --data-urlencode 'q=a b&c' --data-urlencode '=x+y' --data-urlencode 'tag é' -d raw=a+b`,
+			want:    Command{Method: "POST", URL: "https://api.example.com/search", Body: "q=a%20b%26c&x%2By&tag%20%C3%A9&raw=a+b"},
+		},
+		{
+			name:    "urlencoded data in the query",
+			cmdline: `curl -G https://api.example.com/search --data-urlencode 'q=50% off'`,
+			want:    Command{Method: "GET", URL: "https://api.example.com/search?q=50%25%20off"},
+		},
+		{name: "urlencoded data from a file", cmdline: "curl --data-urlencode q@query.txt https://example.com", wantErr: "from a file (q@query.txt) isn't supported"},
+		{name: "not curl", cmdline: "wget https://example.com", wantErr: "not a curl command"},
+		{name: "no URL", cmdline: "curl -H 'A: b'", wantErr: "no URL"},
+		{name: "missing value", cmdline: "curl https://example.com -H", wantErr: "flag -H needs a value"},
+		{name: "unterminated quote", cmdline: "curl 'https://example.com", wantErr: "unterminated single quote"},
+		{name: "multipart", cmdline: "curl -F file=@a.png https://example.com", wantErr: "multipart form data (-F) isn't supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.cmdline)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
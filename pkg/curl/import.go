@@ -0,0 +1,142 @@
+package curl
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+// Request is a curl command mapped onto an operation of a spec.
+type Request struct {
+	Path      *openapi.Path
+	Operation *openapi.Operation
+	// Server is the scheme, host and base path the command was sent to.
+	Server string
+	// Params holds the values of the operation's parameters found in the
+	// command, keyed by location and name.
+	Params map[string]map[string]string
+	// Headers are the headers that aren't parameters of the operation,
+	// without Content-Type.
+	Headers     map[string]string
+	Body        string
+	ContentType string
+	// Ignored lists query parameters and cookies the operation doesn't
+	// declare, they can't be set in the request builder.
+	Ignored []string
+}
+
+// Value returns the value for param, the signature the request builder
+// takes to fill its inputs.
+func (r *Request) Value(param openapi.Parameter) (string, bool) {
+	value, ok := r.Params[param.In][param.Name]
+	return value, ok
+}
+
+// Import parses a curl command line and finds the operation of spec it
+// calls by method and path template.
+func Import(spec *openapi.Spec, cmdline string) (*Request, error) {
+	c, err := Parse(cmdline)
+	if err != nil {
+		return nil, err
+	}
+
+	return Match(spec, c)
+}
+
+// Match finds the operation of spec the command calls and sorts its URL,
+// headers and cookies into the operation's parameters.
+func Match(spec *openapi.Spec, c *Command) (*Request, error) {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL %q: %w", c.URL, err)
+	}
+
+	match, err := spec.MatchOperation(c.Method, u.EscapedPath())
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Request{
+		Path:      match.Path,
+		Operation: match.Operation,
+		Server:    u.Scheme + "://" + u.Host + match.BasePath,
+		Params:    map[string]map[string]string{"path": match.PathParams},
+		Body:      c.Body,
+	}
+
+	query := u.Query()
+	cookies := make(map[string]string, len(c.Cookies))
+	for name, value := range c.Cookies {
+		cookies[name] = value
+	}
+
+	headers := make(map[string]string, len(c.Headers))
+	for _, h := range c.Headers {
+		if strings.EqualFold(h.Name, "Cookie") {
+			for _, pair := range strings.Split(h.Value, ";") {
+				if name, value, ok := strings.Cut(strings.TrimSpace(pair), "="); ok {
+					cookies[name] = value
+				}
+			}
+			continue
+		}
+
+		if strings.EqualFold(h.Name, "Content-Type") {
+			r.ContentType = h.Value
+			continue
+		}
+
+		headers[http.CanonicalHeaderKey(h.Name)] = h.Value
+	}
+
+	for _, param := range match.Operation.Parameters {
+		var (
+			value string
+			ok    bool
+		)
+
+		switch param.In {
+		case "query":
+			if values, found := query[param.Name]; found {
+				value, ok = strings.Join(values, ","), true
+				delete(query, param.Name)
+			}
+		case "header":
+			key := http.CanonicalHeaderKey(param.Name)
+			value, ok = headers[key]
+			delete(headers, key)
+		case "cookie":
+			value, ok = cookies[param.Name]
+			delete(cookies, param.Name)
+		}
+
+		if ok {
+			r.set(param.In, param.Name, value)
+		}
+	}
+
+	for name := range query {
+		r.Ignored = append(r.Ignored, "query parameter "+name)
+	}
+	for name := range cookies {
+		r.Ignored = append(r.Ignored, "cookie "+name)
+	}
+	sort.Strings(r.Ignored)
+
+	if len(headers) > 0 {
+		r.Headers = headers
+	}
+
+	return r, nil
+}
+
+func (r *Request) set(in, name, value string) {
+	if r.Params[in] == nil {
+		r.Params[in] = make(map[string]string)
+	}
+	r.Params[in][name] = value
+}
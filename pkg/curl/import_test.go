@@ -0,0 +1,82 @@
+package curl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+func createImportTestSpec() *openapi.Spec {
+	return &openapi.Spec{
+		Paths: []openapi.Path{
+			{Path: "/pets", Operations: []openapi.Operation{{Method: "POST", OperationID: "addPet"}}},
+			{Path: "/pets/{petId}", Operations: []openapi.Operation{{
+				Method:      "GET",
+				OperationID: "getPet",
+				Parameters: []openapi.Parameter{
+					{Name: "petId", In: "path", Required: true},
+					{Name: "fields", In: "query", Schema: &openapi.Schema{Type: "array"}},
+					{Name: "X-Tenant", In: "header"},
+					{Name: "session", In: "cookie"},
+				},
+			}}},
+		},
+	}
+}
+
+func TestImport(t *testing.T) {
+	spec := createImportTestSpec()
+
+	r, err := Import(spec, `curl 'https://api.example.com/v1/pets/10?fields=id&fields=name&debug=1' \
+  -H 'x-tenant: acme' -H 'Authorization: Bearer t' -H 'Cookie: session=abc; other=1'`)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if r.Operation.OperationID != "getPet" {
+		t.Fatalf("Import() matched %s %s", r.Operation.Method, r.Path.Path)
+	}
+
+	if r.Server != "https://api.example.com/v1" {
+		t.Errorf("Server = %q", r.Server)
+	}
+
+	want := map[string]string{"petId": "10", "fields": "id,name", "X-Tenant": "acme", "session": "abc"}
+	for _, param := range r.Operation.Parameters {
+		if value, ok := r.Value(param); !ok || value != want[param.Name] {
+			t.Errorf("Value(%s) = %q, %v, want %q", param.Name, value, ok, want[param.Name])
+		}
+	}
+
+	if !reflect.DeepEqual(r.Headers, map[string]string{"Authorization": "Bearer t"}) {
+		t.Errorf("Headers = %v", r.Headers)
+	}
+
+	if !reflect.DeepEqual(r.Ignored, []string{"cookie other", "query parameter debug"}) {
+		t.Errorf("Ignored = %v", r.Ignored)
+	}
+}
+
+func TestImportBody(t *testing.T) {
+	r, err := Import(createImportTestSpec(), `curl https://api.example.com/pets -H 'Content-Type: application/json' -d '{"name":"rex"}'`)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if r.Operation.OperationID != "addPet" || r.Body != `{"name":"rex"}` || r.ContentType != "application/json" {
+		t.Errorf("Import() = %s, body %q, content type %q", r.Operation.OperationID, r.Body, r.ContentType)
+	}
+
+	if r.Headers != nil || r.Server != "https://api.example.com" {
+		t.Errorf("Import() headers = %v, server %q", r.Headers, r.Server)
+	}
+}
+
+func TestImportNoMatch(t *testing.T) {
+	_, err := Import(createImportTestSpec(), `curl -X DELETE https://api.example.com/pets`)
+	if err == nil || !strings.Contains(err.Error(), "has no DELETE operation") {
+		t.Errorf("Import() error = %v", err)
+	}
+}
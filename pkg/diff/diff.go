@@ -0,0 +1,713 @@
+// Package diff compares two versions of a spec and tells which changes break
+// existing clients.
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// Change is a single difference between two versions of a spec.
+type Change struct {
+	// Operation is the method and path template, as in the new version when
+	// the operation exists there.
+	Operation string `json:"operation"`
+	// Location is the part of the operation that changed, like
+	// "query parameter limit" or "response 200 application/json". It's empty
+	// for changes to the operation itself.
+	Location string `json:"location,omitempty"`
+	// Path points into the schema at Location, like $.items[*].name.
+	Path     string `json:"path,omitempty"`
+	Kind     Kind   `json:"kind"`
+	Message  string `json:"message"`
+	Breaking bool   `json:"breaking"`
+}
+
+// direction tells whether a schema describes values clients send or values
+// they receive. Narrowing what clients may send breaks them, and so does
+// widening what they may receive.
+type direction int
+
+const (
+	sent direction = iota
+	received
+)
+
+// narrowingBreaks tells whether allowing fewer values than before breaks
+// clients, which it does for clients that send them.
+func (dir direction) narrowingBreaks() bool {
+	return dir == sent
+}
+
+// wideningBreaks tells whether allowing more values than before breaks
+// clients, which it does for clients that receive them.
+func (dir direction) wideningBreaks() bool {
+	return dir == received
+}
+
+// maxSchemaDepth stops comparing deeply nested or recursive schemas.
+const maxSchemaDepth = 16
+
+// Compare lists the differences between the operations of base and revision,
+// sorted by operation. Webhooks aren't compared.
+func Compare(base, revision *openapi.Spec) []Change {
+	d := &differ{}
+
+	oldOps := operations(base)
+	newOps := operations(revision)
+
+	for _, key := range sortedKeys(oldOps) {
+		if _, ok := newOps[key]; !ok {
+			old := oldOps[key]
+			d.operation = old.String()
+			d.add("", "", Removed, "operation removed", true)
+		}
+	}
+
+	for _, key := range sortedKeys(newOps) {
+		rev := newOps[key]
+		d.operation = rev.String()
+
+		old, ok := oldOps[key]
+		if !ok {
+			d.add("", "", Added, "operation added", false)
+			continue
+		}
+
+		d.compareParameters(old, rev)
+		d.compareRequestBody(old.op.RequestBody, rev.op.RequestBody)
+		d.compareResponses(old.op.Responses, rev.op.Responses)
+	}
+
+	sort.SliceStable(d.changes, func(i, j int) bool {
+		return d.changes[i].Operation < d.changes[j].Operation
+	})
+
+	return d.changes
+}
+
+// Breaking counts the breaking changes.
+func Breaking(changes []Change) int {
+	n := 0
+	for _, c := range changes {
+		if c.Breaking {
+			n++
+		}
+	}
+
+	return n
+}
+
+type operation struct {
+	path string
+	op   *openapi.Operation
+}
+
+func (o operation) String() string {
+	return o.op.Method + " " + o.path
+}
+
+var pathParam = regexp.MustCompile(`\{[^}]*\}`)
+
+// operations keys the operations of spec by method and path template, with
+// parameter names left out so renaming one doesn't look like a new path.
+func operations(spec *openapi.Spec) map[string]operation {
+	ops := make(map[string]operation)
+
+	for i := range spec.Paths {
+		p := &spec.Paths[i]
+		template := pathParam.ReplaceAllString(p.Path, "{}")
+
+		for j := range p.Operations {
+			op := &p.Operations[j]
+			ops[strings.ToUpper(op.Method)+" "+template] = operation{path: p.Path, op: op}
+		}
+	}
+
+	return ops
+}
+
+type differ struct {
+	operation string
+	changes   []Change
+}
+
+func (d *differ) add(location, path string, kind Kind, message string, breaking bool) {
+	d.changes = append(d.changes, Change{
+		Operation: d.operation,
+		Location:  location,
+		Path:      path,
+		Kind:      kind,
+		Message:   message,
+		Breaking:  breaking,
+	})
+}
+
+// parameterKey identifies a parameter across versions. Path parameters are
+// matched by position, as their names may change with the template.
+func parameterKey(o operation, param openapi.Parameter) string {
+	switch param.In {
+	case "path":
+		names := pathParam.FindAllString(o.path, -1)
+		if i := slices.Index(names, "{"+param.Name+"}"); i >= 0 {
+			return fmt.Sprintf("path #%d", i)
+		}
+	case "header":
+		return "header " + strings.ToLower(param.Name)
+	}
+
+	return param.In + " " + param.Name
+}
+
+func (d *differ) compareParameters(old, rev operation) {
+	oldParams := make(map[string]openapi.Parameter, len(old.op.Parameters))
+	for _, param := range old.op.Parameters {
+		oldParams[parameterKey(old, param)] = param
+	}
+
+	newParams := make(map[string]openapi.Parameter, len(rev.op.Parameters))
+	for _, param := range rev.op.Parameters {
+		newParams[parameterKey(rev, param)] = param
+	}
+
+	for _, key := range sortedKeys(oldParams) {
+		if _, ok := newParams[key]; !ok {
+			// Servers usually ignore parameters they don't know, so clients
+			// still sending it keep working.
+			param := oldParams[key]
+			d.add(parameterLocation(param), "", Removed, "parameter removed", false)
+		}
+	}
+
+	for _, key := range sortedKeys(newParams) {
+		param := newParams[key]
+		location := parameterLocation(param)
+
+		oldParam, ok := oldParams[key]
+		if !ok {
+			if param.Required {
+				d.add(location, "", Added, "required parameter added", true)
+			} else {
+				d.add(location, "", Added, "optional parameter added", false)
+			}
+			continue
+		}
+
+		// Header names are case-insensitive, only path parameters can be
+		// renamed without a new key.
+		if param.In == "path" && oldParam.Name != param.Name {
+			d.add(location, "", Changed, fmt.Sprintf("renamed from %s", oldParam.Name), false)
+		}
+
+		switch {
+		case param.Required && !oldParam.Required:
+			d.add(location, "", Changed, "became required", true)
+		case !param.Required && oldParam.Required:
+			d.add(location, "", Changed, "became optional", false)
+		}
+
+		if oldParam.Style != param.Style || !sameExplode(oldParam.Explode, param.Explode) {
+			d.add(location, "", Changed, "serialization style changed", true)
+		}
+
+		d.compareSchema(location, "$", oldParam.Schema, param.Schema, sent, 0)
+	}
+}
+
+func parameterLocation(param openapi.Parameter) string {
+	return param.In + " parameter " + param.Name
+}
+
+func sameExplode(a, b *bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func (d *differ) compareRequestBody(old, rev *openapi.RequestBody) {
+	const location = "request body"
+
+	switch {
+	case old == nil && rev == nil:
+		return
+	case old == nil:
+		if rev.Required {
+			d.add(location, "", Added, "required request body added", true)
+		} else {
+			d.add(location, "", Added, "optional request body added", false)
+		}
+		return
+	case rev == nil:
+		d.add(location, "", Removed, "request body removed", true)
+		return
+	}
+
+	switch {
+	case rev.Required && !old.Required:
+		d.add(location, "", Changed, "became required", true)
+	case !rev.Required && old.Required:
+		d.add(location, "", Changed, "became optional", false)
+	}
+
+	d.compareContent(location, old.Content, rev.Content, sent)
+}
+
+func (d *differ) compareResponses(old, rev map[string]openapi.Response) {
+	for _, status := range sortedKeys(old) {
+		if _, ok := rev[status]; !ok {
+			// Clients handle errors generically, but not a missing success.
+			d.add("response "+status, "", Removed, "response removed", strings.HasPrefix(status, "2"))
+		}
+	}
+
+	for _, status := range sortedKeys(rev) {
+		location := "response " + status
+
+		oldResp, ok := old[status]
+		if !ok {
+			d.add(location, "", Added, "response added", false)
+			continue
+		}
+
+		d.compareContent(location, oldResp.Content, rev[status].Content, received)
+	}
+}
+
+func (d *differ) compareContent(location string, old, rev map[string]openapi.MediaType, dir direction) {
+	for _, contentType := range sortedKeys(old) {
+		if _, ok := rev[contentType]; !ok {
+			d.add(location+" "+contentType, "", Removed, "media type removed", true)
+		}
+	}
+
+	for _, contentType := range sortedKeys(rev) {
+		oldMedia, ok := old[contentType]
+		if !ok {
+			d.add(location+" "+contentType, "", Added, "media type added", false)
+			continue
+		}
+
+		d.compareSchema(location+" "+contentType, "$", oldMedia.Schema, rev[contentType].Schema, dir, 0)
+	}
+}
+
+// narrowed records a change that allows fewer values than before. It breaks
+// clients that send them.
+func (d *differ) narrowed(location, path, message string, dir direction) {
+	d.add(location, path, Changed, message, dir.narrowingBreaks())
+}
+
+// widened records a change that allows more values than before. It breaks
+// clients that receive them.
+func (d *differ) widened(location, path, message string, dir direction) {
+	d.add(location, path, Changed, message, dir.wideningBreaks())
+}
+
+func (d *differ) compareSchema(location, path string, old, rev *openapi.Schema, dir direction, depth int) {
+	if old == nil || rev == nil || old.Circular || rev.Circular || depth > maxSchemaDepth {
+		return
+	}
+
+	old, rev = flattenAllOf(old), flattenAllOf(rev)
+
+	if oldType, newType := schemaType(old), schemaType(rev); oldType != newType {
+		message := fmt.Sprintf("type changed from %s to %s", oldType, newType)
+
+		// Every integer is a number, so their constraints still compare.
+		switch {
+		case oldType == "integer" && newType == "number":
+			d.widened(location, path, message, dir)
+		case oldType == "number" && newType == "integer":
+			d.narrowed(location, path, message, dir)
+		case oldType == "":
+			d.narrowed(location, path, fmt.Sprintf("type set to %s", newType), dir)
+			return
+		case newType == "":
+			d.widened(location, path, fmt.Sprintf("type %s removed", oldType), dir)
+			return
+		default:
+			d.add(location, path, Changed, message, true)
+			// Constraints of another type don't compare.
+			return
+		}
+	}
+
+	switch {
+	case old.Format == rev.Format:
+	case old.Format == "":
+		d.narrowed(location, path, fmt.Sprintf("format %s added", rev.Format), dir)
+	case rev.Format == "":
+		d.widened(location, path, fmt.Sprintf("format %s removed", old.Format), dir)
+	default:
+		d.add(location, path, Changed, fmt.Sprintf("format changed from %s to %s", old.Format, rev.Format), true)
+	}
+
+	switch {
+	case rev.Nullable && !old.Nullable:
+		d.widened(location, path, "became nullable", dir)
+	case !rev.Nullable && old.Nullable:
+		d.narrowed(location, path, "no longer nullable", dir)
+	}
+
+	d.compareEnum(location, path, old.Enum, rev.Enum, dir)
+	d.compareLimits(location, path, old, rev, dir)
+
+	d.compareProperties(location, path, old, rev, dir, depth)
+	d.compareAdditionalProperties(location, path, old.AdditionalProperties, rev.AdditionalProperties, dir, depth)
+
+	d.compareSchema(location, path+"[*]", old.Items, rev.Items, dir, depth+1)
+
+	d.compareBranches(location, path, "oneOf", old.OneOf, rev.OneOf, dir, depth)
+	d.compareBranches(location, path, "anyOf", old.AnyOf, rev.AnyOf, dir, depth)
+	d.compareParts(location, path, old.AllOf, rev.AllOf, dir)
+}
+
+// compareBranches compares the oneOf or anyOf alternatives of a schema. A
+// removed branch allows fewer values, an added one more. Branches are
+// matched by $ref, inline ones by position.
+func (d *differ) compareBranches(location, path, keyword string, old, rev []*openapi.Schema, dir direction, depth int) {
+	matched := matchBranches(old, rev)
+
+	for i, branch := range old {
+		if _, ok := matched[i]; !ok {
+			d.narrowed(location, path, fmt.Sprintf("%s branch %s removed", keyword, branchName(branch, i)), dir)
+		}
+	}
+
+	revMatched := make(map[int]bool, len(matched))
+	for _, j := range matched {
+		revMatched[j] = true
+	}
+
+	for j, branch := range rev {
+		if !revMatched[j] {
+			d.widened(location, path, fmt.Sprintf("%s branch %s added", keyword, branchName(branch, j)), dir)
+		}
+	}
+
+	for i := range old {
+		if j, ok := matched[i]; ok {
+			d.compareSchema(location, fmt.Sprintf("%s.%s[%d]", path, keyword, j), old[i], rev[j], dir, depth+1)
+		}
+	}
+}
+
+// compareParts reports allOf parts that were added or removed. Their
+// properties are compared through flattenAllOf.
+func (d *differ) compareParts(location, path string, old, rev []*openapi.Schema, dir direction) {
+	matched := matchBranches(old, rev)
+
+	for i, part := range old {
+		if _, ok := matched[i]; !ok {
+			d.widened(location, path, fmt.Sprintf("allOf part %s removed", branchName(part, i)), dir)
+		}
+	}
+
+	revMatched := make(map[int]bool, len(matched))
+	for _, j := range matched {
+		revMatched[j] = true
+	}
+
+	for j, part := range rev {
+		if !revMatched[j] {
+			d.narrowed(location, path, fmt.Sprintf("allOf part %s added", branchName(part, j)), dir)
+		}
+	}
+}
+
+// matchBranches maps old branches to revised ones, by $ref when a branch
+// has one and by position otherwise.
+func matchBranches(old, rev []*openapi.Schema) map[int]int {
+	matched := make(map[int]int, len(old))
+	taken := make(map[int]bool, len(rev))
+
+	for i, branch := range old {
+		if branch == nil || branch.Ref == "" {
+			continue
+		}
+		for j, candidate := range rev {
+			if !taken[j] && candidate != nil && candidate.Ref == branch.Ref {
+				matched[i], taken[j] = j, true
+				break
+			}
+		}
+	}
+
+	for i, branch := range old {
+		if _, ok := matched[i]; ok || branch == nil || branch.Ref != "" {
+			continue
+		}
+		if i < len(rev) && !taken[i] && rev[i] != nil && rev[i].Ref == "" {
+			matched[i], taken[i] = i, true
+		}
+	}
+
+	return matched
+}
+
+// branchName names a branch by the schema it refers to, or by its position.
+func branchName(s *openapi.Schema, i int) string {
+	if s != nil && s.Ref != "" {
+		return s.Ref[strings.LastIndex(s.Ref, "/")+1:]
+	}
+
+	return fmt.Sprintf("#%d", i)
+}
+
+// compareAdditionalProperties compares which properties beyond the declared
+// ones an object allows: none, those matching a schema, or any.
+func (d *differ) compareAdditionalProperties(location, path string, old, rev *openapi.AdditionalProperties, dir direction, depth int) {
+	oldLevel, revLevel := additionalLevel(old), additionalLevel(rev)
+
+	switch {
+	case revLevel < oldLevel:
+		d.narrowed(location, path, "additionalProperties "+additionalLevels[oldLevel]+" changed to "+additionalLevels[revLevel], dir)
+	case revLevel > oldLevel:
+		d.widened(location, path, "additionalProperties "+additionalLevels[oldLevel]+" changed to "+additionalLevels[revLevel], dir)
+	case revLevel == additionalSchema:
+		d.compareSchema(location, path+".*", old.Schema, rev.Schema, dir, depth+1)
+	}
+}
+
+const (
+	additionalNone = iota
+	additionalSchema
+	additionalAny
+)
+
+var additionalLevels = []string{"not allowed", "restricted to a schema", "allowed"}
+
+func additionalLevel(a *openapi.AdditionalProperties) int {
+	switch {
+	case a == nil:
+		return additionalAny
+	case a.Schema != nil:
+		return additionalSchema
+	case a.Allowed != nil && !*a.Allowed:
+		return additionalNone
+	default:
+		return additionalAny
+	}
+}
+
+func (d *differ) compareEnum(location, path string, old, rev []interface{}, dir direction) {
+	switch {
+	case len(old) == 0 && len(rev) == 0:
+		return
+	case len(old) == 0:
+		d.narrowed(location, path, "enum added", dir)
+		return
+	case len(rev) == 0:
+		d.widened(location, path, "enum removed", dir)
+		return
+	}
+
+	for _, value := range old {
+		if !containsValue(rev, value) {
+			d.narrowed(location, path, fmt.Sprintf("enum value %s removed", formatValue(value)), dir)
+		}
+	}
+
+	for _, value := range rev {
+		if !containsValue(old, value) {
+			d.widened(location, path, fmt.Sprintf("enum value %s added", formatValue(value)), dir)
+		}
+	}
+}
+
+func (d *differ) compareLimits(location, path string, old, rev *openapi.Schema, dir direction) {
+	d.compareUpper(location, path, "maximum", old.Maximum, rev.Maximum, dir)
+	d.compareLower(location, path, "minimum", old.Minimum, rev.Minimum, dir)
+	d.compareUpper(location, path, "maxLength", floatPtr(old.MaxLength), floatPtr(rev.MaxLength), dir)
+	d.compareLower(location, path, "minLength", nonZero(old.MinLength), nonZero(rev.MinLength), dir)
+	d.compareUpper(location, path, "maxItems", floatPtr(old.MaxItems), floatPtr(rev.MaxItems), dir)
+	d.compareLower(location, path, "minItems", nonZero(old.MinItems), nonZero(rev.MinItems), dir)
+
+	switch {
+	case old.Pattern == rev.Pattern:
+	case rev.Pattern == "":
+		d.widened(location, path, "pattern removed", dir)
+	case old.Pattern == "":
+		d.narrowed(location, path, fmt.Sprintf("pattern %s added", rev.Pattern), dir)
+	default:
+		d.narrowed(location, path, fmt.Sprintf("pattern changed from %s to %s", old.Pattern, rev.Pattern), dir)
+	}
+}
+
+// compareUpper compares an upper bound, where nil means there is none.
+func (d *differ) compareUpper(location, path, name string, old, rev *float64, dir direction) {
+	switch {
+	case old == nil && rev == nil:
+	case old == nil:
+		d.narrowed(location, path, fmt.Sprintf("%s %g added", name, *rev), dir)
+	case rev == nil:
+		d.widened(location, path, fmt.Sprintf("%s %g removed", name, *old), dir)
+	case *rev < *old:
+		d.narrowed(location, path, fmt.Sprintf("%s lowered from %g to %g", name, *old, *rev), dir)
+	case *rev > *old:
+		d.widened(location, path, fmt.Sprintf("%s raised from %g to %g", name, *old, *rev), dir)
+	}
+}
+
+// compareLower compares a lower bound, where nil means there is none.
+func (d *differ) compareLower(location, path, name string, old, rev *float64, dir direction) {
+	switch {
+	case old == nil && rev == nil:
+	case old == nil:
+		d.narrowed(location, path, fmt.Sprintf("%s %g added", name, *rev), dir)
+	case rev == nil:
+		d.widened(location, path, fmt.Sprintf("%s %g removed", name, *old), dir)
+	case *rev > *old:
+		d.narrowed(location, path, fmt.Sprintf("%s raised from %g to %g", name, *old, *rev), dir)
+	case *rev < *old:
+		d.widened(location, path, fmt.Sprintf("%s lowered from %g to %g", name, *old, *rev), dir)
+	}
+}
+
+func (d *differ) compareProperties(location, path string, old, rev *openapi.Schema, dir direction, depth int) {
+	for _, name := range sortedKeys(old.Properties) {
+		if _, ok := rev.Properties[name]; ok {
+			continue
+		}
+
+		// Clients lose a value they may rely on. Servers usually ignore
+		// properties they don't know, so sending it is fine.
+		d.add(location, propertyPath(path, name), Removed, "property removed", dir.wideningBreaks())
+	}
+
+	for _, name := range sortedKeys(rev.Properties) {
+		propPath := propertyPath(path, name)
+		required := slices.Contains(rev.Required, name)
+		wasRequired := slices.Contains(old.Required, name)
+
+		oldProp, ok := old.Properties[name]
+		if !ok {
+			if required {
+				d.add(location, propPath, Added, "required property added", dir.narrowingBreaks())
+			} else {
+				d.add(location, propPath, Added, "optional property added", false)
+			}
+			continue
+		}
+
+		switch {
+		case required && !wasRequired:
+			d.narrowed(location, propPath, "became required", dir)
+		case !required && wasRequired:
+			d.widened(location, propPath, "became optional", dir)
+		}
+
+		d.compareSchema(location, propPath, oldProp, rev.Properties[name], dir, depth+1)
+	}
+}
+
+// flattenAllOf merges the properties and required lists of allOf parts into
+// the schema, so composed objects compare like plain ones.
+func flattenAllOf(s *openapi.Schema) *openapi.Schema {
+	if len(s.AllOf) == 0 {
+		return s
+	}
+
+	merged := *s
+	merged.Properties = make(map[string]*openapi.Schema, len(s.Properties))
+	for name, prop := range s.Properties {
+		merged.Properties[name] = prop
+	}
+	merged.Required = slices.Clone(s.Required)
+
+	for _, part := range s.AllOf {
+		if part == nil || part.Circular {
+			continue
+		}
+
+		part = flattenAllOf(part)
+		if merged.Type == "" {
+			merged.Type = part.Type
+		}
+		for name, prop := range part.Properties {
+			if _, ok := merged.Properties[name]; !ok {
+				merged.Properties[name] = prop
+			}
+		}
+		merged.Required = append(merged.Required, part.Required...)
+	}
+
+	return &merged
+}
+
+func schemaType(s *openapi.Schema) string {
+	if s.Type == "" && len(s.Properties) > 0 {
+		return "object"
+	}
+
+	return s.Type
+}
+
+func propertyPath(path, name string) string {
+	return path + "." + name
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if fmt.Sprint(v) == fmt.Sprint(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+
+	return fmt.Sprint(v)
+}
+
+func floatPtr(v *uint64) *float64 {
+	if v == nil {
+		return nil
+	}
+
+	f := float64(*v)
+
+	return &f
+}
+
+// nonZero treats a zero lower bound as none, the loader can't tell them
+// apart.
+func nonZero(v uint64) *float64 {
+	if v == 0 {
+		return nil
+	}
+
+	f := float64(v)
+
+	return &f
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
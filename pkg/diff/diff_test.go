@@ -0,0 +1,473 @@
+package diff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+const baseSpec = `openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: integer, maximum: 100}
+        - name: status
+          in: query
+          schema: {type: string, enum: [available, sold]}
+        - name: X-Trace
+          in: header
+          schema: {type: string}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewPet'
+      responses:
+        '201': {description: created}
+        '400': {description: invalid}
+  /pets/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema: {type: integer}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+    delete:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema: {type: integer}
+      responses:
+        '204': {description: deleted}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id, name]
+      properties:
+        id: {type: integer}
+        name: {type: string}
+        nickname: {type: string}
+        kind: {type: string, enum: [cat, dog]}
+    NewPet:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, maxLength: 50}
+        tag: {type: string}
+`
+
+const revisionSpec = `openapi: 3.0.3
+info: {title: Pets, version: "2.0"}
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          required: true
+          schema: {type: integer, maximum: 50}
+        - name: status
+          in: query
+          schema: {type: string, enum: [available, pending]}
+        - name: x-trace
+          in: header
+          schema: {type: string}
+        - name: sort
+          in: query
+          schema: {type: string}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/NewPet'
+      responses:
+        '201': {description: created}
+  /pets/{petId}:
+    get:
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema: {type: string}
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+        '404': {description: not found}
+  /owners:
+    get:
+      responses:
+        '200': {description: ok}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [id]
+      properties:
+        id: {type: integer}
+        name: {type: string}
+        kind: {type: string, enum: [cat, dog, bird]}
+        age: {type: integer}
+    NewPet:
+      type: object
+      required: [name, kind]
+      properties:
+        name: {type: string, maxLength: 100}
+        kind: {type: string}
+`
+
+func loadSpec(t *testing.T, data string) *openapi.Spec {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := openapi.LoadFromFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return spec
+}
+
+func TestCompare(t *testing.T) {
+	changes := Compare(loadSpec(t, baseSpec), loadSpec(t, revisionSpec))
+
+	expected := []Change{
+		{Operation: "DELETE /pets/{id}", Kind: Removed, Message: "operation removed", Breaking: true},
+		{Operation: "GET /owners", Kind: Added, Message: "operation added"},
+		{Operation: "GET /pets", Location: "query parameter limit", Kind: Changed, Message: "became required", Breaking: true},
+		{Operation: "GET /pets", Location: "query parameter limit", Path: "$", Kind: Changed, Message: "maximum lowered from 100 to 50", Breaking: true},
+		{Operation: "GET /pets", Location: "query parameter sort", Kind: Added, Message: "optional parameter added"},
+		{Operation: "GET /pets", Location: "query parameter status", Path: "$", Kind: Changed, Message: `enum value "sold" removed`, Breaking: true},
+		{Operation: "GET /pets", Location: "query parameter status", Path: "$", Kind: Changed, Message: `enum value "pending" added`},
+		{Operation: "GET /pets", Location: "response 200 application/json", Path: "$[*].nickname", Kind: Removed, Message: "property removed", Breaking: true},
+		{Operation: "GET /pets", Location: "response 200 application/json", Path: "$[*].age", Kind: Added, Message: "optional property added"},
+		{Operation: "GET /pets", Location: "response 200 application/json", Path: "$[*].kind", Kind: Changed, Message: `enum value "bird" added`, Breaking: true},
+		{Operation: "GET /pets", Location: "response 200 application/json", Path: "$[*].name", Kind: Changed, Message: "became optional", Breaking: true},
+		{Operation: "GET /pets/{petId}", Location: "path parameter petId", Kind: Changed, Message: "renamed from id"},
+		{Operation: "GET /pets/{petId}", Location: "path parameter petId", Path: "$", Kind: Changed, Message: "type changed from integer to string", Breaking: true},
+		{Operation: "GET /pets/{petId}", Location: "response 200 application/json", Path: "$.nickname", Kind: Removed, Message: "property removed", Breaking: true},
+		{Operation: "GET /pets/{petId}", Location: "response 200 application/json", Path: "$.age", Kind: Added, Message: "optional property added"},
+		{Operation: "GET /pets/{petId}", Location: "response 200 application/json", Path: "$.kind", Kind: Changed, Message: `enum value "bird" added`, Breaking: true},
+		{Operation: "GET /pets/{petId}", Location: "response 200 application/json", Path: "$.name", Kind: Changed, Message: "became optional", Breaking: true},
+		{Operation: "GET /pets/{petId}", Location: "response 404", Kind: Added, Message: "response added"},
+		{Operation: "POST /pets", Location: "request body application/json", Path: "$.tag", Kind: Removed, Message: "property removed"},
+		{Operation: "POST /pets", Location: "request body application/json", Path: "$.kind", Kind: Added, Message: "required property added", Breaking: true},
+		{Operation: "POST /pets", Location: "request body application/json", Path: "$.name", Kind: Changed, Message: "maxLength raised from 50 to 100"},
+		{Operation: "POST /pets", Location: "response 400", Kind: Removed, Message: "response removed"},
+	}
+
+	if len(changes) != len(expected) {
+		for _, c := range changes {
+			t.Logf("%+v", c)
+		}
+		t.Fatalf("Compare() returned %d changes, want %d", len(changes), len(expected))
+	}
+
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("change %d = %+v, want %+v", i, changes[i], expected[i])
+		}
+	}
+
+	if got := Breaking(changes); got != 12 {
+		t.Errorf("Breaking() = %d, want 12", got)
+	}
+}
+
+func TestCompareUnchanged(t *testing.T) {
+	if changes := Compare(loadSpec(t, baseSpec), loadSpec(t, baseSpec)); len(changes) != 0 {
+		t.Errorf("Compare() of the same spec = %+v, want no changes", changes)
+	}
+}
+
+func TestCompareAllOf(t *testing.T) {
+	spec := func(required string) string {
+		return `openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              allOf:
+                - $ref: '#/components/schemas/Base'
+                - type: object
+                  required: [` + required + `]
+                  properties:
+                    name: {type: string}
+      responses:
+        '201': {description: created}
+components:
+  schemas:
+    Base:
+      type: object
+      properties:
+        id: {type: integer}
+`
+	}
+
+	changes := Compare(loadSpec(t, spec("name")), loadSpec(t, spec("name, id")))
+
+	expected := Change{Operation: "POST /pets", Location: "request body application/json", Path: "$.id", Kind: Changed, Message: "became required", Breaking: true}
+	if len(changes) != 1 || changes[0] != expected {
+		t.Errorf("Compare() = %+v, want %+v", changes, expected)
+	}
+}
+
+func TestCompareDirection(t *testing.T) {
+	spec := func(paramSchema, responseType, params string) string {
+		return `openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema: ` + paramSchema + params + `
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema: {type: ` + responseType + `}
+`
+	}
+
+	const removable = `
+        - name: tag
+          in: query
+          schema: {type: string}
+        - name: X-Tenant
+          in: header
+          required: true
+          schema: {type: string}`
+
+	tests := []struct {
+		name     string
+		old, rev string
+		expected []Change
+	}{
+		{
+			name: "parameters removed",
+			old:  spec("{type: integer}", "integer", removable),
+			rev:  spec("{type: integer}", "integer", ""),
+			expected: []Change{
+				{Operation: "GET /pets", Location: "header parameter X-Tenant", Kind: Removed, Message: "parameter removed"},
+				{Operation: "GET /pets", Location: "query parameter tag", Kind: Removed, Message: "parameter removed"},
+			},
+		},
+		{
+			name: "integer widened to number",
+			old:  spec("{type: integer}", "integer", ""),
+			rev:  spec("{type: number}", "number", ""),
+			expected: []Change{
+				{Operation: "GET /pets", Location: "query parameter limit", Path: "$", Kind: Changed, Message: "type changed from integer to number"},
+				{Operation: "GET /pets", Location: "response 200 application/json", Path: "$", Kind: Changed, Message: "type changed from integer to number", Breaking: true},
+			},
+		},
+		{
+			name: "number narrowed to integer",
+			old:  spec("{type: number}", "number", ""),
+			rev:  spec("{type: integer}", "integer", ""),
+			expected: []Change{
+				{Operation: "GET /pets", Location: "query parameter limit", Path: "$", Kind: Changed, Message: "type changed from number to integer", Breaking: true},
+				{Operation: "GET /pets", Location: "response 200 application/json", Path: "$", Kind: Changed, Message: "type changed from number to integer"},
+			},
+		},
+		{
+			name: "number constraints still compare",
+			old:  spec("{type: integer}", "integer", ""),
+			rev:  spec("{type: number, maximum: 10}", "integer", ""),
+			expected: []Change{
+				{Operation: "GET /pets", Location: "query parameter limit", Path: "$", Kind: Changed, Message: "type changed from integer to number"},
+				{Operation: "GET /pets", Location: "query parameter limit", Path: "$", Kind: Changed, Message: "maximum 10 added", Breaking: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Compare(loadSpec(t, tt.old), loadSpec(t, tt.rev))
+
+			if len(changes) != len(tt.expected) {
+				t.Fatalf("Compare() = %+v, want %+v", changes, tt.expected)
+			}
+
+			for i := range tt.expected {
+				if changes[i] != tt.expected[i] {
+					t.Errorf("change %d = %+v, want %+v", i, changes[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompareComposition(t *testing.T) {
+	spec := func(request, response string) string {
+		return `openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+` + request + `
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+` + strings.ReplaceAll("  "+response, "\n", "\n  ") + `
+components:
+  schemas:
+    Cat:
+      type: object
+      properties:
+        name: {type: string, maxLength: 50}
+    Dog:
+      type: object
+      properties:
+        name: {type: string}
+    Bird:
+      type: object
+      properties:
+        wings: {type: integer}
+`
+	}
+
+	const (
+		catOrDog = `              oneOf:
+                - $ref: '#/components/schemas/Cat'
+                - $ref: '#/components/schemas/Dog'`
+		catOrBird = `              oneOf:
+                - $ref: '#/components/schemas/Cat'
+                - $ref: '#/components/schemas/Bird'`
+		anyLabel = `              anyOf:
+                - {type: string, maxLength: 10}
+                - {type: integer}`
+		anyLongLabel = `              anyOf:
+                - {type: string, maxLength: 5}
+                - {type: integer}`
+		openMap = `              type: object
+              additionalProperties: {type: string}`
+		closedMap = `              type: object
+              additionalProperties: false`
+		intMap = `              type: object
+              additionalProperties: {type: integer}`
+		catOnly = `              allOf:
+                - $ref: '#/components/schemas/Cat'`
+		catAndDog = `              allOf:
+                - $ref: '#/components/schemas/Cat'
+                - $ref: '#/components/schemas/Dog'`
+	)
+
+	tests := []struct {
+		name     string
+		old, rev string
+		expected []Change
+	}{
+		{
+			name: "oneOf branch replaced",
+			old:  spec(catOrDog, catOrDog),
+			rev:  spec(catOrBird, catOrBird),
+			expected: []Change{
+				{Operation: "POST /pets", Location: "request body application/json", Path: "$", Kind: Changed, Message: "oneOf branch Dog removed", Breaking: true},
+				{Operation: "POST /pets", Location: "request body application/json", Path: "$", Kind: Changed, Message: "oneOf branch Bird added"},
+				{Operation: "POST /pets", Location: "response 200 application/json", Path: "$", Kind: Changed, Message: "oneOf branch Dog removed"},
+				{Operation: "POST /pets", Location: "response 200 application/json", Path: "$", Kind: Changed, Message: "oneOf branch Bird added", Breaking: true},
+			},
+		},
+		{
+			name: "anyOf branch narrowed",
+			old:  spec(anyLabel, anyLabel),
+			rev:  spec(anyLongLabel, anyLongLabel),
+			expected: []Change{
+				{Operation: "POST /pets", Location: "request body application/json", Path: "$.anyOf[0]", Kind: Changed, Message: "maxLength lowered from 10 to 5", Breaking: true},
+				{Operation: "POST /pets", Location: "response 200 application/json", Path: "$.anyOf[0]", Kind: Changed, Message: "maxLength lowered from 10 to 5"},
+			},
+		},
+		{
+			name: "additionalProperties",
+			old:  spec(openMap, openMap),
+			rev:  spec(closedMap, intMap),
+			expected: []Change{
+				{Operation: "POST /pets", Location: "request body application/json", Path: "$", Kind: Changed, Message: "additionalProperties restricted to a schema changed to not allowed", Breaking: true},
+				{Operation: "POST /pets", Location: "response 200 application/json", Path: "$.*", Kind: Changed, Message: "type changed from string to integer", Breaking: true},
+			},
+		},
+		{
+			name: "allOf part added",
+			old:  spec(catOnly, catAndDog),
+			rev:  spec(catAndDog, catOnly),
+			expected: []Change{
+				{Operation: "POST /pets", Location: "request body application/json", Path: "$", Kind: Changed, Message: "allOf part Dog added", Breaking: true},
+				{Operation: "POST /pets", Location: "response 200 application/json", Path: "$", Kind: Changed, Message: "allOf part Dog removed", Breaking: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := Compare(loadSpec(t, tt.old), loadSpec(t, tt.rev))
+
+			if len(changes) != len(tt.expected) {
+				t.Fatalf("Compare() = %+v, want %+v", changes, tt.expected)
+			}
+
+			for i := range tt.expected {
+				if changes[i] != tt.expected[i] {
+					t.Errorf("change %d = %+v, want %+v", i, changes[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
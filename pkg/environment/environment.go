@@ -0,0 +1,107 @@
+// Package environment loads named sets of variables from the user's config
+// file and substitutes {{name}} placeholders in request values, so the same
+// operations can be sent to dev, staging and prod.
+package environment
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/oasdiff/yaml"
+)
+
+// BaseURLVar is the variable that, when set, replaces the spec's servers.
+const BaseURLVar = "baseUrl"
+
+var placeholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// Config is the user config file. Environments maps an environment name to
+// its variables.
+type Config struct {
+	Environments map[string]map[string]string `json:"environments"`
+}
+
+// DefaultPath returns the config file location, tapi/config.yaml under the
+// user config directory ($XDG_CONFIG_HOME on Linux).
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+
+	return filepath.Join(dir, "tapi", "config.yaml"), nil
+}
+
+// Load reads the config file at path. A missing file is an empty config.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
+
+// LoadDefault reads the config file at DefaultPath.
+func LoadDefault() (*Config, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(path)
+}
+
+// Names lists the environments in a stable order.
+func (c *Config) Names() []string {
+	names := make([]string, 0, len(c.Environments))
+	for name := range c.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Get returns the variables of the named environment.
+func (c *Config) Get(name string) (map[string]string, error) {
+	vars, ok := c.Environments[name]
+	if !ok {
+		if len(c.Environments) == 0 {
+			return nil, fmt.Errorf("environment %q not found, no environments are configured", name)
+		}
+		return nil, fmt.Errorf("environment %q not found, available: %s", name, strings.Join(c.Names(), ", "))
+	}
+
+	return vars, nil
+}
+
+// Interpolate replaces {{name}} placeholders with values from vars. Unknown
+// placeholders are left as they are.
+func Interpolate(s string, vars map[string]string) string {
+	if len(vars) == 0 || !strings.Contains(s, "{{") {
+		return s
+	}
+
+	return placeholder.ReplaceAllStringFunc(s, func(match string) string {
+		name := placeholder.FindStringSubmatch(match)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return match
+	})
+}
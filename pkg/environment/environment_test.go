@@ -0,0 +1,106 @@
+package environment
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	data := `
+environments:
+  dev:
+    baseUrl: http://localhost:8080
+    token: dev-token
+  prod:
+    baseUrl: https://api.example.com
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if got := cfg.Names(); !reflect.DeepEqual(got, []string{"dev", "prod"}) {
+		t.Errorf("Names() = %v", got)
+	}
+
+	vars, err := cfg.Get("dev")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if vars["token"] != "dev-token" || vars[BaseURLVar] != "http://localhost:8080" {
+		t.Errorf("Get() = %v", vars)
+	}
+
+	if _, err := cfg.Get("staging"); err == nil || err.Error() != `environment "staging" not found, available: dev, prod` {
+		t.Errorf("Get() error = %v", err)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(cfg.Names()) != 0 {
+		t.Errorf("missing file should give an empty config, got %v", cfg.Names())
+	}
+
+	if _, err := cfg.Get("dev"); err == nil {
+		t.Error("Get() should fail without environments")
+	}
+}
+
+func TestLoadDefault(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "tapi"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tapi", "config.yaml"), []byte("environments: {dev: {id: '1'}}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadDefault()
+	if err != nil {
+		t.Fatalf("LoadDefault() error = %v", err)
+	}
+
+	if cfg.Environments["dev"]["id"] != "1" {
+		t.Errorf("LoadDefault() = %+v", cfg)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	vars := map[string]string{"token": "abc", "tenantId": "42", "api.host": "example.com"}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "single", input: "Bearer {{token}}", expected: "Bearer abc"},
+		{name: "spaces", input: "{{ tenantId }}", expected: "42"},
+		{name: "several", input: `{"tenant": {{tenantId}}, "token": "{{token}}"}`, expected: `{"tenant": 42, "token": "abc"}`},
+		{name: "dotted name", input: "https://{{api.host}}", expected: "https://example.com"},
+		{name: "unknown kept", input: "{{missing}}", expected: "{{missing}}"},
+		{name: "single braces untouched", input: "/pets/{petId}", expected: "/pets/{petId}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Interpolate(tt.input, vars); got != tt.expected {
+				t.Errorf("Interpolate() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
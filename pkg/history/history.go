@@ -0,0 +1,356 @@
+// Package history persists sent requests and their responses so they can be
+// browsed and replayed later. Entries are appended as JSON lines to a file
+// under the XDG data directory, with credentials redacted.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+// MaxBodySize caps each stored request and response body, longer bodies are
+// truncated and the entry is marked Truncated.
+const MaxBodySize = 64 << 10
+
+// MaxSize caps the history file. Once it grows past it the oldest entries
+// are dropped.
+const MaxSize = 32 << 20
+
+// Redacted is stored in place of credentials.
+const Redacted = "REDACTED"
+
+// authHeaders carry credentials whatever the spec says.
+var authHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// authResponseHeaders carry credentials in responses, on top of authHeaders.
+var authResponseHeaders = []string{"Set-Cookie"}
+
+// Entry is a single sent request and the response it got.
+type Entry struct {
+	Time time.Time `json:"time"`
+	// Spec is the title of the spec the operation belongs to.
+	Spec        string `json:"spec,omitempty"`
+	OperationID string `json:"operationId,omitempty"`
+	// Operation is "METHOD /path" with the path template, e.g. "GET /pets/{id}".
+	Operation   string            `json:"operation"`
+	Method      string            `json:"method"`
+	URL         string            `json:"url"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        string            `json:"body,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	// Inputs and InputBody are the values as they were typed, before
+	// environment variables were filled in, so a replay can reuse them.
+	Inputs    []Input `json:"inputs,omitempty"`
+	InputBody string  `json:"inputBody,omitempty"`
+
+	StatusCode      int           `json:"statusCode,omitempty"`
+	Status          string        `json:"status,omitempty"`
+	Duration        time.Duration `json:"duration"`
+	ResponseHeaders http.Header   `json:"responseHeaders,omitempty"`
+	ResponseBody    string        `json:"responseBody,omitempty"`
+	Truncated       bool          `json:"truncated,omitempty"`
+	Error           string        `json:"error,omitempty"`
+}
+
+// Input is a parameter value entered for a request.
+type Input struct {
+	Name  string `json:"name"`
+	In    string `json:"in"`
+	Value string `json:"value"`
+}
+
+// NewEntry records resp and the request it was sent for, without the
+// values of auth headers. Callers fill in the spec, operation and inputs,
+// then Redact the credentials of the spec's security schemes.
+func NewEntry(resp request.ResponseMsg) Entry {
+	req := resp.Request
+
+	e := Entry{
+		Time:            time.Now(),
+		Operation:       req.Method + " " + req.Path,
+		Method:          req.Method,
+		URL:             req.URL(),
+		Headers:         maps.Clone(req.Headers),
+		Body:            req.Body,
+		StatusCode:      resp.StatusCode,
+		Status:          resp.Status,
+		Duration:        resp.Duration,
+		ResponseHeaders: resp.Headers.Clone(),
+		ResponseBody:    resp.Body,
+	}
+
+	if req.Body != "" {
+		e.ContentType = req.ContentType
+	}
+
+	e.truncate()
+
+	if resp.Error != nil {
+		e.Error = resp.Error.Error()
+	}
+
+	e.Redact(nil)
+
+	return e
+}
+
+// truncate cuts the bodies down to MaxBodySize.
+func (e *Entry) truncate() {
+	for _, body := range []*string{&e.Body, &e.InputBody, &e.ResponseBody} {
+		if len(*body) > MaxBodySize {
+			*body = (*body)[:MaxBodySize]
+			e.Truncated = true
+		}
+	}
+}
+
+// Redact replaces the values of auth headers, in the request and the
+// response, and of the headers, query parameters and cookies apiKey schemes
+// send keys in, with Redacted so they aren't written to disk.
+func (e *Entry) Redact(schemes map[string]openapi.SecurityScheme) {
+	secret := func(name, in string) bool {
+		if in == "header" && slices.ContainsFunc(authHeaders, func(h string) bool { return strings.EqualFold(h, name) }) {
+			return true
+		}
+
+		for _, scheme := range schemes {
+			if scheme.Type != "apiKey" || scheme.In != in {
+				continue
+			}
+			// Header names are case-insensitive, query and cookie names aren't.
+			if scheme.ParamName == name || in == "header" && strings.EqualFold(scheme.ParamName, name) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for name := range e.Headers {
+		if secret(name, "header") {
+			e.Headers[name] = Redacted
+		}
+	}
+
+	for name, values := range e.ResponseHeaders {
+		if secret(name, "header") || slices.ContainsFunc(authResponseHeaders, func(h string) bool { return strings.EqualFold(h, name) }) {
+			for i := range values {
+				values[i] = Redacted
+			}
+		}
+	}
+
+	for i, input := range e.Inputs {
+		if input.Value != "" && secret(input.Name, input.In) {
+			e.Inputs[i].Value = Redacted
+		}
+	}
+
+	if u, err := url.Parse(e.URL); err == nil && u.RawQuery != "" {
+		query := u.Query()
+
+		redacted := false
+		for name := range query {
+			if secret(name, "query") {
+				query.Set(name, Redacted)
+				redacted = true
+			}
+		}
+
+		if redacted {
+			u.RawQuery = query.Encode()
+			e.URL = u.String()
+		}
+	}
+}
+
+// Response rebuilds the response message to show a past response again.
+func (e Entry) Response() request.ResponseMsg {
+	resp := request.ResponseMsg{
+		StatusCode: e.StatusCode,
+		Status:     e.Status,
+		Headers:    e.ResponseHeaders,
+		Body:       e.ResponseBody,
+		Duration:   e.Duration,
+	}
+
+	if e.Error != "" {
+		resp.Error = errors.New(e.Error)
+	}
+
+	return resp
+}
+
+// Input returns the value entered for the parameter, if any.
+func (e Entry) Input(name, in string) (string, bool) {
+	for _, input := range e.Inputs {
+		if input.Name == name && input.In == in {
+			return input.Value, true
+		}
+	}
+
+	return "", false
+}
+
+type Store struct {
+	path    string
+	maxSize int64
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path, maxSize: MaxSize}
+}
+
+// DefaultPath returns tapi/history.jsonl under $XDG_DATA_HOME, falling back
+// to ~/.local/share.
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate data directory: %w", err)
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+
+	return filepath.Join(dir, "tapi", "history.jsonl"), nil
+}
+
+// OpenDefault returns a store at DefaultPath.
+func OpenDefault() (*Store, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStore(path), nil
+}
+
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Add appends an entry to the history file, creating it when needed, and
+// drops the oldest entries once the file is larger than MaxSize. Bodies
+// longer than MaxBodySize are truncated.
+func (s *Store) Add(e Entry) error {
+	e.truncate()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open history: %w", err)
+	}
+
+	_, err = f.Write(append(data, '\n'))
+	_ = f.Close()
+
+	if err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+
+	return s.trim()
+}
+
+// trim keeps the newest entries that fit in three quarters of the maximum
+// size, so the file isn't rewritten on every request once it's full. The
+// newest entry is kept even when it alone is larger.
+func (s *Store) trim() error {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to check history size: %w", err)
+	}
+
+	if info.Size() <= s.maxSize {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+
+	keep := s.maxSize * 3 / 4
+	lines := bytes.SplitAfter(data, []byte("\n"))
+
+	start := len(lines)
+	size := int64(0)
+	for start > 0 && (size == 0 || size+int64(len(lines[start-1])) <= keep) {
+		start--
+		size += int64(len(lines[start]))
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, bytes.Join(lines[start:], nil), 0o600); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+
+	return nil
+}
+
+// List returns all entries, newest first. Lines that can't be decoded are
+// skipped so a single corrupt entry doesn't hide the rest.
+func (s *Store) List() ([]Entry, error) {
+	f, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	entries := make([]Entry, 0)
+
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var e Entry
+			if json.Unmarshal(line, &e) == nil {
+				entries = append(entries, e)
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,246 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+func TestNewEntry(t *testing.T) {
+	resp := request.ResponseMsg{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Headers:    http.Header{"Content-Type": []string{"application/json"}},
+		Body:       strings.Repeat("a", MaxBodySize+10),
+		Duration:   120 * time.Millisecond,
+		Request: request.Request{
+			BaseURL:     "https://api.example.com",
+			Path:        "/pets/{id}",
+			Method:      "PUT",
+			PathParams:  map[string]string{"id": "1"},
+			Headers:     map[string]string{"X-Trace": "abc"},
+			Body:        `{"name":"rex"}`,
+			ContentType: "application/json",
+		},
+	}
+
+	e := NewEntry(resp)
+
+	if e.Operation != "PUT /pets/{id}" || e.URL != "https://api.example.com/pets/1" {
+		t.Errorf("operation = %q, URL = %q", e.Operation, e.URL)
+	}
+
+	if e.Headers["X-Trace"] != "abc" || e.Body != `{"name":"rex"}` || e.ContentType != "application/json" {
+		t.Errorf("request details = %+v", e)
+	}
+
+	if !e.Truncated || len(e.ResponseBody) != MaxBodySize {
+		t.Errorf("response body should be truncated to %d bytes, got %d", MaxBodySize, len(e.ResponseBody))
+	}
+
+	if e.Duration != 120*time.Millisecond || e.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, duration = %v", e.StatusCode, e.Duration)
+	}
+
+	upload := NewEntry(request.ResponseMsg{Request: request.Request{Method: "POST", Path: "/files", Body: strings.Repeat("b", MaxBodySize+1)}})
+	if !upload.Truncated || len(upload.Body) != MaxBodySize {
+		t.Errorf("request body should be truncated to %d bytes, got %d", MaxBodySize, len(upload.Body))
+	}
+
+	failed := NewEntry(request.ResponseMsg{Error: errors.New("request failed: refused"), Request: request.Request{Method: "GET", Path: "/"}})
+	if got := failed.Response().Error; got == nil || got.Error() != "request failed: refused" {
+		t.Errorf("Response().Error = %v", got)
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "tapi", "history.jsonl"))
+
+	entries, err := store.List()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("List() on a missing file = %v, %v", entries, err)
+	}
+
+	for _, op := range []string{"GET /pets", "POST /pets"} {
+		e := Entry{
+			Operation: op,
+			Inputs:    []Input{{Name: "limit", In: "query", Value: "{{limit}}"}},
+			Duration:  time.Second,
+		}
+		if err := store.Add(e); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	// A corrupt line must not hide the other entries.
+	f, err := os.OpenFile(store.Path(), os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("{not json\n")
+	_ = f.Close()
+
+	entries, err = store.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if len(entries) != 2 || entries[0].Operation != "POST /pets" || entries[1].Operation != "GET /pets" {
+		t.Fatalf("List() = %+v, want newest first", entries)
+	}
+
+	if value, ok := entries[0].Input("limit", "query"); !ok || value != "{{limit}}" {
+		t.Errorf("Input() = %q, %v", value, ok)
+	}
+
+	if _, ok := entries[0].Input("limit", "header"); ok {
+		t.Error("Input() should match the parameter location too")
+	}
+}
+
+func TestRedact(t *testing.T) {
+	headers := map[string]string{"Authorization": "Bearer tok", "X-Api-Key": "key", "X-Trace": "abc"}
+	responseHeaders := http.Header{
+		"Set-Cookie":    {"session=abc; HttpOnly", "theme=dark"},
+		"Authorization": {"Bearer refreshed"},
+		"X-Api-Key":     {"issued"},
+		"Content-Type":  {"application/json"},
+	}
+
+	e := NewEntry(request.ResponseMsg{Headers: responseHeaders, Request: request.Request{
+		BaseURL: "https://api.example.com",
+		Path:    "/pets",
+		Method:  "GET",
+		Query:   url.Values{"api_key": {"secret"}, "limit": {"10"}},
+		Headers: headers,
+	}})
+	e.Inputs = []Input{
+		{Name: "api_key", In: "query", Value: "secret"},
+		{Name: "session", In: "cookie", Value: "abc"},
+		{Name: "session", In: "query", Value: "1"},
+		{Name: "limit", In: "query", Value: "10"},
+	}
+
+	if e.Headers["Authorization"] != Redacted || headers["Authorization"] != "Bearer tok" {
+		t.Errorf("NewEntry() should redact a copy of the auth headers, got %v and %v", e.Headers, headers)
+	}
+
+	if got := e.ResponseHeaders["Set-Cookie"]; len(got) != 2 || got[0] != Redacted || got[1] != Redacted {
+		t.Errorf("Set-Cookie = %v, want every cookie redacted", got)
+	}
+	if e.ResponseHeaders.Get("Authorization") != Redacted || responseHeaders.Get("Set-Cookie") != "session=abc; HttpOnly" {
+		t.Errorf("NewEntry() should redact a copy of the response auth headers, got %v and %v", e.ResponseHeaders, responseHeaders)
+	}
+
+	e.Redact(map[string]openapi.SecurityScheme{
+		"key":     {Type: "apiKey", In: "header", ParamName: "X-API-Key"},
+		"query":   {Type: "apiKey", In: "query", ParamName: "api_key"},
+		"session": {Type: "apiKey", In: "cookie", ParamName: "session"},
+		"bearer":  {Type: "http", Scheme: "bearer"},
+	})
+
+	if e.Headers["X-Api-Key"] != Redacted || e.Headers["X-Trace"] != "abc" {
+		t.Errorf("Headers = %v", e.Headers)
+	}
+
+	if e.ResponseHeaders.Get("X-Api-Key") != Redacted || e.ResponseHeaders.Get("Content-Type") != "application/json" {
+		t.Errorf("ResponseHeaders = %v", e.ResponseHeaders)
+	}
+
+	if e.URL != "https://api.example.com/pets?api_key="+Redacted+"&limit=10" {
+		t.Errorf("URL = %q", e.URL)
+	}
+
+	want := []string{Redacted, Redacted, "1", "10"}
+	for i, input := range e.Inputs {
+		if input.Value != want[i] {
+			t.Errorf("input %s in %s = %q, want %q", input.Name, input.In, input.Value, want[i])
+		}
+	}
+}
+
+func TestStoreTrim(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	store.maxSize = 1000
+
+	for i := 0; i < 20; i++ {
+		if err := store.Add(Entry{Operation: fmt.Sprintf("GET /pets/%d", i)}); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	info, err := os.Stat(store.Path())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > store.maxSize {
+		t.Errorf("history size = %d, want at most %d", info.Size(), store.maxSize)
+	}
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) == 0 || len(entries) == 20 || entries[0].Operation != "GET /pets/19" {
+		t.Errorf("List() = %d entries, newest %+v, want the oldest dropped", len(entries), entries)
+	}
+}
+
+func TestStoreAddLimitsSize(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	store.maxSize = 1000
+
+	if err := store.Add(Entry{Operation: "GET /pets"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	// Larger than the whole file may be, it still replaces the older entries.
+	large := Entry{Operation: "POST /files", Body: strings.Repeat("b", 2000), InputBody: strings.Repeat("i", MaxBodySize+1)}
+	if err := store.Add(large); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 || entries[0].Operation != "POST /files" {
+		t.Fatalf("List() = %d entries, want only the newest", len(entries))
+	}
+
+	if !entries[0].Truncated || len(entries[0].InputBody) != MaxBodySize || len(entries[0].Body) != 2000 {
+		t.Errorf("entry truncated = %v, input body %d bytes, body %d bytes", entries[0].Truncated, len(entries[0].InputBody), len(entries[0].Body))
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dir)
+
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath() error = %v", err)
+	}
+
+	if path != filepath.Join(dir, "tapi", "history.jsonl") {
+		t.Errorf("DefaultPath() = %q", path)
+	}
+
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("HOME", dir)
+
+	if path, _ := DefaultPath(); path != filepath.Join(dir, ".local", "share", "tapi", "history.jsonl") {
+		t.Errorf("DefaultPath() without XDG_DATA_HOME = %q", path)
+	}
+}
@@ -0,0 +1,69 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/oasdiff/yaml"
+)
+
+// ConfigFile is the config looked up in the working directory when none is
+// given.
+const ConfigFile = ".tapi-lint.yaml"
+
+// Config sets the severity of rules by name; "off" disables a rule. Rules
+// that aren't listed keep their default severity.
+type Config struct {
+	Rules map[string]Severity `json:"rules"`
+}
+
+// LoadConfig reads the config file at path. An empty path reads ConfigFile
+// when it exists and is the default config otherwise.
+func LoadConfig(path string) (*Config, error) {
+	if path == "" {
+		cfg, err := LoadConfig(ConfigFile)
+		if errors.Is(err, fs.ErrNotExist) {
+			return &Config{}, nil
+		}
+		return cfg, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lint config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse lint config %s: %w", path, err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid lint config %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
+
+func (c *Config) validate() error {
+	for name, severity := range c.Rules {
+		if _, ok := findRule(name); !ok {
+			return fmt.Errorf("unknown rule %q", name)
+		}
+		if !severity.valid() {
+			return fmt.Errorf("rule %q has severity %q, want error, warning, info or off", name, severity)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) severity(r Rule) Severity {
+	if severity, ok := c.Rules[r.Name]; ok {
+		return severity
+	}
+
+	return r.Severity
+}
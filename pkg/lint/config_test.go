@@ -0,0 +1,93 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]Severity
+		wantErr string
+	}{
+		{
+			name: "severities",
+			data: "rules:\n  path-kebab-case: error\n  parameter-description: off\n",
+			want: map[string]Severity{"path-kebab-case": Error, "parameter-description": Off},
+		},
+		{
+			name: "empty",
+			data: "",
+		},
+		{
+			name:    "unknown rule",
+			data:    "rules:\n  no-such-rule: error\n",
+			wantErr: `unknown rule "no-such-rule"`,
+		},
+		{
+			name:    "unknown severity",
+			data:    "rules:\n  path-kebab-case: fatal\n",
+			wantErr: `rule "path-kebab-case" has severity "fatal"`,
+		},
+		{
+			name:    "malformed",
+			data:    "rules: [",
+			wantErr: "failed to parse lint config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "lint.yaml")
+			if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := LoadConfig(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+
+			if len(cfg.Rules) != len(tt.want) {
+				t.Fatalf("Rules = %v, want %v", cfg.Rules, tt.want)
+			}
+			for name, severity := range tt.want {
+				if cfg.Rules[name] != severity {
+					t.Errorf("Rules[%q] = %q, want %q", name, cfg.Rules[name], severity)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfigDefault(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	cfg, err := LoadConfig("")
+	if err != nil || len(cfg.Rules) != 0 {
+		t.Fatalf("LoadConfig() without a config file = %+v, %v", cfg, err)
+	}
+
+	if err := os.WriteFile(ConfigFile, []byte("rules:\n  example-schema: warning\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err = LoadConfig("")
+	if err != nil || cfg.Rules["example-schema"] != Warning {
+		t.Fatalf("LoadConfig() with %s = %+v, %v", ConfigFile, cfg, err)
+	}
+
+	if _, err := LoadConfig("missing.yaml"); err == nil {
+		t.Error("Expected error for a missing config file")
+	}
+}
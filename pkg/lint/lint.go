@@ -0,0 +1,119 @@
+// Package lint checks a spec against style rules that go beyond what makes it
+// valid, like operations without an operationId or examples that don't match
+// their schemas.
+package lint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+	Info    Severity = "info"
+	// Off disables a rule.
+	Off Severity = "off"
+)
+
+func (s Severity) valid() bool {
+	switch s {
+	case Error, Warning, Info, Off:
+		return true
+	}
+	return false
+}
+
+// Rule is a built-in check. Severity is what it reports at unless the config
+// says otherwise.
+type Rule struct {
+	Name        string
+	Description string
+	Severity    Severity
+	check       func(d *document, report reportFunc)
+}
+
+// reportFunc records an issue at the path of keys and indexes in the
+// document, like "paths", "/pets", "get".
+type reportFunc func(path []string, format string, args ...interface{})
+
+// Issue is a place where the spec breaks a rule.
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// Pointer is the JSON pointer to the offending part of the document.
+	Pointer string `json:"pointer"`
+	openapi.Location
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s %s: %s", i.Location, i.Severity, i.Rule, i.Message)
+}
+
+// document is what rules check: the loaded model, and the source text for
+// what the model doesn't keep, like $refs.
+type document struct {
+	*openapi3.T
+	source *openapi.Source
+}
+
+func findRule(name string) (Rule, bool) {
+	for _, r := range rules {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Lint checks spec with the rules cfg enables. The spec should be loaded with
+// openapi.WithoutExampleValidation so the example rule gets to report bad
+// examples, and source is the text it was loaded from. Issues are sorted by
+// location.
+func Lint(spec *openapi.Spec, source *openapi.Source, cfg *Config) []Issue {
+	d := &document{T: spec.Document(), source: source}
+
+	var issues []Issue
+
+	for _, r := range rules {
+		severity := cfg.severity(r)
+		if severity == Off {
+			continue
+		}
+
+		r.check(d, func(path []string, format string, args ...interface{}) {
+			issues = append(issues, Issue{
+				Rule:     r.Name,
+				Severity: severity,
+				Message:  fmt.Sprintf(format, args...),
+				Pointer:  source.Pointer(path...),
+				Location: source.Locate(path...),
+			})
+		})
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Line != issues[j].Line {
+			return issues[i].Line < issues[j].Line
+		}
+		return issues[i].Column < issues[j].Column
+	})
+
+	return issues
+}
+
+// Count returns the number of issues at each severity.
+func Count(issues []Issue) map[Severity]int {
+	counts := make(map[Severity]int)
+	for _, issue := range issues {
+		counts[issue.Severity]++
+	}
+
+	return counts
+}
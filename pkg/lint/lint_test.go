@@ -0,0 +1,149 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+const lintTestSpec = `openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /petStore/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema: {type: integer}
+        example: abc
+    get:
+      security:
+        - apiKey: []
+        - oauth: []
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+              examples:
+                rex:
+                  value: {id: "1", name: rex}
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      security: []
+      responses:
+        '200': {description: ok}
+        '404': {description: none}
+components:
+  securitySchemes:
+    apiKey: {type: apiKey, in: header, name: X-Key}
+    unusedKey: {type: apiKey, in: query, name: key}
+  schemas:
+    Pet:
+      type: object
+      properties:
+        id: {type: integer, example: seven}
+        name: {type: string}
+    Orphan:
+      type: string
+`
+
+func lintSpec(t *testing.T, data string, cfg *Config) []Issue {
+	t.Helper()
+
+	spec, err := openapi.LoadFromData([]byte(data), openapi.WithoutExampleValidation())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := openapi.ParseSource([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Lint(spec, source, cfg)
+}
+
+func TestLint(t *testing.T) {
+	issues := lintSpec(t, lintTestSpec, &Config{})
+
+	expected := []struct {
+		rule     string
+		severity Severity
+		line     int
+		message  string
+	}{
+		{"path-kebab-case", Warning, 4, `path /petStore/{petId} has segment "petStore" that isn't kebab-case`},
+		{"parameter-description", Info, 6, `path parameter "petId" has no description`},
+		{"example-schema", Error, 10, "example doesn't match the schema: $: value must be an integer"},
+		{"operation-operationId", Warning, 11, "GET /petStore/{petId} has no operationId"},
+		{"operation-description", Warning, 11, "GET /petStore/{petId} has no summary or description"},
+		{"security-defined", Error, 14, `security scheme "oauth" is not declared`},
+		{"operation-4xx-response", Warning, 15, "GET /petStore/{petId} declares no 4xx response"},
+		{"example-schema", Error, 24, "example doesn't match the schema: $.id: value must be an integer"},
+		{"unused-component", Warning, 36, `security scheme "unusedKey" is never referenced`},
+		{"example-schema", Error, 41, "example doesn't match the schema: $: value must be an integer"},
+		{"unused-component", Warning, 43, `schema "Orphan" is never referenced`},
+	}
+
+	if len(issues) != len(expected) {
+		for _, issue := range issues {
+			t.Logf("%+v", issue)
+		}
+		t.Fatalf("Lint() returned %d issues, want %d", len(issues), len(expected))
+	}
+
+	for i, want := range expected {
+		got := issues[i]
+		if got.Rule != want.rule || got.Severity != want.severity || got.Line != want.line || got.Message != want.message {
+			t.Errorf("issue %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	if got, want := issues[7].Pointer, "/paths/~1petStore~1{petId}/get/responses/200/content/application~1json/examples/rex/value"; got != want {
+		t.Errorf("Pointer = %q, want %q", got, want)
+	}
+
+	if counts := Count(issues); counts[Error] != 4 || counts[Warning] != 6 || counts[Info] != 1 {
+		t.Errorf("Count() = %v", counts)
+	}
+}
+
+func TestLintConfig(t *testing.T) {
+	cfg := &Config{Rules: map[string]Severity{
+		"example-schema":        Off,
+		"security-defined":      Off,
+		"parameter-description": Off,
+		"unused-component":      Off,
+		"path-kebab-case":       Error,
+	}}
+
+	issues := lintSpec(t, lintTestSpec, cfg)
+
+	counts := Count(issues)
+	if counts[Error] != 1 || counts[Warning] != 3 || counts[Info] != 0 {
+		t.Errorf("Count() = %v, issues %+v", counts, issues)
+	}
+
+	if issues[0].Rule != "path-kebab-case" || issues[0].Severity != Error {
+		t.Errorf("first issue = %+v, want path-kebab-case error", issues[0])
+	}
+}
+
+func TestRules(t *testing.T) {
+	seen := make(map[string]bool)
+
+	for _, r := range rules {
+		if r.Name == "" || r.Description == "" || !r.Severity.valid() || r.Severity == Off || r.check == nil {
+			t.Errorf("rule %+v is incomplete", r)
+		}
+		if seen[r.Name] {
+			t.Errorf("rule %q is declared twice", r.Name)
+		}
+		seen[r.Name] = true
+	}
+}
@@ -0,0 +1,503 @@
+package lint
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+var rules = []Rule{
+	{
+		Name:        "operation-operationId",
+		Description: "Operations have an operationId",
+		Severity:    Warning,
+		check:       checkOperationID,
+	},
+	{
+		Name:        "operation-description",
+		Description: "Operations have a summary or description",
+		Severity:    Warning,
+		check:       checkOperationDescription,
+	},
+	{
+		Name:        "parameter-description",
+		Description: "Parameters have a description",
+		Severity:    Info,
+		check:       checkParameterDescription,
+	},
+	{
+		Name:        "path-kebab-case",
+		Description: "Path segments are lowercase words joined by hyphens",
+		Severity:    Warning,
+		check:       checkPathKebabCase,
+	},
+	{
+		Name:        "unused-component",
+		Description: "Every component is referenced",
+		Severity:    Warning,
+		check:       checkUnusedComponents,
+	},
+	{
+		Name:        "operation-4xx-response",
+		Description: "Operations declare at least one 4xx response",
+		Severity:    Warning,
+		check:       checkClientErrorResponse,
+	},
+	{
+		Name:        "operation-security",
+		Description: "Operations state their security when the API has security schemes",
+		Severity:    Warning,
+		check:       checkOperationSecurity,
+	},
+	{
+		Name:        "security-defined",
+		Description: "Security requirements name declared security schemes",
+		Severity:    Error,
+		check:       checkSecurityDefined,
+	},
+	{
+		Name:        "example-schema",
+		Description: "Examples match their schemas",
+		Severity:    Error,
+		check:       checkExamples,
+	},
+}
+
+// operation is an operation with where it is in the document.
+type operation struct {
+	*openapi3.Operation
+	name string
+	path []string
+}
+
+// operations lists the operations of the document sorted by path, then
+// method.
+func (d *document) operations() []operation {
+	var ops []operation
+
+	if d.Paths == nil {
+		return nil
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(d.Paths.Map())) {
+		item := d.Paths.Value(path)
+		for _, method := range slices.Sorted(maps.Keys(item.Operations())) {
+			ops = append(ops, operation{
+				Operation: item.Operations()[method],
+				name:      method + " " + path,
+				path:      []string{"paths", path, strings.ToLower(method)},
+			})
+		}
+	}
+
+	return ops
+}
+
+func (d *document) components() *openapi3.Components {
+	if d.Components == nil {
+		return &openapi3.Components{}
+	}
+
+	return d.Components
+}
+
+func checkOperationID(d *document, report reportFunc) {
+	for _, op := range d.operations() {
+		if op.OperationID == "" {
+			report(op.path, "%s has no operationId", op.name)
+		}
+	}
+}
+
+func checkOperationDescription(d *document, report reportFunc) {
+	for _, op := range d.operations() {
+		if op.Summary == "" && op.Description == "" {
+			report(op.path, "%s has no summary or description", op.name)
+		}
+	}
+}
+
+// checkParameterDescription reports parameters where they are declared, so
+// one shared through components is reported once.
+func checkParameterDescription(d *document, report reportFunc) {
+	check := func(path []string, params openapi3.Parameters) {
+		for i, ref := range params {
+			if ref == nil || ref.Value == nil || ref.Ref != "" {
+				continue
+			}
+			if ref.Value.Description == "" {
+				report(openapi.At(path, "parameters", strconv.Itoa(i)), "%s parameter %q has no description", ref.Value.In, ref.Value.Name)
+			}
+		}
+	}
+
+	if d.Paths != nil {
+		for _, path := range slices.Sorted(maps.Keys(d.Paths.Map())) {
+			check([]string{"paths", path}, d.Paths.Value(path).Parameters)
+		}
+	}
+
+	for _, op := range d.operations() {
+		check(op.path, op.Parameters)
+	}
+
+	params := d.components().Parameters
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		if p := params[name]; p != nil && p.Value != nil && p.Value.Description == "" {
+			report([]string{"components", "parameters", name}, "%s parameter %q has no description", p.Value.In, p.Value.Name)
+		}
+	}
+}
+
+var (
+	kebabCase = regexp.MustCompile(`^[a-z0-9]+([-.][a-z0-9]+)*$`)
+	template  = regexp.MustCompile(`\{[^}]*\}`)
+)
+
+func checkPathKebabCase(d *document, report reportFunc) {
+	if d.Paths == nil {
+		return
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(d.Paths.Map())) {
+		for _, segment := range strings.Split(path, "/") {
+			// Parameters are named by the spec's own conventions, only the
+			// literal parts of a segment show up in URLs.
+			literal := template.ReplaceAllString(segment, "x")
+			if literal == "" || kebabCase.MatchString(literal) {
+				continue
+			}
+
+			report([]string{"paths", path}, "path %s has segment %q that isn't kebab-case", path, segment)
+		}
+	}
+}
+
+// componentKinds names the component sections in messages.
+var componentKinds = []struct {
+	section string
+	kind    string
+}{
+	{"schemas", "schema"},
+	{"parameters", "parameter"},
+	{"headers", "header"},
+	{"requestBodies", "request body"},
+	{"responses", "response"},
+	{"examples", "example"},
+	{"links", "link"},
+	{"callbacks", "callback"},
+	{"securitySchemes", "security scheme"},
+}
+
+// swaggerRefs maps Swagger 2.0 reference prefixes to their OpenAPI 3
+// component sections.
+var swaggerRefs = map[string]string{
+	"#/definitions/": "#/components/schemas/",
+	"#/parameters/":  "#/components/parameters/",
+	"#/responses/":   "#/components/responses/",
+}
+
+func checkUnusedComponents(d *document, report reportFunc) {
+	used := make(map[string]bool)
+
+	for _, ref := range d.source.Refs() {
+		for prefix, section := range swaggerRefs {
+			if rest, ok := strings.CutPrefix(ref, prefix); ok {
+				ref = section + rest
+			}
+		}
+
+		// Only references into this document count, down to the component
+		// they're in, like #/components/schemas/Pet/properties/id.
+		segments := strings.Split(strings.TrimPrefix(ref, "#/"), "/")
+		if !strings.HasPrefix(ref, "#/") || len(segments) < 3 {
+			continue
+		}
+		used["#/"+strings.Join(segments[:3], "/")] = true
+	}
+
+	for _, req := range d.securityRequirements() {
+		for name := range req.requirement {
+			used[componentRef("securitySchemes", name)] = true
+		}
+	}
+
+	c := d.components()
+	sections := map[string][]string{
+		"schemas":         slices.Collect(maps.Keys(c.Schemas)),
+		"parameters":      slices.Collect(maps.Keys(c.Parameters)),
+		"headers":         slices.Collect(maps.Keys(c.Headers)),
+		"requestBodies":   slices.Collect(maps.Keys(c.RequestBodies)),
+		"responses":       slices.Collect(maps.Keys(c.Responses)),
+		"examples":        slices.Collect(maps.Keys(c.Examples)),
+		"links":           slices.Collect(maps.Keys(c.Links)),
+		"callbacks":       slices.Collect(maps.Keys(c.Callbacks)),
+		"securitySchemes": slices.Collect(maps.Keys(c.SecuritySchemes)),
+	}
+
+	for _, k := range componentKinds {
+		names := sections[k.section]
+		slices.Sort(names)
+
+		for _, name := range names {
+			if !used[componentRef(k.section, name)] {
+				report([]string{"components", k.section, name}, "%s %q is never referenced", k.kind, name)
+			}
+		}
+	}
+}
+
+// componentRef is the $ref of a component, like #/components/schemas/Pet.
+func componentRef(section, name string) string {
+	return "#" + openapi.JSONPointer("components", section, name)
+}
+
+func checkClientErrorResponse(d *document, report reportFunc) {
+	for _, op := range d.operations() {
+		if op.Responses == nil {
+			continue
+		}
+
+		declared := slices.ContainsFunc(slices.Collect(maps.Keys(op.Responses.Map())), func(code string) bool {
+			return len(code) == 3 && code[0] == '4'
+		})
+		if !declared {
+			report(openapi.At(op.path, "responses"), "%s declares no 4xx response", op.name)
+		}
+	}
+}
+
+// checkOperationSecurity reports operations that leave it open whether they
+// need credentials: the API has security schemes, but neither the operation
+// nor the document says which apply. Public operations say so with an empty
+// security list.
+func checkOperationSecurity(d *document, report reportFunc) {
+	if len(d.components().SecuritySchemes) == 0 || len(d.Security) > 0 {
+		return
+	}
+
+	for _, op := range d.operations() {
+		if op.Security == nil {
+			report(op.path, "%s doesn't declare its security, the API has security schemes", op.name)
+		}
+	}
+}
+
+// securityRequirement is a requirement with where it is in the document.
+type securityRequirement struct {
+	requirement openapi3.SecurityRequirement
+	path        []string
+}
+
+func (d *document) securityRequirements() []securityRequirement {
+	var reqs []securityRequirement
+
+	for i, req := range d.Security {
+		reqs = append(reqs, securityRequirement{requirement: req, path: []string{"security", strconv.Itoa(i)}})
+	}
+
+	for _, op := range d.operations() {
+		if op.Security == nil {
+			continue
+		}
+		for i, req := range *op.Security {
+			reqs = append(reqs, securityRequirement{requirement: req, path: openapi.At(op.path, "security", strconv.Itoa(i))})
+		}
+	}
+
+	return reqs
+}
+
+func checkSecurityDefined(d *document, report reportFunc) {
+	schemes := d.components().SecuritySchemes
+
+	for _, req := range d.securityRequirements() {
+		for _, name := range slices.Sorted(maps.Keys(req.requirement)) {
+			if _, ok := schemes[name]; !ok {
+				report(req.path, "security scheme %q is not declared", name)
+			}
+		}
+	}
+}
+
+// checkExamples validates the examples of parameters, headers, media types
+// and schemas. Parts shared through components are checked once, where they
+// are declared.
+func checkExamples(d *document, report reportFunc) {
+	e := exampleChecker{report: report, shared: d.components().Examples}
+
+	if d.Paths != nil {
+		for _, path := range slices.Sorted(maps.Keys(d.Paths.Map())) {
+			e.parameters([]string{"paths", path}, d.Paths.Value(path).Parameters)
+		}
+	}
+
+	for _, op := range d.operations() {
+		e.parameters(op.path, op.Parameters)
+
+		if op.RequestBody != nil && op.RequestBody.Ref == "" && op.RequestBody.Value != nil {
+			e.content(openapi.At(op.path, "requestBody", "content"), op.RequestBody.Value.Content)
+		}
+
+		if op.Responses != nil {
+			for _, code := range slices.Sorted(maps.Keys(op.Responses.Map())) {
+				if ref := op.Responses.Value(code); ref != nil && ref.Ref == "" {
+					e.response(openapi.At(op.path, "responses", code), ref.Value)
+				}
+			}
+		}
+	}
+
+	c := d.components()
+
+	for _, name := range slices.Sorted(maps.Keys(c.Schemas)) {
+		e.schema([]string{"components", "schemas", name}, c.Schemas[name].Value)
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Parameters)) {
+		if p := c.Parameters[name]; p != nil && p.Value != nil {
+			e.parameter([]string{"components", "parameters", name}, p.Value)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Headers)) {
+		if h := c.Headers[name]; h != nil && h.Value != nil {
+			e.parameter([]string{"components", "headers", name}, &h.Value.Parameter)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.RequestBodies)) {
+		if b := c.RequestBodies[name]; b != nil && b.Value != nil {
+			e.content([]string{"components", "requestBodies", name, "content"}, b.Value.Content)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Responses)) {
+		if r := c.Responses[name]; r != nil {
+			e.response([]string{"components", "responses", name}, r.Value)
+		}
+	}
+}
+
+type exampleChecker struct {
+	report reportFunc
+	// shared are the component examples, which the loader doesn't always
+	// resolve references to.
+	shared openapi3.Examples
+}
+
+// value reports each way an example breaks its schema.
+func (e exampleChecker) value(path []string, schema *openapi3.SchemaRef, value interface{}) {
+	if schema == nil || schema.Value == nil || value == nil {
+		return
+	}
+
+	for _, v := range openapi.ValidateValue(schema.Value, value) {
+		e.report(path, "example doesn't match the schema: %s", v)
+	}
+}
+
+// examples checks the example and named examples declared next to a schema.
+func (e exampleChecker) examples(path []string, schema *openapi3.SchemaRef, example interface{}, examples openapi3.Examples) {
+	e.value(openapi.At(path, "example"), schema, example)
+
+	for _, name := range slices.Sorted(maps.Keys(examples)) {
+		ref := examples[name]
+		if ref == nil {
+			continue
+		}
+
+		example := ref.Value
+		if example == nil {
+			if shared, ok := e.shared[strings.TrimPrefix(ref.Ref, "#/components/examples/")]; ok && shared != nil {
+				example = shared.Value
+			}
+		}
+		if example == nil {
+			continue
+		}
+
+		// A shared example is checked against each schema it illustrates,
+		// at the reference.
+		examplePath := openapi.At(path, "examples", name)
+		if ref.Ref == "" {
+			examplePath = openapi.At(examplePath, "value")
+		}
+		e.value(examplePath, schema, example.Value)
+	}
+}
+
+func (e exampleChecker) parameters(path []string, params openapi3.Parameters) {
+	for i, ref := range params {
+		if ref != nil && ref.Ref == "" && ref.Value != nil {
+			e.parameter(openapi.At(path, "parameters", strconv.Itoa(i)), ref.Value)
+		}
+	}
+}
+
+func (e exampleChecker) parameter(path []string, param *openapi3.Parameter) {
+	e.examples(path, param.Schema, param.Example, param.Examples)
+	e.inlineSchema(openapi.At(path, "schema"), param.Schema)
+	e.content(openapi.At(path, "content"), param.Content)
+}
+
+func (e exampleChecker) response(path []string, resp *openapi3.Response) {
+	if resp == nil {
+		return
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(resp.Headers)) {
+		if h := resp.Headers[name]; h != nil && h.Ref == "" && h.Value != nil {
+			e.parameter(openapi.At(path, "headers", name), &h.Value.Parameter)
+		}
+	}
+
+	e.content(openapi.At(path, "content"), resp.Content)
+}
+
+func (e exampleChecker) content(path []string, content openapi3.Content) {
+	for _, contentType := range slices.Sorted(maps.Keys(content)) {
+		media := content[contentType]
+		if media == nil {
+			continue
+		}
+
+		mediaPath := openapi.At(path, contentType)
+		e.examples(mediaPath, media.Schema, media.Example, media.Examples)
+		e.inlineSchema(openapi.At(mediaPath, "schema"), media.Schema)
+	}
+}
+
+// inlineSchema checks a schema written in place. Referenced ones are checked
+// as components.
+func (e exampleChecker) inlineSchema(path []string, ref *openapi3.SchemaRef) {
+	if ref != nil && ref.Ref == "" {
+		e.schema(path, ref.Value)
+	}
+}
+
+func (e exampleChecker) schema(path []string, schema *openapi3.Schema) {
+	if schema == nil {
+		return
+	}
+
+	e.value(openapi.At(path, "example"), openapi3.NewSchemaRef("", schema), schema.Example)
+
+	for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+		e.inlineSchema(openapi.At(path, "properties", name), schema.Properties[name])
+	}
+
+	e.inlineSchema(openapi.At(path, "items"), schema.Items)
+	e.inlineSchema(openapi.At(path, "not"), schema.Not)
+	e.inlineSchema(openapi.At(path, "additionalProperties"), schema.AdditionalProperties.Schema)
+
+	for _, list := range []struct {
+		keyword string
+		refs    openapi3.SchemaRefs
+	}{{"allOf", schema.AllOf}, {"anyOf", schema.AnyOf}, {"oneOf", schema.OneOf}} {
+		for i, ref := range list.refs {
+			e.inlineSchema(openapi.At(path, list.keyword, strconv.Itoa(i)), ref)
+		}
+	}
+}
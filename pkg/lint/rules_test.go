@@ -0,0 +1,211 @@
+package lint
+
+import (
+	"testing"
+)
+
+func TestRuleChecks(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		spec string
+		want []string
+	}{
+		{
+			name: "kebab-case allows templates and extensions",
+			rule: "path-kebab-case",
+			spec: `openapi: 3.0.3
+info: {title: T, version: "1"}
+paths:
+  /pet-owners/{ownerId}/files/{name}.json:
+    parameters:
+      - {name: ownerId, in: path, required: true, schema: {type: string}}
+      - {name: name, in: path, required: true, schema: {type: string}}
+    get:
+      responses: {'200': {description: ok}}
+  /v1.2/Pets_list:
+    get:
+      responses: {'200': {description: ok}}
+`,
+			want: []string{`path /v1.2/Pets_list has segment "Pets_list" that isn't kebab-case`},
+		},
+		{
+			name: "4XX range and explicit codes count",
+			rule: "operation-4xx-response",
+			spec: `openapi: 3.0.3
+info: {title: T, version: "1"}
+paths:
+  /a:
+    get:
+      responses: {'200': {description: ok}, 4XX: {description: client error}}
+  /b:
+    get:
+      responses: {'200': {description: ok}, default: {description: error}}
+`,
+			want: []string{"GET /b declares no 4xx response"},
+		},
+		{
+			name: "security left open without a document default",
+			rule: "operation-security",
+			spec: `openapi: 3.0.3
+info: {title: T, version: "1"}
+paths:
+  /public:
+    get:
+      security: []
+      responses: {'200': {description: ok}}
+  /secret:
+    get:
+      security: [{key: []}]
+      responses: {'200': {description: ok}}
+  /unknown:
+    get:
+      responses: {'200': {description: ok}}
+components:
+  securitySchemes:
+    key: {type: apiKey, in: header, name: X-Key}
+`,
+			want: []string{"GET /unknown doesn't declare its security, the API has security schemes"},
+		},
+		{
+			name: "document default security covers operations",
+			rule: "operation-security",
+			spec: `openapi: 3.0.3
+info: {title: T, version: "1"}
+security: [{key: []}]
+paths:
+  /pets:
+    get:
+      responses: {'200': {description: ok}}
+components:
+  securitySchemes:
+    key: {type: apiKey, in: header, name: X-Key}
+`,
+		},
+		{
+			name: "document default security names undeclared scheme",
+			rule: "security-defined",
+			spec: `openapi: 3.0.3
+info: {title: T, version: "1"}
+security: [{basic: [], key: []}]
+paths: {}
+components:
+  securitySchemes:
+    key: {type: apiKey, in: header, name: X-Key}
+`,
+			want: []string{`security scheme "basic" is not declared`},
+		},
+		{
+			name: "components referenced from other components are used",
+			rule: "unused-component",
+			spec: `openapi: 3.0.3
+info: {title: T, version: "1"}
+paths:
+  /pets:
+    get:
+      parameters:
+        - $ref: '#/components/parameters/Limit'
+      responses:
+        '200':
+          $ref: '#/components/responses/Pets'
+components:
+  parameters:
+    Limit: {name: limit, in: query, schema: {type: integer}}
+    Offset: {name: offset, in: query, schema: {type: integer}}
+  responses:
+    Pets:
+      description: ok
+      content:
+        application/json:
+          schema: {$ref: '#/components/schemas/Pet/properties/name'}
+  schemas:
+    Pet:
+      type: object
+      properties:
+        name: {type: string}
+`,
+			want: []string{`parameter "Offset" is never referenced`},
+		},
+		{
+			name: "swagger definitions",
+			rule: "unused-component",
+			spec: `swagger: "2.0"
+info: {title: T, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        '200': {description: ok, schema: {$ref: '#/definitions/Pet'}}
+definitions:
+  Pet: {type: object}
+  Orphan: {type: object}
+`,
+			want: []string{`schema "Orphan" is never referenced`},
+		},
+		{
+			name: "shared examples are checked where they are used",
+			rule: "example-schema",
+			spec: `openapi: 3.0.3
+info: {title: T, version: "1"}
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: tags
+          in: query
+          schema:
+            type: array
+            items: {type: string, enum: [a, b]}
+          examples:
+            bad: {$ref: '#/components/examples/Tags'}
+      responses:
+        '200':
+          description: ok
+          headers:
+            X-Rate-Limit:
+              schema: {type: integer, minimum: 1}
+              example: 0
+components:
+  examples:
+    Tags:
+      value: [a, c]
+`,
+			want: []string{
+				`example doesn't match the schema: $[1]: value is not one of the allowed values ["a","b"]`,
+				"example doesn't match the schema: $: number must be at least 1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, ok := findRule(tt.rule)
+			if !ok {
+				t.Fatalf("unknown rule %q", tt.rule)
+			}
+
+			cfg := &Config{Rules: map[string]Severity{}}
+			for _, other := range rules {
+				if other.Name != r.Name {
+					cfg.Rules[other.Name] = Off
+				}
+			}
+
+			issues := lintSpec(t, tt.spec, cfg)
+
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.Message)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("issues = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("issue %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
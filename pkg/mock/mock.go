@@ -0,0 +1,329 @@
+// Package mock serves the operations of a spec with their example responses,
+// a stand-in for a backend that doesn't exist yet.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+// Handler answers requests for the operations of a spec. Requests are routed
+// by path template and checked against the operation first; the response is
+// the declared example for the chosen status, or one generated from its
+// schema.
+type Handler struct {
+	spec *openapi.Spec
+	log  io.Writer
+}
+
+type Option func(*Handler)
+
+// WithLog writes a line per request to w, with the reason for error replies.
+func WithLog(w io.Writer) Option {
+	return func(h *Handler) {
+		h.log = w
+	}
+}
+
+func New(spec *openapi.Spec, opts ...Option) *Handler {
+	h := &Handler{spec: spec}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// reply is a response made up for a request.
+type reply struct {
+	status      int
+	contentType string
+	body        []byte
+	// problems explain an error reply in the log.
+	problems []string
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	allowCORS(w, r)
+
+	// Browsers ask before cross origin requests with custom headers, which
+	// the spec doesn't declare as operations.
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	rep := h.reply(r)
+
+	if rep.contentType != "" {
+		w.Header().Set("Content-Type", rep.contentType)
+	}
+	w.WriteHeader(rep.status)
+	_, _ = w.Write(rep.body)
+
+	if h.log != nil {
+		_, _ = fmt.Fprintf(h.log, "%s %s → %d\n", r.Method, r.URL.RequestURI(), rep.status)
+		for _, p := range rep.problems {
+			_, _ = fmt.Fprintf(h.log, "  %s\n", p)
+		}
+	}
+}
+
+func (h *Handler) reply(r *http.Request) reply {
+	match, err := h.spec.MatchOperation(r.Method, r.URL.EscapedPath())
+	if err != nil {
+		return errorReply(http.StatusNotFound, err.Error(), nil)
+	}
+
+	pathParams := make(map[string]string, len(match.PathParams))
+	for name, value := range match.PathParams {
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+		pathParams[name] = value
+	}
+
+	violations, _ := h.spec.ValidateRequest(match.Path.Path, r, pathParams)
+	if len(violations) > 0 {
+		return errorReply(http.StatusBadRequest, "request doesn't match the spec", violations)
+	}
+
+	prefer := parsePrefer(r.Header.Get("Prefer"))
+
+	status, resp, err := selectResponse(match.Operation, prefer.code, prefer.example)
+	if err != nil {
+		return errorReply(http.StatusBadRequest, fmt.Sprintf("%s for %s %s", err, match.Operation.Method, match.Path.Path), nil)
+	}
+
+	contentType, media, ok := negotiate(resp.Content, r.Header.Get("Accept"))
+	if !ok {
+		if prefer.example != "" {
+			return errorReply(http.StatusBadRequest, fmt.Sprintf("status %d has no content for example %q", status, prefer.example), nil)
+		}
+		return reply{status: status}
+	}
+
+	body, err := exampleBody(media, prefer.example, contentType)
+	if err != nil {
+		return errorReply(http.StatusBadRequest, err.Error(), nil)
+	}
+
+	return reply{status: status, contentType: contentType, body: body}
+}
+
+// errorReply explains why the mock couldn't answer as the spec says.
+func errorReply(status int, msg string, violations []openapi.Violation) reply {
+	payload := struct {
+		Error      string   `json:"error"`
+		Violations []string `json:"violations,omitempty"`
+	}{Error: msg}
+
+	problems := []string{msg}
+	for _, v := range violations {
+		payload.Violations = append(payload.Violations, v.String())
+		problems = append(problems, v.String())
+	}
+
+	body, _ := json.MarshalIndent(payload, "", "  ")
+
+	return reply{status: status, contentType: "application/json", body: body, problems: problems}
+}
+
+func allowCORS(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Origin") == "" {
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if method := r.Header.Get("Access-Control-Request-Method"); method != "" {
+		w.Header().Set("Access-Control-Allow-Methods", method)
+	}
+	if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+		w.Header().Set("Access-Control-Allow-Headers", headers)
+	}
+}
+
+// preference is what a client asks for with the Prefer header, like
+// "code=404, example=notFound".
+type preference struct {
+	code    int
+	example string
+}
+
+func parsePrefer(header string) preference {
+	var p preference
+
+	for _, part := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "code":
+			if code, err := strconv.Atoi(value); err == nil {
+				p.code = code
+			}
+		case "example":
+			p.example = value
+		}
+	}
+
+	return p
+}
+
+// selectResponse picks the response for the status code the client asked
+// for, found directly, by range like 4XX or as the default. Without one it
+// takes the response declaring the requested example, or the first success.
+func selectResponse(op *openapi.Operation, code int, example string) (int, openapi.Response, error) {
+	if code > 0 {
+		for _, key := range []string{strconv.Itoa(code), fmt.Sprintf("%dXX", code/100), "default"} {
+			if resp, ok := op.Responses[key]; ok {
+				return code, resp, nil
+			}
+		}
+		return 0, openapi.Response{}, fmt.Errorf("status %d is not declared", code)
+	}
+
+	keys := make([]string, 0, len(op.Responses))
+	for key := range op.Responses {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return statusRank(keys[i]) < statusRank(keys[j]) })
+
+	if example != "" {
+		for _, key := range keys {
+			for _, media := range op.Responses[key].Content {
+				if _, ok := media.Examples[example]; ok {
+					return statusCode(key), op.Responses[key], nil
+				}
+			}
+		}
+		return 0, openapi.Response{}, fmt.Errorf("example %q is not declared", example)
+	}
+
+	if len(keys) == 0 {
+		return http.StatusOK, openapi.Response{}, nil
+	}
+
+	return statusCode(keys[0]), op.Responses[keys[0]], nil
+}
+
+// statusRank orders response keys by preference as the default reply:
+// successes first, then the default response, then the rest by code.
+func statusRank(key string) int {
+	code := statusCode(key)
+	switch {
+	case key == "default":
+		return 1000
+	case code >= 200 && code < 300:
+		return code
+	default:
+		return 1000 + code
+	}
+}
+
+// statusCode is the code to send for a response key, the lowest of a range
+// and 200 for the default response.
+func statusCode(key string) int {
+	if code, err := strconv.Atoi(key); err == nil {
+		return code
+	}
+
+	if len(key) == 3 && strings.HasSuffix(strings.ToUpper(key), "XX") && key[0] >= '1' && key[0] <= '5' {
+		return int(key[0]-'0') * 100
+	}
+
+	return http.StatusOK
+}
+
+// negotiate picks the content type to answer with: the first declared one the
+// Accept header allows, preferring JSON. When none is allowed it answers with
+// the preferred one anyway, a mock is more useful lenient.
+func negotiate(content map[string]openapi.MediaType, accept string) (string, openapi.MediaType, bool) {
+	if len(content) == 0 {
+		return "", openapi.MediaType{}, false
+	}
+
+	types := openapi.SortedContentTypes(content)
+
+	chosen := types[0]
+
+accepted:
+	for _, part := range strings.Split(accept, ",") {
+		want, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		want = strings.ToLower(strings.TrimSpace(want))
+		if want == "" {
+			continue
+		}
+
+		for _, contentType := range types {
+			if mediaMatches(want, contentType) {
+				chosen = contentType
+				break accepted
+			}
+		}
+	}
+
+	// Wildcards in the spec don't name a type to send.
+	name := chosen
+	if strings.Contains(name, "*") {
+		name = "application/json"
+	}
+
+	return name, content[chosen], true
+}
+
+// mediaMatches reports whether the media range from an Accept header covers
+// the content type.
+func mediaMatches(want, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	switch {
+	case want == "*/*", want == mediaType:
+		return true
+	case strings.HasSuffix(want, "/*"):
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(want, "*"))
+	}
+
+	return false
+}
+
+// exampleBody encodes the named example, the media type's example or one
+// generated from its schema.
+func exampleBody(media openapi.MediaType, name, contentType string) ([]byte, error) {
+	value := media.Example
+	if name != "" {
+		example, ok := media.Examples[name]
+		if !ok {
+			return nil, fmt.Errorf("example %q is not declared for %s", name, contentType)
+		}
+		value = example
+	}
+
+	if value == nil {
+		value = openapi.GenerateExample(media.Schema, openapi.ExampleResponse)
+	}
+
+	body, err := openapi.FormatExample(contentType, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(body), nil
+}
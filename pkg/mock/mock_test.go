@@ -0,0 +1,340 @@
+package mock
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+const testSpec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+servers:
+  - url: https://api.example.com/v1
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 10
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Pet'
+            text/plain:
+              example: rex, tom
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        '201':
+          description: created
+  /pets/{petId}:
+    get:
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              examples:
+                rex:
+                  value: {id: 1, name: rex}
+                tom:
+                  value: {id: 2, name: tom}
+        4XX:
+          description: client error
+          content:
+            application/json:
+              examples:
+                notFound:
+                  value: {message: no such pet}
+  /pets/mine:
+    get:
+      responses:
+        default:
+          description: anything
+          content:
+            application/json:
+              example: {id: 7, name: mine}
+components:
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        id:
+          type: integer
+          readOnly: true
+        name:
+          type: string
+          example: doggie
+`
+
+func newTestServer(t *testing.T, log io.Writer) *httptest.Server {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(file, []byte(testSpec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := openapi.LoadFromFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(New(spec, WithLog(log)))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestHandler(t *testing.T) {
+	server := newTestServer(t, io.Discard)
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		headers         map[string]string
+		body            string
+		wantStatus      int
+		wantContentType string
+		wantBody        string
+	}{
+		{
+			name:            "schema generated body",
+			method:          "GET",
+			path:            "/v1/pets",
+			wantStatus:      200,
+			wantContentType: "application/json",
+			wantBody:        `[{"id":0,"name":"doggie"}]`,
+		},
+		{
+			name:            "accept picks the content type",
+			method:          "GET",
+			path:            "/pets?limit=5",
+			headers:         map[string]string{"Accept": "text/plain"},
+			wantStatus:      200,
+			wantContentType: "text/plain",
+			wantBody:        "rex, tom",
+		},
+		{
+			name:            "first named example",
+			method:          "GET",
+			path:            "/v1/pets/1",
+			wantStatus:      200,
+			wantContentType: "application/json",
+			wantBody:        `{"id":1,"name":"rex"}`,
+		},
+		{
+			name:       "prefer example",
+			method:     "GET",
+			path:       "/v1/pets/2",
+			headers:    map[string]string{"Prefer": "example=tom"},
+			wantStatus: 200,
+			wantBody:   `{"id":2,"name":"tom"}`,
+		},
+		{
+			name:       "prefer example of another status",
+			method:     "GET",
+			path:       "/v1/pets/2",
+			headers:    map[string]string{"Prefer": "example=notFound"},
+			wantStatus: 400,
+			wantBody:   `{"message":"no such pet"}`,
+		},
+		{
+			name:       "prefer code from a range",
+			method:     "GET",
+			path:       "/v1/pets/2",
+			headers:    map[string]string{"Prefer": "code=404, example=notFound"},
+			wantStatus: 404,
+			wantBody:   `{"message":"no such pet"}`,
+		},
+		{
+			name:       "literal path wins over template",
+			method:     "GET",
+			path:       "/v1/pets/mine",
+			wantStatus: 200,
+			wantBody:   `{"id":7,"name":"mine"}`,
+		},
+		{
+			name:       "no content",
+			method:     "POST",
+			path:       "/v1/pets",
+			headers:    map[string]string{"Content-Type": "application/json"},
+			body:       `{"name":"rex"}`,
+			wantStatus: 201,
+		},
+		{
+			name:       "undeclared code",
+			method:     "GET",
+			path:       "/v1/pets/2",
+			headers:    map[string]string{"Prefer": "code=500"},
+			wantStatus: 400,
+			wantBody:   `{"error":"status 500 is not declared for GET /pets/{petId}"}`,
+		},
+		{
+			name:       "unknown example",
+			method:     "GET",
+			path:       "/v1/pets/2",
+			headers:    map[string]string{"Prefer": "example=felix"},
+			wantStatus: 400,
+			wantBody:   `{"error":"example \"felix\" is not declared for GET /pets/{petId}"}`,
+		},
+		{
+			name:       "unknown path",
+			method:     "GET",
+			path:       "/v1/owners",
+			wantStatus: 404,
+			wantBody:   `{"error":"no operation matches GET /v1/owners"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d, body %s", resp.StatusCode, tt.wantStatus, body)
+			}
+
+			if tt.wantContentType != "" && resp.Header.Get("Content-Type") != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", resp.Header.Get("Content-Type"), tt.wantContentType)
+			}
+
+			if got := compact(body); got != tt.wantBody {
+				t.Errorf("body = %s, want %s", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestHandlerValidatesRequests(t *testing.T) {
+	var log bytes.Buffer
+	server := newTestServer(t, &log)
+
+	resp, err := http.Post(server.URL+"/v1/pets", "application/json", strings.NewReader(`{"id":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.StatusCode)
+	}
+
+	var payload struct {
+		Error      string   `json:"error"`
+		Violations []string `json:"violations"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.Error != "request doesn't match the spec" || !strings.Contains(strings.Join(payload.Violations, "\n"), `request body: $.name: property "name" is missing`) {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	if !strings.Contains(log.String(), "POST /v1/pets → 400") || !strings.Contains(log.String(), `property "name" is missing`) {
+		t.Errorf("log = %q", log.String())
+	}
+}
+
+func TestHandlerCORS(t *testing.T) {
+	server := newTestServer(t, io.Discard)
+
+	req, _ := http.NewRequest(http.MethodOptions, server.URL+"/v1/pets", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	req.Header.Set("Access-Control-Request-Headers", "content-type")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", resp.StatusCode)
+	}
+
+	for name, want := range map[string]string{
+		"Access-Control-Allow-Origin":  "*",
+		"Access-Control-Allow-Methods": "POST",
+		"Access-Control-Allow-Headers": "content-type",
+	} {
+		if got := resp.Header.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestParsePrefer(t *testing.T) {
+	tests := []struct {
+		header string
+		want   preference
+	}{
+		{"", preference{}},
+		{"code=404", preference{code: 404}},
+		{`code=404, example="not found"`, preference{code: 404, example: "not found"}},
+		{"respond-async; example=rex", preference{example: "rex"}},
+		{"code=abc", preference{}},
+	}
+
+	for _, tt := range tests {
+		if got := parsePrefer(tt.header); got != tt.want {
+			t.Errorf("parsePrefer(%q) = %+v, want %+v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func compact(body []byte) string {
+	var b bytes.Buffer
+	if err := json.Compact(&b, body); err != nil {
+		return string(body)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,321 @@
+// Package oauth acquires and refreshes OAuth2 access tokens for the flows
+// declared in a spec's oauth2 security schemes. A Manager can be passed to
+// request.Send as a credential and fetches a token on first use.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+const (
+	FlowClientCredentials = "client_credentials"
+	FlowPassword          = "password"
+	FlowAuthorizationCode = "authorization_code"
+)
+
+// expiryDelta renews tokens slightly before they expire so a request isn't
+// sent with a token that runs out in flight.
+const expiryDelta = 30 * time.Second
+
+type Config struct {
+	// Flow is one of the Flow* constants. When empty the flow is picked from
+	// what the scheme declares and which values are set.
+	Flow         string
+	ClientID     string
+	ClientSecret string
+	Username     string
+	Password     string
+	// Scopes defaults to every scope the selected flow declares.
+	Scopes []string
+	// RedirectPort is the loopback port for the authorization code flow, 0
+	// picks a free one.
+	RedirectPort int
+}
+
+type Token struct {
+	AccessToken  string
+	TokenType    string
+	RefreshToken string
+	Expiry       time.Time
+}
+
+func (t *Token) valid(now time.Time) bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+
+	return t.Expiry.IsZero() || now.Add(expiryDelta).Before(t.Expiry)
+}
+
+type Manager struct {
+	flow   string
+	spec   *openapi.OAuthFlow
+	config Config
+	client *http.Client
+	now    func() time.Time
+	// OpenBrowser is called with the authorization URL in the authorization
+	// code flow. It defaults to the platform's URL opener.
+	OpenBrowser func(authURL string) error
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewManager creates a token manager for an oauth2 security scheme.
+func NewManager(scheme openapi.SecurityScheme, cfg Config) (*Manager, error) {
+	if scheme.Type != "oauth2" || scheme.Flows == nil {
+		return nil, fmt.Errorf("security scheme %q has no OAuth2 flows", scheme.Name)
+	}
+
+	flow := cfg.Flow
+	if flow == "" {
+		flow = selectFlow(scheme.Flows, cfg)
+	}
+
+	var spec *openapi.OAuthFlow
+	switch flow {
+	case FlowClientCredentials:
+		spec = scheme.Flows.ClientCredentials
+	case FlowPassword:
+		spec = scheme.Flows.Password
+	case FlowAuthorizationCode:
+		spec = scheme.Flows.AuthorizationCode
+	default:
+		return nil, fmt.Errorf("unsupported OAuth2 flow %q", flow)
+	}
+
+	if spec == nil {
+		return nil, fmt.Errorf("security scheme %q doesn't declare the %s flow", scheme.Name, flow)
+	}
+
+	if u, err := url.Parse(spec.TokenURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid token URL %q", spec.TokenURL)
+	}
+
+	if cfg.Scopes == nil {
+		cfg.Scopes = flowScopes(spec)
+	}
+
+	return &Manager{
+		flow:        flow,
+		spec:        spec,
+		config:      cfg,
+		client:      &http.Client{Timeout: 30 * time.Second},
+		now:         time.Now,
+		OpenBrowser: openBrowser,
+	}, nil
+}
+
+// selectFlow picks the flow that matches the values the user provided,
+// preferring non-interactive flows.
+func selectFlow(flows *openapi.OAuthFlows, cfg Config) string {
+	switch {
+	case flows.Password != nil && cfg.Username != "":
+		return FlowPassword
+	case flows.ClientCredentials != nil && cfg.ClientSecret != "":
+		return FlowClientCredentials
+	case flows.AuthorizationCode != nil:
+		return FlowAuthorizationCode
+	case flows.ClientCredentials != nil:
+		return FlowClientCredentials
+	case flows.Password != nil:
+		return FlowPassword
+	default:
+		return ""
+	}
+}
+
+func flowScopes(flow *openapi.OAuthFlow) []string {
+	scopes := make([]string, 0, len(flow.Scopes))
+	for scope := range flow.Scopes {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+
+	return scopes
+}
+
+func (m *Manager) Flow() string {
+	return m.flow
+}
+
+// Apply sets a bearer token on the request, fetching or refreshing it first
+// when needed.
+func (m *Manager) Apply(req *http.Request) error {
+	token, err := m.Token(req.Context())
+	if err != nil {
+		return err
+	}
+
+	tokenType := token.TokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+
+	req.Header.Set("Authorization", tokenType+" "+token.AccessToken)
+
+	return nil
+}
+
+// Token returns the cached token if it's still valid, otherwise refreshes
+// it or runs the configured flow again.
+func (m *Manager) Token(ctx context.Context) (*Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.token.valid(m.now()) {
+		return m.token, nil
+	}
+
+	if m.token != nil && m.token.RefreshToken != "" {
+		if token, err := m.refresh(ctx, m.token.RefreshToken); err == nil {
+			m.token = token
+			return token, nil
+		}
+	}
+
+	token, err := m.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.token = token
+
+	return token, nil
+}
+
+func (m *Manager) fetch(ctx context.Context) (*Token, error) {
+	switch m.flow {
+	case FlowClientCredentials:
+		form := url.Values{"grant_type": {"client_credentials"}}
+		return m.exchange(ctx, m.spec.TokenURL, m.withScopes(form))
+	case FlowPassword:
+		form := url.Values{
+			"grant_type": {"password"},
+			"username":   {m.config.Username},
+			"password":   {m.config.Password},
+		}
+		return m.exchange(ctx, m.spec.TokenURL, m.withScopes(form))
+	case FlowAuthorizationCode:
+		return m.authorizationCode(ctx)
+	default:
+		return nil, fmt.Errorf("unsupported OAuth2 flow %q", m.flow)
+	}
+}
+
+func (m *Manager) refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	endpoint := m.spec.RefreshURL
+	if endpoint == "" {
+		endpoint = m.spec.TokenURL
+	}
+
+	token, err := m.exchange(ctx, endpoint, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Servers may omit the refresh token when it doesn't rotate.
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+
+	return token, nil
+}
+
+func (m *Manager) withScopes(form url.Values) url.Values {
+	if len(m.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(m.config.Scopes, " "))
+	}
+	return form
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// exchange posts a token request and parses the RFC 6749 response. Client
+// credentials go in the Authorization header when there is a secret.
+func (m *Manager) exchange(ctx context.Context, endpoint string, form url.Values) (*Token, error) {
+	if endpoint == "" {
+		return nil, errors.New("no token URL declared for the OAuth2 flow")
+	}
+
+	if m.config.ClientID != "" {
+		form.Set("client_id", m.config.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if m.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(m.config.ClientID), url.QueryEscape(m.config.ClientSecret))
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return nil, fmt.Errorf("invalid token response (%s): %w", resp.Status, err)
+	}
+
+	if tr.Error != "" {
+		if tr.ErrorDescription != "" {
+			return nil, fmt.Errorf("token request rejected: %s: %s", tr.Error, tr.ErrorDescription)
+		}
+		return nil, fmt.Errorf("token request rejected: %s", tr.Error)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token request failed: %s", resp.Status)
+	}
+
+	if tr.AccessToken == "" {
+		return nil, errors.New("token response has no access_token")
+	}
+
+	token := &Token{
+		AccessToken:  tr.AccessToken,
+		TokenType:    tr.TokenType,
+		RefreshToken: tr.RefreshToken,
+	}
+
+	if tr.ExpiresIn > 0 {
+		token.Expiry = m.now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,208 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+// tokenServer is a stand-in token endpoint that records the grants it sees.
+type tokenServer struct {
+	*httptest.Server
+	grants    []string
+	issued    atomic.Int32
+	expiresIn int64
+	lastForm  map[string]string
+	lastUser  string
+}
+
+func newTokenServer(t *testing.T) *tokenServer {
+	ts := &tokenServer{expiresIn: 3600}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm() error = %v", err)
+		}
+
+		ts.lastForm = map[string]string{}
+		for k := range r.PostForm {
+			ts.lastForm[k] = r.PostForm.Get(k)
+		}
+		ts.lastUser, _, _ = r.BasicAuth()
+
+		grant := r.PostForm.Get("grant_type")
+		ts.grants = append(ts.grants, grant)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if grant == "password" && r.PostForm.Get("password") != "hunter2" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": "bad credentials"})
+			return
+		}
+
+		n := ts.issued.Add(1)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "token-" + string(rune('0'+n)),
+			"token_type":    "bearer",
+			"expires_in":    ts.expiresIn,
+			"refresh_token": "refresh",
+		})
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func oauthScheme(tokenURL string) openapi.SecurityScheme {
+	flow := &openapi.OAuthFlow{
+		TokenURL: tokenURL,
+		Scopes:   map[string]string{"write": "Write", "read": "Read"},
+	}
+
+	return openapi.SecurityScheme{
+		Name: "oauth",
+		Type: "oauth2",
+		Flows: &openapi.OAuthFlows{
+			ClientCredentials: flow,
+			Password:          flow,
+		},
+	}
+}
+
+func TestClientCredentialsFlow(t *testing.T) {
+	ts := newTokenServer(t)
+
+	m, err := NewManager(oauthScheme(ts.URL), Config{ClientID: "app", ClientSecret: "s3cret"})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	if m.Flow() != FlowClientCredentials {
+		t.Errorf("Flow() = %q, want %q", m.Flow(), FlowClientCredentials)
+	}
+
+	token, err := m.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+
+	if token.AccessToken != "token-1" {
+		t.Errorf("AccessToken = %q, want token-1", token.AccessToken)
+	}
+
+	if ts.lastUser != "app" {
+		t.Errorf("client authenticated as %q, want app", ts.lastUser)
+	}
+
+	if ts.lastForm["scope"] != "read write" {
+		t.Errorf("scope = %q, want all declared scopes", ts.lastForm["scope"])
+	}
+
+	// Cached token is reused.
+	if _, err := m.Token(context.Background()); err != nil || ts.issued.Load() != 1 {
+		t.Errorf("expected cached token, issued %d tokens (err %v)", ts.issued.Load(), err)
+	}
+}
+
+func TestPasswordFlow(t *testing.T) {
+	ts := newTokenServer(t)
+
+	m, err := NewManager(oauthScheme(ts.URL), Config{ClientID: "app", Username: "alice", Password: "hunter2"})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	if m.Flow() != FlowPassword {
+		t.Fatalf("Flow() = %q, want %q", m.Flow(), FlowPassword)
+	}
+
+	if _, err := m.Token(context.Background()); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+
+	if ts.lastForm["username"] != "alice" || ts.lastForm["client_id"] != "app" {
+		t.Errorf("unexpected token request form: %v", ts.lastForm)
+	}
+
+	bad, _ := NewManager(oauthScheme(ts.URL), Config{Username: "alice", Password: "wrong"})
+	if _, err := bad.Token(context.Background()); err == nil {
+		t.Error("expected error for rejected credentials")
+	}
+}
+
+func TestTokenRefresh(t *testing.T) {
+	ts := newTokenServer(t)
+
+	m, err := NewManager(oauthScheme(ts.URL), Config{ClientID: "app", ClientSecret: "s3cret"})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	if _, err := m.Token(context.Background()); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+
+	token, err := m.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() after expiry error = %v", err)
+	}
+
+	if token.AccessToken != "token-2" {
+		t.Errorf("AccessToken = %q, want refreshed token-2", token.AccessToken)
+	}
+
+	if len(ts.grants) != 2 || ts.grants[1] != "refresh_token" {
+		t.Errorf("grants = %v, want refresh_token on expiry", ts.grants)
+	}
+}
+
+func TestManagerApply(t *testing.T) {
+	ts := newTokenServer(t)
+
+	m, err := NewManager(oauthScheme(ts.URL), Config{ClientID: "app", ClientSecret: "s3cret"})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.example.com/pets", nil)
+	if err := m.Apply(req); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if got := req.Header.Get("Authorization"); got != "Bearer token-1" {
+		t.Errorf("Authorization = %q, want 'Bearer token-1'", got)
+	}
+}
+
+func TestNewManagerErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme openapi.SecurityScheme
+		cfg    Config
+	}{
+		{"not oauth2", openapi.SecurityScheme{Type: "apiKey"}, Config{}},
+		{"no flows", openapi.SecurityScheme{Type: "oauth2"}, Config{}},
+		{"undeclared flow", oauthScheme("http://example.com"), Config{Flow: FlowAuthorizationCode}},
+		{"unknown flow", oauthScheme("http://example.com"), Config{Flow: "implicit"}},
+		{"relative token URL", oauthScheme("/token"), Config{ClientID: "app", ClientSecret: "s"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewManager(tt.scheme, tt.cfg); err == nil {
+				t.Error("NewManager() expected error")
+			}
+		})
+	}
+}
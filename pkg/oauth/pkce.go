@@ -0,0 +1,169 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// authorizationTimeout bounds how long we wait for the user to finish the
+// login in the browser.
+const authorizationTimeout = 5 * time.Minute
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// authorizationCode runs the authorization code flow with PKCE, receiving
+// the code on a loopback redirect listener (RFC 8252).
+func (m *Manager) authorizationCode(ctx context.Context) (*Token, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", m.config.RedirectPort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start redirect listener: %w", err)
+	}
+
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr().String())
+
+	verifier, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan callbackResult, 1)
+	server := &http.Server{
+		Handler:           callbackHandler(state, results),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() { _ = server.Serve(listener) }()
+	defer func() { _ = server.Close() }()
+
+	authURL, err := m.authorizationURL(redirectURI, state, codeChallenge(verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.OpenBrowser(authURL); err != nil {
+		return nil, fmt.Errorf("failed to open browser for %s: %w", authURL, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, authorizationTimeout)
+	defer cancel()
+
+	var result callbackResult
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for authorization: %w", ctx.Err())
+	}
+
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {result.code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {verifier},
+	}
+
+	return m.exchange(ctx, m.spec.TokenURL, form)
+}
+
+func (m *Manager) authorizationURL(redirectURI, state, challenge string) (string, error) {
+	u, err := url.Parse(m.spec.AuthorizationURL)
+	if err != nil || m.spec.AuthorizationURL == "" {
+		return "", fmt.Errorf("invalid authorization URL %q", m.spec.AuthorizationURL)
+	}
+
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", m.config.ClientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("state", state)
+	q.Set("code_challenge", challenge)
+	q.Set("code_challenge_method", "S256")
+	if len(m.config.Scopes) > 0 {
+		q.Set("scope", strings.Join(m.config.Scopes, " "))
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+func callbackHandler(state string, results chan<- callbackResult) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		var result callbackResult
+		switch {
+		case q.Get("error") != "":
+			result.err = fmt.Errorf("authorization denied: %s %s", q.Get("error"), q.Get("error_description"))
+		case q.Get("state") != state:
+			result.err = errors.New("authorization response has an unexpected state")
+		case q.Get("code") == "":
+			result.err = errors.New("authorization response has no code")
+		default:
+			result.code = q.Get("code")
+		}
+
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			_, _ = fmt.Fprintln(w, "Authorization complete, you can close this window and return to tapi.")
+		}
+
+		select {
+		case results <- result:
+		default:
+		}
+	})
+
+	return mux
+}
+
+func randomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func openBrowser(authURL string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", authURL)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", authURL)
+	default:
+		cmd = exec.Command("xdg-open", authURL)
+	}
+
+	return cmd.Start()
+}
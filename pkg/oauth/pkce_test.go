@@ -0,0 +1,121 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+func authCodeScheme(tokenURL string) openapi.SecurityScheme {
+	return openapi.SecurityScheme{
+		Name: "oauth",
+		Type: "oauth2",
+		Flows: &openapi.OAuthFlows{
+			AuthorizationCode: &openapi.OAuthFlow{
+				AuthorizationURL: "https://auth.example.com/authorize",
+				TokenURL:         tokenURL,
+				Scopes:           map[string]string{"read": "Read"},
+			},
+		},
+	}
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	ts := newTokenServer(t)
+
+	m, err := NewManager(authCodeScheme(ts.URL), Config{ClientID: "cli"})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	if m.Flow() != FlowAuthorizationCode {
+		t.Fatalf("Flow() = %q, want %q", m.Flow(), FlowAuthorizationCode)
+	}
+
+	var challenge string
+
+	// Play the authorization server: redirect back to the loopback listener.
+	m.OpenBrowser = func(authURL string) error {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+
+		q := u.Query()
+		if q.Get("code_challenge_method") != "S256" || q.Get("client_id") != "cli" {
+			t.Errorf("unexpected authorization request: %s", authURL)
+		}
+		challenge = q.Get("code_challenge")
+
+		go func() {
+			resp, err := http.Get(q.Get("redirect_uri") + "?code=abc&state=" + url.QueryEscape(q.Get("state")))
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+
+		return nil
+	}
+
+	token, err := m.Token(context.Background())
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+
+	if token.AccessToken != "token-1" {
+		t.Errorf("AccessToken = %q, want token-1", token.AccessToken)
+	}
+
+	if ts.lastForm["code"] != "abc" || ts.lastForm["grant_type"] != "authorization_code" {
+		t.Errorf("unexpected token exchange: %v", ts.lastForm)
+	}
+
+	if codeChallenge(ts.lastForm["code_verifier"]) != challenge {
+		t.Error("code_verifier doesn't match the code_challenge sent to the authorization endpoint")
+	}
+}
+
+func TestAuthorizationCodeFlowStateMismatch(t *testing.T) {
+	ts := newTokenServer(t)
+
+	m, err := NewManager(authCodeScheme(ts.URL), Config{ClientID: "cli"})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	m.OpenBrowser = func(authURL string) error {
+		u, _ := url.Parse(authURL)
+		go func() {
+			resp, err := http.Get(u.Query().Get("redirect_uri") + "?code=abc&state=forged")
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+
+	if _, err := m.Token(context.Background()); err == nil {
+		t.Error("Token() expected error for mismatched state")
+	}
+
+	if ts.issued.Load() != 0 {
+		t.Error("no token should be exchanged for a forged callback")
+	}
+}
+
+func TestAuthorizationCodeFlowBrowserError(t *testing.T) {
+	m, err := NewManager(authCodeScheme("http://127.0.0.1:1"), Config{ClientID: "cli"})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	m.OpenBrowser = func(string) error { return errors.New("no browser") }
+
+	if _, err := m.Token(context.Background()); err == nil {
+		t.Error("Token() expected error when the browser can't be opened")
+	}
+}
@@ -0,0 +1,440 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// Problem is a way a document breaks the OpenAPI specification.
+type Problem struct {
+	Message string `json:"message"`
+	// Pointer is the JSON pointer to the offending part of the document,
+	// empty when the document couldn't be read that far.
+	Pointer string `json:"pointer,omitempty"`
+	Location
+}
+
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return p.Message
+	}
+
+	return p.Location.String() + ": " + p.Message
+}
+
+var syntaxLine = regexp.MustCompile(`line (\d+)`)
+
+// ValidateDocument checks the contents of an OpenAPI 3.x or Swagger 2.0
+// document and returns every problem with where it is, where loading a spec
+// stops at the first. Syntax errors and references that can't be resolved
+// keep the document from being checked any further.
+func ValidateDocument(data []byte) []Problem {
+	source, err := ParseSource(data)
+	if err != nil {
+		p := Problem{Message: err.Error()}
+		if m := syntaxLine.FindStringSubmatch(err.Error()); m != nil {
+			p.Line, _ = strconv.Atoi(m[1])
+		}
+		return []Problem{p}
+	}
+
+	p, err := parse(data)
+	if err != nil {
+		return []Problem{source.loadProblem(err)}
+	}
+
+	v := &documentValidator{
+		ctx:    openapi3.WithValidationOptions(p.loader.Context, p.validation...),
+		source: source,
+	}
+	v.run(v.document(p.doc))
+
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		if v.problems[i].Line != v.problems[j].Line {
+			return v.problems[i].Line < v.problems[j].Line
+		}
+		return v.problems[i].Column < v.problems[j].Column
+	})
+
+	return v.problems
+}
+
+// loadProblem locates an error from loading the document at the reference it
+// is about, when it names one.
+func (s *Source) loadProblem(err error) Problem {
+	for _, ref := range s.refNodes() {
+		if strings.Contains(err.Error(), strconv.Quote(ref.Value)) {
+			return Problem{Message: err.Error(), Location: Location{Line: ref.Line, Column: ref.Column}}
+		}
+	}
+
+	return Problem{Message: err.Error()}
+}
+
+// check is a part of the document to validate, with the parts it's made of.
+// The parts narrow a problem down to the smallest part that has it.
+type check struct {
+	path     []string
+	validate func() error
+	parts    func() []check
+}
+
+type documentValidator struct {
+	ctx      context.Context
+	source   *Source
+	problems []Problem
+}
+
+// run validates c and reports its problem at the parts that have it, or at c
+// when none of them do or c has a problem of its own too. It returns the
+// error of c.
+func (v *documentValidator) run(c check) error {
+	err := c.validate()
+	if err == nil {
+		return nil
+	}
+
+	explained := false
+	if c.parts != nil {
+		for _, part := range c.parts() {
+			// Errors of the parts are wrapped in the error of the whole.
+			if partErr := v.run(part); partErr != nil && strings.Contains(err.Error(), partErr.Error()) {
+				explained = true
+			}
+		}
+	}
+
+	if !explained {
+		// Schema errors go on with the schema and value in JSON.
+		msg, _, _ := strings.Cut(err.Error(), "\n")
+
+		v.problems = append(v.problems, Problem{
+			Message:  msg,
+			Pointer:  v.source.Pointer(c.path...),
+			Location: v.source.Locate(c.path...),
+		})
+	}
+
+	return err
+}
+
+type validatable interface {
+	Validate(ctx context.Context, opts ...openapi3.ValidationOption) error
+}
+
+// of checks a value that validates itself, or is nil and valid.
+func (v *documentValidator) of(path []string, value validatable, parts func() []check) check {
+	return check{
+		path: path,
+		validate: func() error {
+			if value == nil || reflect.ValueOf(value).IsNil() {
+				return nil
+			}
+			return value.Validate(v.ctx)
+		},
+		parts: parts,
+	}
+}
+
+func (v *documentValidator) document(doc *openapi3.T) check {
+	return check{
+		validate: func() error { return doc.Validate(v.ctx) },
+		parts: func() []check {
+			checks := []check{
+				{
+					path: []string{"openapi"},
+					validate: func() error {
+						if doc.OpenAPI == "" {
+							return errors.New("value of openapi must be a non-empty string")
+						}
+						return nil
+					},
+				},
+				{
+					path: []string{"info"},
+					validate: func() error {
+						if doc.Info == nil {
+							return errors.New("info must be an object")
+						}
+						return doc.Info.Validate(v.ctx)
+					},
+				},
+				v.paths(doc.Paths),
+			}
+
+			if doc.Components != nil {
+				checks = append(checks, v.components(doc.Components))
+			}
+			if doc.Security != nil {
+				checks = append(checks, v.of([]string{"security"}, doc.Security, nil))
+			}
+			for i, server := range doc.Servers {
+				if server != nil {
+					checks = append(checks, v.of([]string{"servers", strconv.Itoa(i)}, server, nil))
+				}
+			}
+			for i, tag := range doc.Tags {
+				if tag != nil {
+					checks = append(checks, v.of([]string{"tags", strconv.Itoa(i)}, tag, nil))
+				}
+			}
+			if doc.ExternalDocs != nil {
+				checks = append(checks, v.of([]string{"externalDocs"}, doc.ExternalDocs, nil))
+			}
+
+			return checks
+		},
+	}
+}
+
+func (v *documentValidator) paths(paths *openapi3.Paths) check {
+	return check{
+		path: []string{"paths"},
+		validate: func() error {
+			if paths == nil {
+				return errors.New("paths must be an object")
+			}
+			return paths.Validate(v.ctx)
+		},
+		parts: func() []check {
+			var checks []check
+
+			for _, path := range slices.Sorted(maps.Keys(paths.Map())) {
+				item := paths.Value(path)
+				if item == nil {
+					continue
+				}
+
+				// A path on its own, for the checks that span its operations
+				// like declaring every path parameter.
+				single := openapi3.NewPaths(openapi3.WithPath(path, item))
+				checks = append(checks, v.of([]string{"paths", path}, single, func() []check {
+					return v.pathItem([]string{"paths", path}, item)
+				}))
+			}
+
+			return checks
+		},
+	}
+}
+
+func (v *documentValidator) pathItem(path []string, item *openapi3.PathItem) []check {
+	checks := v.parameters(path, item.Parameters)
+
+	operations := item.Operations()
+	for _, method := range slices.Sorted(maps.Keys(operations)) {
+		op := operations[method]
+		opPath := At(path, strings.ToLower(method))
+
+		checks = append(checks, v.of(opPath, op, func() []check {
+			return v.operation(opPath, op)
+		}))
+	}
+
+	return checks
+}
+
+func (v *documentValidator) operation(path []string, op *openapi3.Operation) []check {
+	checks := v.parameters(path, op.Parameters)
+
+	if op.RequestBody != nil {
+		checks = append(checks, v.of(At(path, "requestBody"), op.RequestBody, func() []check {
+			if op.RequestBody.Ref != "" || op.RequestBody.Value == nil {
+				return nil
+			}
+			return v.content(At(path, "requestBody", "content"), op.RequestBody.Value.Content)
+		}))
+	}
+
+	if op.Responses != nil {
+		checks = append(checks, v.of(At(path, "responses"), op.Responses, func() []check {
+			var responses []check
+			for _, code := range slices.Sorted(maps.Keys(op.Responses.Map())) {
+				if ref := op.Responses.Value(code); ref != nil {
+					responses = append(responses, v.response(At(path, "responses", code), ref))
+				}
+			}
+			return responses
+		}))
+	}
+
+	if op.Security != nil {
+		checks = append(checks, v.of(At(path, "security"), *op.Security, nil))
+	}
+
+	for i, server := range derefServers(op.Servers) {
+		if server != nil {
+			checks = append(checks, v.of(At(path, "servers", strconv.Itoa(i)), server, nil))
+		}
+	}
+
+	return checks
+}
+
+func derefServers(servers *openapi3.Servers) openapi3.Servers {
+	if servers == nil {
+		return nil
+	}
+
+	return *servers
+}
+
+func (v *documentValidator) parameters(path []string, params openapi3.Parameters) []check {
+	var checks []check
+
+	for i, ref := range params {
+		if ref != nil {
+			checks = append(checks, v.parameter(At(path, "parameters", strconv.Itoa(i)), ref))
+		}
+	}
+
+	return checks
+}
+
+// Parts shared through components are narrowed down where they are
+// declared, and only checked as a whole where they are referenced.
+
+func (v *documentValidator) parameter(path []string, ref *openapi3.ParameterRef) check {
+	return v.of(path, ref, func() []check {
+		if ref.Ref != "" || ref.Value == nil {
+			return nil
+		}
+		return append(v.schema(At(path, "schema"), ref.Value.Schema), v.content(At(path, "content"), ref.Value.Content)...)
+	})
+}
+
+func (v *documentValidator) response(path []string, ref *openapi3.ResponseRef) check {
+	return v.of(path, ref, func() []check {
+		if ref.Ref != "" || ref.Value == nil {
+			return nil
+		}
+
+		var checks []check
+		for _, name := range slices.Sorted(maps.Keys(ref.Value.Headers)) {
+			if header := ref.Value.Headers[name]; header != nil {
+				checks = append(checks, v.of(At(path, "headers", name), header, nil))
+			}
+		}
+
+		return append(checks, v.content(At(path, "content"), ref.Value.Content)...)
+	})
+}
+
+func (v *documentValidator) content(path []string, content openapi3.Content) []check {
+	var checks []check
+
+	for _, contentType := range slices.Sorted(maps.Keys(content)) {
+		media := content[contentType]
+		if media == nil {
+			continue
+		}
+
+		mediaPath := At(path, contentType)
+		checks = append(checks, v.of(mediaPath, media, func() []check {
+			return v.schema(At(mediaPath, "schema"), media.Schema)
+		}))
+	}
+
+	return checks
+}
+
+// schema checks a schema written in place, down to its properties and items.
+func (v *documentValidator) schema(path []string, ref *openapi3.SchemaRef) []check {
+	if ref == nil || ref.Ref != "" || ref.Value == nil {
+		return nil
+	}
+
+	return []check{v.of(path, ref, func() []check {
+		return v.schemaParts(path, ref.Value)
+	})}
+}
+
+func (v *documentValidator) schemaParts(path []string, schema *openapi3.Schema) []check {
+	var checks []check
+
+	for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+		checks = append(checks, v.schema(At(path, "properties", name), schema.Properties[name])...)
+	}
+
+	checks = append(checks, v.schema(At(path, "items"), schema.Items)...)
+	checks = append(checks, v.schema(At(path, "additionalProperties"), schema.AdditionalProperties.Schema)...)
+	checks = append(checks, v.schema(At(path, "not"), schema.Not)...)
+
+	for _, list := range []struct {
+		keyword string
+		refs    openapi3.SchemaRefs
+	}{{"allOf", schema.AllOf}, {"anyOf", schema.AnyOf}, {"oneOf", schema.OneOf}} {
+		for i, ref := range list.refs {
+			checks = append(checks, v.schema(At(path, list.keyword, strconv.Itoa(i)), ref)...)
+		}
+	}
+
+	return checks
+}
+
+func (v *documentValidator) components(c *openapi3.Components) check {
+	return v.of([]string{"components"}, c, func() []check {
+		var checks []check
+
+		for _, name := range slices.Sorted(maps.Keys(c.Schemas)) {
+			if ref := c.Schemas[name]; ref != nil {
+				path := []string{"components", "schemas", name}
+				checks = append(checks, v.of(path, ref, func() []check {
+					if ref.Value == nil {
+						return nil
+					}
+					return v.schemaParts(path, ref.Value)
+				}))
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(c.Parameters)) {
+			if ref := c.Parameters[name]; ref != nil {
+				checks = append(checks, v.parameter([]string{"components", "parameters", name}, ref))
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(c.RequestBodies)) {
+			if ref := c.RequestBodies[name]; ref != nil {
+				path := []string{"components", "requestBodies", name}
+				checks = append(checks, v.of(path, ref, func() []check {
+					if ref.Value == nil {
+						return nil
+					}
+					return v.content(At(path, "content"), ref.Value.Content)
+				}))
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(c.Responses)) {
+			if ref := c.Responses[name]; ref != nil {
+				checks = append(checks, v.response([]string{"components", "responses", name}, ref))
+			}
+		}
+
+		checks = append(checks, componentChecks(v, "headers", c.Headers)...)
+		checks = append(checks, componentChecks(v, "securitySchemes", c.SecuritySchemes)...)
+		checks = append(checks, componentChecks(v, "examples", c.Examples)...)
+		checks = append(checks, componentChecks(v, "links", c.Links)...)
+		checks = append(checks, componentChecks(v, "callbacks", c.Callbacks)...)
+
+		return checks
+	})
+}
+
+// componentChecks checks each component of a section as a whole.
+func componentChecks[M ~map[string]V, V validatable](v *documentValidator, section string, components M) []check {
+	var checks []check
+
+	for _, name := range slices.Sorted(maps.Keys(components)) {
+		checks = append(checks, v.of([]string{"components", section, name}, components[name], nil))
+	}
+
+	return checks
+}
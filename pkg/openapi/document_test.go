@@ -0,0 +1,119 @@
+package openapi
+
+import (
+	"testing"
+)
+
+func TestValidateDocument(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []Problem
+	}{
+		{
+			name: "valid",
+			data: `openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      responses:
+        '200': {description: ok}
+`,
+		},
+		{
+			name: "every problem",
+			data: `openapi: 3.0.3
+info:
+  title: Pets
+paths:
+  /pets/{id}:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: integer}
+          example: ten
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  id: {type: integer, example: x}
+  pets:
+    get:
+      responses:
+        '200': {description: ok}
+components:
+  schemas:
+    Pet:
+      type: objekt
+`,
+			want: []Problem{
+				{Message: "value of version must be a non-empty string", Pointer: "/info", Location: Location{Line: 2, Column: 1}},
+				{Message: "operation GET /pets/{id} must define exactly all path parameters (missing: [id])", Pointer: "/paths/~1pets~1{id}", Location: Location{Line: 5, Column: 3}},
+				{Message: "invalid example: value must be an integer", Pointer: "/paths/~1pets~1{id}/get/parameters/0", Location: Location{Line: 8, Column: 11}},
+				{Message: "invalid example: value must be an integer", Pointer: "/paths/~1pets~1{id}/get/responses/200/content/application~1json/schema/properties/id", Location: Location{Line: 20, Column: 19}},
+				{Message: `path "pets" does not start with a forward slash (/)`, Pointer: "/paths/pets", Location: Location{Line: 21, Column: 3}},
+				{Message: `unsupported 'type' value "objekt"`, Pointer: "/components/schemas/Pet", Location: Location{Line: 27, Column: 5}},
+			},
+		},
+		{
+			name: "swagger definitions",
+			data: `swagger: "2.0"
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      responses:
+        '200': {description: ok}
+definitions:
+  Pet:
+    type: objekt
+`,
+			want: []Problem{
+				{Message: `unsupported 'type' value "objekt"`, Pointer: "/definitions/Pet", Location: Location{Line: 9, Column: 3}},
+			},
+		},
+		{
+			name: "syntax error",
+			data: "openapi: 3.0.3\ninfo: [\n",
+			want: []Problem{
+				{Message: "failed to parse document: yaml: line 2: did not find expected node content", Location: Location{Line: 2}},
+			},
+		},
+		{
+			name: "unresolved reference",
+			data: `openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      responses:
+        '200': {$ref: '#/components/responses/Missing'}
+`,
+			want: []Problem{
+				{Message: `failed to parse OpenAPI spec: failed to resolve "responses" in fragment in URI: "#/components/responses/Missing": not a map, slice nor struct`, Location: Location{Line: 7, Column: 23}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidateDocument([]byte(tt.data))
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("ValidateDocument() = %+v, want %+v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("problem %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package openapi
+
+import (
+	"math"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExampleMode selects which side of the API an example is generated for.
+// Request examples skip readOnly properties, response examples skip
+// writeOnly ones.
+type ExampleMode int
+
+const (
+	ExampleRequest ExampleMode = iota
+	ExampleResponse
+)
+
+// maxExampleDepth stops generation for deeply nested or recursive schemas.
+const maxExampleDepth = 8
+
+// GenerateExample builds a sample value for the schema. Values given in the
+// spec win: example, then examples, default, const and the first enum
+// value. Anything else gets a placeholder based on its type and format,
+// kept within the bounds, lengths and pattern the schema declares.
+func GenerateExample(schema *Schema, mode ExampleMode) interface{} {
+	return generateExample(schema, mode, 0)
+}
+
+func generateExample(schema *Schema, mode ExampleMode, depth int) interface{} {
+	if schema == nil || schema.Circular || depth > maxExampleDepth {
+		return nil
+	}
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case len(schema.Examples) > 0:
+		return schema.Examples[0]
+	case schema.Default != nil:
+		return schema.Default
+	case schema.Const != nil:
+		return schema.Const
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	}
+
+	if len(schema.AllOf) > 0 {
+		return mergeAllOf(schema, mode, depth)
+	}
+
+	if len(schema.OneOf) > 0 {
+		return generateExample(schema.OneOf[0], mode, depth+1)
+	}
+
+	if len(schema.AnyOf) > 0 {
+		return generateExample(schema.AnyOf[0], mode, depth+1)
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		return objectExample(schema, mode, depth)
+	case "array":
+		item := generateExample(schema.Items, mode, depth+1)
+		if item == nil {
+			return []interface{}{}
+		}
+		return []interface{}{item}
+	case "integer":
+		if schema.Minimum == nil && schema.Maximum == nil {
+			return 0
+		}
+		return int64(numberExample(schema, true))
+	case "number":
+		return numberExample(schema, false)
+	case "boolean":
+		return false
+	case "string":
+		return stringExample(schema)
+	default:
+		return nil
+	}
+}
+
+// schemaType falls back to "object" for schemas that only declare
+// properties, which is common in hand-written specs.
+func schemaType(schema *Schema) string {
+	if schema.Type != "" {
+		return schema.Type
+	}
+
+	if len(schema.Properties) > 0 || schema.AdditionalProperties != nil {
+		return "object"
+	}
+
+	if schema.Items != nil {
+		return "array"
+	}
+
+	return ""
+}
+
+func objectExample(schema *Schema, mode ExampleMode, depth int) map[string]interface{} {
+	result := make(map[string]interface{}, len(schema.Properties))
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop := schema.Properties[name]
+		if prop == nil || (mode == ExampleRequest && prop.ReadOnly) || (mode == ExampleResponse && prop.WriteOnly) {
+			continue
+		}
+
+		if value := generateExample(prop, mode, depth+1); value != nil {
+			result[name] = value
+		}
+	}
+
+	return result
+}
+
+// mergeAllOf combines the object examples of every subschema, so properties
+// from the composing schemas all end up in a single value.
+func mergeAllOf(schema *Schema, mode ExampleMode, depth int) interface{} {
+	merged := make(map[string]interface{})
+
+	if len(schema.Properties) > 0 {
+		for k, v := range objectExample(schema, mode, depth) {
+			merged[k] = v
+		}
+	}
+
+	for _, sub := range schema.AllOf {
+		value := generateExample(sub, mode, depth+1)
+
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			if len(schema.AllOf) == 1 && len(merged) == 0 {
+				return value
+			}
+			continue
+		}
+
+		for k, v := range obj {
+			merged[k] = v
+		}
+	}
+
+	return merged
+}
+
+// numberExample picks zero when it lies within the bounds, or else the
+// value closest to the bound it's outside of. Exclusive bounds move one
+// step inside, or halfway between both bounds when they are less than a
+// step apart.
+func numberExample(schema *Schema, integer bool) float64 {
+	above := func(v float64) bool {
+		if schema.Minimum == nil {
+			return true
+		}
+		if schema.ExclusiveMinimum {
+			return v > *schema.Minimum
+		}
+		return v >= *schema.Minimum
+	}
+	below := func(v float64) bool {
+		if schema.Maximum == nil {
+			return true
+		}
+		if schema.ExclusiveMaximum {
+			return v < *schema.Maximum
+		}
+		return v <= *schema.Maximum
+	}
+
+	var v float64
+	switch {
+	case !above(0):
+		v = *schema.Minimum
+		if integer {
+			v = math.Ceil(v)
+		}
+		if !above(v) {
+			v++
+		}
+		if !integer && !below(v) {
+			v = (*schema.Minimum + *schema.Maximum) / 2
+		}
+	case !below(0):
+		v = *schema.Maximum
+		if integer {
+			v = math.Floor(v)
+		}
+		if !below(v) {
+			v--
+		}
+	}
+
+	return v
+}
+
+// stringExample pads or cuts the placeholder for the format to the length
+// limits, and generates a value from the pattern when that doesn't match it.
+func stringExample(schema *Schema) string {
+	value := fitLength(formatExample(schema.Format), schema.MinLength, schema.MaxLength)
+
+	if schema.Pattern != "" {
+		if re, err := regexp.Compile(schema.Pattern); err == nil && !re.MatchString(value) {
+			if generated, ok := patternExample(schema, re); ok {
+				return generated
+			}
+		}
+	}
+
+	return value
+}
+
+func fitLength(value string, minLength uint64, maxLength *uint64) string {
+	for uint64(utf8.RuneCountInString(value)) < minLength {
+		value += "x"
+	}
+
+	if maxLength != nil && uint64(utf8.RuneCountInString(value)) > *maxLength {
+		value = string([]rune(value)[:*maxLength])
+	}
+
+	return value
+}
+
+// patternExample generates the shortest string matching the pattern, then
+// repeats open-ended parts more often until it also fits the length limits.
+func patternExample(schema *Schema, re *regexp.Regexp) (string, bool) {
+	parsed, err := syntax.Parse(schema.Pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	parsed = parsed.Simplify()
+
+	for repeat := 0; repeat <= int(schema.MinLength); repeat++ {
+		var b strings.Builder
+		writeMatch(&b, parsed, repeat)
+
+		value := b.String()
+		n := uint64(utf8.RuneCountInString(value))
+		if n >= schema.MinLength && (schema.MaxLength == nil || n <= *schema.MaxLength) && re.MatchString(value) {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// writeMatch writes a string matching re, repeating unbounded parts at
+// least repeat times.
+func writeMatch(b *strings.Builder, re *syntax.Regexp, repeat int) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		b.WriteRune(classRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune('a')
+	case syntax.OpCapture:
+		writeMatch(b, re.Sub[0], repeat)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeMatch(b, sub, repeat)
+		}
+	case syntax.OpAlternate:
+		writeMatch(b, re.Sub[0], repeat)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpRepeat:
+		count := repeat
+		switch {
+		case re.Op == syntax.OpPlus && count < 1:
+			count = 1
+		case re.Op == syntax.OpRepeat:
+			count = max(count, re.Min)
+			if re.Max >= 0 {
+				count = min(count, re.Max)
+			}
+		}
+		for i := 0; i < count; i++ {
+			writeMatch(b, re.Sub[0], repeat)
+		}
+	case syntax.OpQuest:
+		if repeat > 0 {
+			writeMatch(b, re.Sub[0], repeat)
+		}
+	}
+}
+
+// classRune picks a readable rune from a character class, given as pairs
+// of range bounds.
+func classRune(ranges []rune) rune {
+	for _, preferred := range []rune{'a', 'A', '0'} {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= preferred && preferred <= ranges[i+1] {
+				return preferred
+			}
+		}
+	}
+
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := ranges[i]; r <= ranges[i+1] && r-ranges[i] < 128; r++ {
+			if unicode.IsGraphic(r) && !unicode.IsSpace(r) {
+				return r
+			}
+		}
+	}
+
+	if len(ranges) == 0 {
+		return 'a'
+	}
+
+	return ranges[0]
+}
+
+func formatExample(format string) string {
+	switch format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "time":
+		return "00:00:00"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "uri", "url":
+		return "https://example.com"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "192.0.2.1"
+	case "ipv6":
+		return "2001:db8::1"
+	case "byte":
+		return "c3RyaW5n"
+	case "password":
+		return "password"
+	default:
+		return "string"
+	}
+}
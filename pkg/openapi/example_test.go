@@ -0,0 +1,174 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+func TestGenerateExample(t *testing.T) {
+	minimum := 5.0
+
+	tests := []struct {
+		name     string
+		schema   *Schema
+		mode     ExampleMode
+		expected interface{}
+	}{
+		{name: "nil schema", schema: nil, expected: nil},
+		{name: "example wins", schema: &Schema{Type: "string", Example: "rex", Default: "x"}, expected: "rex"},
+		{name: "examples", schema: &Schema{Type: "string", Examples: []interface{}{"a", "b"}}, expected: "a"},
+		{name: "default", schema: &Schema{Type: "integer", Default: 3}, expected: 3},
+		{name: "const", schema: &Schema{Type: "string", Const: "fixed"}, expected: "fixed"},
+		{name: "enum", schema: &Schema{Type: "string", Enum: []interface{}{"available", "sold"}}, expected: "available"},
+		{name: "string format", schema: &Schema{Type: "string", Format: "email"}, expected: "user@example.com"},
+		{name: "plain string", schema: &Schema{Type: "string"}, expected: "string"},
+		{name: "integer minimum", schema: &Schema{Type: "integer", Minimum: &minimum}, expected: int64(5)},
+		{name: "boolean", schema: &Schema{Type: "boolean"}, expected: false},
+		{name: "array", schema: &Schema{Type: "array", Items: &Schema{Type: "integer"}}, expected: []interface{}{0}},
+		{name: "circular stub", schema: &Schema{Circular: true}, expected: nil},
+		{
+			name: "request skips readOnly",
+			schema: &Schema{Type: "object", Properties: map[string]*Schema{
+				"id":       {Type: "integer", ReadOnly: true},
+				"name":     {Type: "string", Example: "doggie"},
+				"password": {Type: "string", WriteOnly: true},
+			}},
+			mode:     ExampleRequest,
+			expected: map[string]interface{}{"name": "doggie", "password": "string"},
+		},
+		{
+			name: "response skips writeOnly",
+			schema: &Schema{Type: "object", Properties: map[string]*Schema{
+				"id":       {Type: "integer", ReadOnly: true},
+				"password": {Type: "string", WriteOnly: true},
+			}},
+			mode:     ExampleResponse,
+			expected: map[string]interface{}{"id": 0},
+		},
+		{
+			name: "allOf merges objects",
+			schema: &Schema{AllOf: []*Schema{
+				{Type: "object", Properties: map[string]*Schema{"id": {Type: "integer"}}},
+				{Type: "object", Properties: map[string]*Schema{"name": {Type: "string"}}},
+			}},
+			expected: map[string]interface{}{"id": 0, "name": "string"},
+		},
+		{
+			name:     "oneOf picks the first",
+			schema:   &Schema{OneOf: []*Schema{{Type: "boolean"}, {Type: "string"}}},
+			expected: false,
+		},
+		{
+			name:     "properties without type",
+			schema:   &Schema{Properties: map[string]*Schema{"ok": {Type: "boolean"}}},
+			expected: map[string]interface{}{"ok": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GenerateExample(tt.schema, tt.mode)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("GenerateExample() = %#v, want %#v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateExampleConstraints(t *testing.T) {
+	float := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name   string
+		schema *openapi3.Schema
+	}{
+		{name: "integer maximum below zero", schema: openapi3.NewIntegerSchema().WithMax(-3)},
+		{name: "integer exclusive minimum", schema: &openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: float(2), ExclusiveMin: true}},
+		{name: "integer exclusive maximum", schema: &openapi3.Schema{Type: &openapi3.Types{"integer"}, Max: float(-1), ExclusiveMax: true}},
+		{name: "integer fractional minimum", schema: openapi3.NewIntegerSchema().WithMin(2.5)},
+		{name: "number exclusive minimum", schema: &openapi3.Schema{Type: &openapi3.Types{"number"}, Min: float(0), ExclusiveMin: true}},
+		{name: "number narrow exclusive range", schema: &openapi3.Schema{Type: &openapi3.Types{"number"}, Min: float(0), Max: float(0.5), ExclusiveMin: true, ExclusiveMax: true}},
+		{name: "number exclusive maximum", schema: &openapi3.Schema{Type: &openapi3.Types{"number"}, Max: float(0), ExclusiveMax: true}},
+		{name: "string minLength", schema: openapi3.NewStringSchema().WithMinLength(10)},
+		{name: "string maxLength", schema: openapi3.NewStringSchema().WithMaxLength(3)},
+		{name: "pattern", schema: openapi3.NewStringSchema().WithPattern(`^[A-Z]{3}-\d{4}$`)},
+		{name: "pattern with alternation", schema: openapi3.NewStringSchema().WithPattern(`^(cat|dog)s?$`)},
+		{name: "pattern with lengths", schema: openapi3.NewStringSchema().WithPattern(`^[a-f0-9]+$`).WithMinLength(8).WithMaxLength(8)},
+		{name: "pattern with negated class", schema: openapi3.NewStringSchema().WithPattern(`^[^a-z]+$`)},
+		{name: "unanchored pattern", schema: openapi3.NewStringSchema().WithPattern(`v\d+`)},
+		{name: "format and pattern", schema: openapi3.NewStringSchema().WithFormat("email").WithPattern(`@example\.org$`)},
+		{
+			name: "object properties",
+			schema: openapi3.NewObjectSchema().
+				WithProperty("code", openapi3.NewStringSchema().WithPattern(`^[0-9]{5}$`)).
+				WithProperty("count", openapi3.NewIntegerSchema().WithMin(1).WithMax(10)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := GenerateExample(convertSchema(openapi3.NewSchemaRef("", tt.schema)), ExampleRequest)
+			if violations := ValidateValue(tt.schema, value); len(violations) > 0 {
+				t.Errorf("GenerateExample() = %#v doesn't match its schema: %v", value, violations)
+			}
+		})
+	}
+}
+
+func TestConvertMediaTypeExample(t *testing.T) {
+	data := []byte(`openapi: 3.0.3
+info: {title: Test, version: "1.0"}
+paths:
+  /pets:
+    post:
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: integer}
+          example: 5
+        - name: X-Trace
+          in: header
+          schema: {type: string}
+          examples:
+            b: {value: second}
+            a: {value: first}
+      requestBody:
+        content:
+          application/json:
+            schema: {type: object}
+            examples:
+              b: {value: {name: second}}
+              a: {value: {name: first}}
+          text/plain:
+            schema: {type: string}
+            example: hello
+      responses:
+        "200": {description: ok}
+`)
+
+	spec, err := LoadFromData(data)
+	if err != nil {
+		t.Fatalf("LoadFromData() error = %v", err)
+	}
+
+	params := spec.Paths[0].Operations[0].Parameters
+	if len(params) != 2 || params[0].Example != float64(5) || params[1].Example != "first" {
+		t.Errorf("parameter examples = %+v, want 5 and the first named example", params)
+	}
+
+	content := spec.Paths[0].Operations[0].RequestBody.Content
+
+	if got := content["text/plain"].Example; got != "hello" {
+		t.Errorf("text/plain example = %v, want hello", got)
+	}
+
+	if got := content["application/json"].Example; !reflect.DeepEqual(got, map[string]interface{}{"name": "first"}) {
+		t.Errorf("application/json example = %v, want the first named example", got)
+	}
+
+	if got := content["application/json"].Examples["b"]; !reflect.DeepEqual(got, map[string]interface{}{"name": "second"}) {
+		t.Errorf("application/json example b = %v, want it by name", got)
+	}
+}
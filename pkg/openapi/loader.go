@@ -1,27 +1,59 @@
 package openapi
 
 import (
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
 	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oasdiff/yaml"
 )
 
 type Spec struct {
-	Title       string
-	Version     string
+	// OpenAPIVersion is the version of the source document, e.g. "3.0.3" or
+	// "2.0" for specs that were converted from Swagger.
+	OpenAPIVersion string
+	Title          string
+	Version        string
+	Description    string
+	Servers        []Server
+	// Tags keeps the order and descriptions of the top-level tags list.
+	Tags  []Tag
+	Paths []Path
+	// Webhooks lists OpenAPI 3.1 webhooks. Path holds the webhook name.
+	Webhooks []Path
+	// SecuritySchemes is keyed by the component name used in requirements.
+	SecuritySchemes map[string]SecurityScheme
+	// Security is the document-wide default requirement list.
+	Security []SecurityRequirement
+	// SourceURL is where the spec was fetched from, empty for local files.
+	SourceURL string
+	raw       *openapi3.T
+}
+
+type Tag struct {
+	Name        string
 	Description string
-	Servers     []Server
-	Paths       []Path
-	raw         *openapi3.T
 }
 
 type Server struct {
 	URL         string
 	Description string
+	// Variables are substituted for {name} placeholders in URL.
+	Variables map[string]ServerVariable
+}
+
+type ServerVariable struct {
+	Default     string
+	Enum        []string
+	Description string
 }
 
 type Path struct {
@@ -38,6 +70,13 @@ type Operation struct {
 	RequestBody *RequestBody
 	Responses   map[string]Response
 	Tags        []string
+	// Servers overrides the document servers for this operation. It holds the
+	// operation's own servers, or the path item's, and is empty otherwise.
+	Servers []Server
+	// Security is the effective requirement list, already falling back to the
+	// document default. Any one requirement satisfies the operation; an empty
+	// non-nil slice means the operation is explicitly public.
+	Security []SecurityRequirement
 }
 
 type Parameter struct {
@@ -46,6 +85,13 @@ type Parameter struct {
 	Description string
 	Required    bool
 	Schema      *Schema
+	// Style and Explode are copied as declared. Style is empty and Explode nil
+	// when the spec relies on the defaults for the parameter location.
+	Style   string
+	Explode *bool
+	// Example is the parameter's example, or the first of its named
+	// examples. Examples in the schema aren't copied here.
+	Example interface{}
 }
 
 type RequestBody struct {
@@ -56,6 +102,11 @@ type RequestBody struct {
 
 type MediaType struct {
 	Schema *Schema
+	// Example is the media type level example, or the first of its named
+	// examples. It takes precedence over examples in the schema.
+	Example interface{}
+	// Examples holds the named examples by name.
+	Examples map[string]interface{}
 }
 
 type Response struct {
@@ -63,170 +114,627 @@ type Response struct {
 	Content     map[string]MediaType
 }
 
+// SecurityRequirement maps security scheme names to the scopes required. All
+// schemes in a single requirement must be satisfied together.
+type SecurityRequirement map[string][]string
+
+type SecurityScheme struct {
+	Name        string
+	Type        string
+	Description string
+	// In and ParamName locate the key for apiKey schemes.
+	In        string
+	ParamName string
+	// Scheme is the HTTP authorization scheme, e.g. "basic" or "bearer".
+	Scheme           string
+	BearerFormat     string
+	Flows            *OAuthFlows
+	OpenIDConnectURL string
+}
+
+type OAuthFlows struct {
+	Implicit          *OAuthFlow
+	Password          *OAuthFlow
+	ClientCredentials *OAuthFlow
+	AuthorizationCode *OAuthFlow
+}
+
+type OAuthFlow struct {
+	AuthorizationURL string
+	TokenURL         string
+	RefreshURL       string
+	Scopes           map[string]string
+}
+
 type Schema struct {
-	Type       string
-	Format     string
-	Properties map[string]*Schema
-	Required   []string
-	Example    interface{}
+	// Type is the first non-null entry of Types, kept for single-typed schemas.
+	Type        string
+	Types       []string
+	Format      string
+	Title       string
+	Description string
+	Properties  map[string]*Schema
+	Required    []string
+	Example     interface{}
+	Examples    []interface{}
+	Const       interface{}
+	Default     interface{}
+	Enum        []interface{}
+	Nullable    bool
+	ReadOnly    bool
+	WriteOnly   bool
+	Deprecated  bool
+
+	Items       *Schema
+	MinItems    uint64
+	MaxItems    *uint64
+	UniqueItems bool
+
+	OneOf         []*Schema
+	AnyOf         []*Schema
+	AllOf         []*Schema
+	Not           *Schema
+	Discriminator *Discriminator
+
+	Minimum          *float64
+	Maximum          *float64
+	ExclusiveMinimum bool
+	ExclusiveMaximum bool
+	MultipleOf       *float64
+
+	MinLength uint64
+	MaxLength *uint64
+	Pattern   string
+
+	MinProperties        uint64
+	MaxProperties        *uint64
+	AdditionalProperties *AdditionalProperties
+
+	// Defs holds 3.1 $defs; refs inside them are left unresolved.
+	Defs map[string]*Schema
+
+	// Ref holds the original $ref this schema was resolved from, if any.
+	Ref string
+	// Circular is set on the stub that stands in for a schema already being
+	// converted higher up the tree. Its children are not populated.
+	Circular bool
+}
+
+type Discriminator struct {
+	PropertyName string
+	Mapping      map[string]string
+}
+
+// AdditionalProperties mirrors the boolean-or-schema form of the keyword.
+// Allowed is nil when the keyword is absent.
+type AdditionalProperties struct {
+	Allowed *bool
+	Schema  *Schema
+}
+
+// LoadOption relaxes the checks a spec must pass to load.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	validation []openapi3.ValidationOption
+}
+
+// WithoutExampleValidation loads specs whose examples don't match their
+// schemas, for callers that report them on their own.
+func WithoutExampleValidation() LoadOption {
+	return func(o *loadOptions) {
+		o.validation = append(o.validation, openapi3.DisableExamplesValidation())
+	}
 }
 
-func LoadFromFile(path string) (*Spec, error) {
+func LoadFromFile(path string, opts ...LoadOption) (*Spec, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	return parseSpec(data)
+	return LoadFromData(data, opts...)
 }
 
-func LoadFromURL(url string) (*Spec, error) {
-	resp, err := http.Get(url)
+func LoadFromURL(rawURL string, opts ...LoadOption) (*Spec, error) {
+	data, finalURL, err := Fetch(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := LoadFromData(data, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch URL: %w", err)
+		return nil, err
+	}
+
+	// Use the final URL so relative servers follow redirects.
+	spec.SourceURL = finalURL.String()
+	spec.resolveServers(finalURL)
+
+	return spec, nil
+}
+
+// fetchClient downloads documents, the timeout keeps an unresponsive server
+// from hanging the command.
+var fetchClient = &http.Client{Timeout: 30 * time.Second}
+
+// Fetch downloads the document at rawURL and returns it with the URL it was
+// served from after redirects.
+func Fetch(rawURL string) ([]byte, *url.URL, error) {
+	resp, err := fetchClient.Get(rawURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch URL: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to fetch URL: %s", resp.Status)
+	}
+
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return data, resp.Request.URL, nil
+}
+
+// LoadFromData loads a spec from the contents of an OpenAPI 3.x or Swagger 2.0
+// document in YAML or JSON.
+func LoadFromData(data []byte, opts ...LoadOption) (*Spec, error) {
+	var options loadOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	p, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.doc.Validate(p.loader.Context, append(p.validation, options.validation...)...); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+
+	spec := convertSpec(p.doc)
+	spec.OpenAPIVersion = p.version
+
+	if p.webhooks != nil {
+		for name, item := range p.webhooks.Map() {
+			if wh := convertPath(name, item, spec.Security); len(wh.Operations) > 0 {
+				spec.Webhooks = append(spec.Webhooks, wh)
+			}
+		}
 	}
 
-	return parseSpec(data)
+	return spec, nil
 }
 
-func parseSpec(data []byte) (*Spec, error) {
+// parsed is a document loaded into the kin-openapi model, not yet validated.
+type parsed struct {
+	loader *openapi3.Loader
+	doc    *openapi3.T
+	// version is the OpenAPI or Swagger version of the source document.
+	version string
+	// validation holds the options documents of this version need to pass.
+	validation []openapi3.ValidationOption
+	// webhooks holds the OpenAPI 3.1 webhooks, which the model can't keep.
+	webhooks *openapi3.Paths
+}
+
+func parse(data []byte) (*parsed, error) {
+	var header struct {
+		Swagger string `json:"swagger"`
+		OpenAPI string `json:"openapi"`
+	}
+
+	// Errors are ignored here, the loaders below report malformed documents.
+	_ = yaml.Unmarshal(data, &header)
+
+	switch {
+	case strings.HasPrefix(header.Swagger, "2."):
+		return parseSwagger2(data)
+	case strings.HasPrefix(header.OpenAPI, "3.1"):
+		return parseOpenAPI31(data)
+	}
+
 	loader := openapi3.NewLoader()
 	loader.IsExternalRefsAllowed = true
 
-	var doc *openapi3.T
-	var err error
+	doc, err := loader.LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	return &parsed{loader: loader, doc: doc, version: doc.OpenAPI}, nil
+}
 
-	if json.Valid(data) {
-		doc, err = loader.LoadFromData(data)
-	} else {
-		doc, err = loader.LoadFromData(data)
+// parseSwagger2 upgrades a Swagger 2.0 document to OpenAPI 3 so the rest of
+// the loader only ever deals with a single model.
+func parseSwagger2(data []byte) (*parsed, error) {
+	var doc2 openapi2.T
+	if err := yaml.Unmarshal(data, &doc2); err != nil {
+		return nil, fmt.Errorf("failed to parse Swagger spec: %w", err)
 	}
 
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+
+	doc, err := openapi2conv.ToV3WithLoader(&doc2, loader, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+		return nil, fmt.Errorf("failed to convert Swagger spec: %w", err)
 	}
 
-	if err := doc.Validate(loader.Context); err != nil {
-		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	// Without a host the converter drops basePath, keep it as a relative server.
+	if doc2.Host == "" && doc2.BasePath != "" {
+		doc.AddServer(&openapi3.Server{URL: doc2.BasePath})
 	}
 
-	return convertSpec(doc), nil
+	return &parsed{loader: loader, doc: doc, version: doc2.Swagger}, nil
+}
+
+// Document returns the kin-openapi model the spec was converted from, for
+// checks that need more of the document than Spec keeps.
+func (s *Spec) Document() *openapi3.T {
+	return s.raw
 }
 
 func convertSpec(doc *openapi3.T) *Spec {
 	spec := &Spec{
-		Title:       doc.Info.Title,
-		Version:     doc.Info.Version,
-		Description: doc.Info.Description,
-		raw:         doc,
+		OpenAPIVersion: doc.OpenAPI,
+		Title:          doc.Info.Title,
+		Version:        doc.Info.Version,
+		Description:    doc.Info.Description,
+		raw:            doc,
 	}
 
-	for _, server := range doc.Servers {
-		spec.Servers = append(spec.Servers, Server{
-			URL:         server.URL,
-			Description: server.Description,
-		})
+	spec.Servers = convertServers(doc.Servers)
+
+	if doc.Components != nil && len(doc.Components.SecuritySchemes) > 0 {
+		spec.SecuritySchemes = make(map[string]SecurityScheme)
+		for name, ref := range doc.Components.SecuritySchemes {
+			if ref != nil && ref.Value != nil {
+				spec.SecuritySchemes[name] = convertSecurityScheme(name, ref.Value)
+			}
+		}
 	}
 
+	for _, tag := range doc.Tags {
+		if tag != nil {
+			spec.Tags = append(spec.Tags, Tag{Name: tag.Name, Description: tag.Description})
+		}
+	}
+
+	spec.Security = convertSecurityRequirements(doc.Security)
+
 	for path, pathItem := range doc.Paths.Map() {
-		p := Path{Path: path}
+		if p := convertPath(path, pathItem, spec.Security); len(p.Operations) > 0 {
+			spec.Paths = append(spec.Paths, p)
+		}
+	}
 
-		for method, op := range pathItem.Operations() {
-			if op == nil {
-				continue
-			}
+	return spec
+}
+
+func convertPath(path string, pathItem *openapi3.PathItem, security []SecurityRequirement) Path {
+	p := Path{Path: path}
+
+	for method, op := range pathItem.Operations() {
+		if op == nil {
+			continue
+		}
 
-			operation := Operation{
-				Method:      method,
-				Summary:     op.Summary,
-				Description: op.Description,
-				OperationID: op.OperationID,
-				Tags:        op.Tags,
+		operation := Operation{
+			Method:      method,
+			Summary:     op.Summary,
+			Description: op.Description,
+			OperationID: op.OperationID,
+			Tags:        op.Tags,
+			Security:    security,
+		}
+
+		switch {
+		case op.Servers != nil && len(*op.Servers) > 0:
+			operation.Servers = convertServers(*op.Servers)
+		case len(pathItem.Servers) > 0:
+			operation.Servers = convertServers(pathItem.Servers)
+		}
+
+		if op.Security != nil {
+			operation.Security = convertSecurityRequirements(*op.Security)
+			if operation.Security == nil {
+				operation.Security = []SecurityRequirement{}
 			}
+		}
 
-			for _, param := range op.Parameters {
-				if param.Value != nil {
-					operation.Parameters = append(operation.Parameters, Parameter{
-						Name:        param.Value.Name,
-						In:          param.Value.In,
-						Description: param.Value.Description,
-						Required:    param.Value.Required,
-						Schema:      convertSchema(param.Value.Schema),
-					})
+		for _, param := range op.Parameters {
+			if param.Value != nil {
+				example := param.Value.Example
+				if example == nil {
+					example = firstExample(namedExamples(param.Value.Examples))
 				}
+
+				operation.Parameters = append(operation.Parameters, Parameter{
+					Name:        param.Value.Name,
+					In:          param.Value.In,
+					Description: param.Value.Description,
+					Required:    param.Value.Required,
+					Schema:      convertSchema(param.Value.Schema),
+					Style:       param.Value.Style,
+					Explode:     param.Value.Explode,
+					Example:     example,
+				})
 			}
+		}
 
-			if op.RequestBody != nil && op.RequestBody.Value != nil {
-				rb := &RequestBody{
-					Description: op.RequestBody.Value.Description,
-					Required:    op.RequestBody.Value.Required,
-					Content:     make(map[string]MediaType),
-				}
-				for contentType, mediaType := range op.RequestBody.Value.Content {
-					rb.Content[contentType] = MediaType{
-						Schema: convertSchema(mediaType.Schema),
+		if op.RequestBody != nil && op.RequestBody.Value != nil {
+			rb := &RequestBody{
+				Description: op.RequestBody.Value.Description,
+				Required:    op.RequestBody.Value.Required,
+				Content:     make(map[string]MediaType),
+			}
+			for contentType, mediaType := range op.RequestBody.Value.Content {
+				rb.Content[contentType] = convertMediaType(mediaType)
+			}
+			operation.RequestBody = rb
+		}
+
+		operation.Responses = make(map[string]Response)
+		if op.Responses != nil {
+			for status, resp := range op.Responses.Map() {
+				if resp.Value != nil {
+					r := Response{
+						Description: *resp.Value.Description,
+						Content:     make(map[string]MediaType),
 					}
+					for contentType, mediaType := range resp.Value.Content {
+						r.Content[contentType] = convertMediaType(mediaType)
+					}
+					operation.Responses[status] = r
 				}
-				operation.RequestBody = rb
 			}
+		}
 
-			operation.Responses = make(map[string]Response)
-			if op.Responses != nil {
-				for status, resp := range op.Responses.Map() {
-					if resp.Value != nil {
-						r := Response{
-							Description: *resp.Value.Description,
-							Content:     make(map[string]MediaType),
-						}
-						for contentType, mediaType := range resp.Value.Content {
-							r.Content[contentType] = MediaType{
-								Schema: convertSchema(mediaType.Schema),
-							}
-						}
-						operation.Responses[status] = r
-					}
+		p.Operations = append(p.Operations, operation)
+	}
+
+	return p
+}
+
+func convertServers(servers openapi3.Servers) []Server {
+	var result []Server
+	for _, server := range servers {
+		if server == nil {
+			continue
+		}
+
+		s := Server{
+			URL:         server.URL,
+			Description: server.Description,
+		}
+
+		if len(server.Variables) > 0 {
+			s.Variables = make(map[string]ServerVariable, len(server.Variables))
+			for name, v := range server.Variables {
+				if v != nil {
+					s.Variables[name] = ServerVariable{Default: v.Default, Enum: v.Enum, Description: v.Description}
 				}
 			}
+		}
+
+		result = append(result, s)
+	}
+
+	return result
+}
+
+func convertMediaType(mt *openapi3.MediaType) MediaType {
+	result := MediaType{
+		Schema:  convertSchema(mt.Schema),
+		Example: mt.Example,
+	}
 
-			p.Operations = append(p.Operations, operation)
+	result.Examples = namedExamples(mt.Examples)
+	if result.Example == nil {
+		result.Example = firstExample(result.Examples)
+	}
+
+	return result
+}
+
+// namedExamples returns the values of examples by name, nil when there are
+// none.
+func namedExamples(examples openapi3.Examples) map[string]interface{} {
+	var result map[string]interface{}
+
+	for name, ex := range examples {
+		if ex != nil && ex.Value != nil && ex.Value.Value != nil {
+			if result == nil {
+				result = make(map[string]interface{}, len(examples))
+			}
+			result[name] = ex.Value.Value
 		}
+	}
 
-		if len(p.Operations) > 0 {
-			spec.Paths = append(spec.Paths, p)
+	return result
+}
+
+// firstExample picks the example first by name, so the choice is stable.
+func firstExample(examples map[string]interface{}) interface{} {
+	names := make([]string, 0, len(examples))
+	for name := range examples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	return examples[names[0]]
+}
+
+func convertSecurityScheme(name string, s *openapi3.SecurityScheme) SecurityScheme {
+	scheme := SecurityScheme{
+		Name:             name,
+		Type:             s.Type,
+		Description:      s.Description,
+		In:               s.In,
+		ParamName:        s.Name,
+		Scheme:           s.Scheme,
+		BearerFormat:     s.BearerFormat,
+		OpenIDConnectURL: s.OpenIdConnectUrl,
+	}
+
+	if s.Flows != nil {
+		scheme.Flows = &OAuthFlows{
+			Implicit:          convertOAuthFlow(s.Flows.Implicit),
+			Password:          convertOAuthFlow(s.Flows.Password),
+			ClientCredentials: convertOAuthFlow(s.Flows.ClientCredentials),
+			AuthorizationCode: convertOAuthFlow(s.Flows.AuthorizationCode),
 		}
 	}
 
-	return spec
+	return scheme
+}
+
+func convertOAuthFlow(f *openapi3.OAuthFlow) *OAuthFlow {
+	if f == nil {
+		return nil
+	}
+
+	return &OAuthFlow{
+		AuthorizationURL: f.AuthorizationURL,
+		TokenURL:         f.TokenURL,
+		RefreshURL:       f.RefreshURL,
+		Scopes:           f.Scopes,
+	}
+}
+
+func convertSecurityRequirements(reqs openapi3.SecurityRequirements) []SecurityRequirement {
+	if len(reqs) == 0 {
+		return nil
+	}
+
+	result := make([]SecurityRequirement, 0, len(reqs))
+	for _, req := range reqs {
+		result = append(result, SecurityRequirement(req))
+	}
+
+	return result
 }
 
 func convertSchema(schemaRef *openapi3.SchemaRef) *Schema {
+	return convertSchemaVisiting(schemaRef, make(map[*openapi3.Schema]bool))
+}
+
+// convertSchemaVisiting converts schemaRef while tracking the schemas on the
+// current branch, so that recursive $refs end in a Circular stub instead of
+// recursing forever.
+func convertSchemaVisiting(schemaRef *openapi3.SchemaRef, visiting map[*openapi3.Schema]bool) *Schema {
 	if schemaRef == nil || schemaRef.Value == nil {
 		return nil
 	}
 
 	s := schemaRef.Value
+	var types []string
+	if s.Type != nil {
+		types = s.Type.Slice()
+	}
+
 	schemaType := ""
-	if s.Type != nil && len(*s.Type) > 0 {
-		schemaType = (*s.Type)[0]
+	if len(types) > 0 {
+		schemaType = types[0]
 	}
-	
+
+	if visiting[s] {
+		return &Schema{
+			Type:     schemaType,
+			Title:    s.Title,
+			Ref:      schemaRef.Ref,
+			Circular: true,
+		}
+	}
+
+	visiting[s] = true
+	defer delete(visiting, s)
+
 	schema := &Schema{
-		Type:       schemaType,
-		Format:     s.Format,
-		Properties: make(map[string]*Schema),
-		Required:   s.Required,
-		Example:    s.Example,
+		Type:             schemaType,
+		Types:            types,
+		Format:           s.Format,
+		Title:            s.Title,
+		Description:      s.Description,
+		Properties:       make(map[string]*Schema),
+		Required:         s.Required,
+		Example:          s.Example,
+		Const:            s.Extensions["const"],
+		Default:          s.Default,
+		Enum:             s.Enum,
+		Nullable:         s.Nullable,
+		ReadOnly:         s.ReadOnly,
+		WriteOnly:        s.WriteOnly,
+		Deprecated:       s.Deprecated,
+		MinItems:         s.MinItems,
+		MaxItems:         s.MaxItems,
+		UniqueItems:      s.UniqueItems,
+		Minimum:          s.Min,
+		Maximum:          s.Max,
+		ExclusiveMinimum: s.ExclusiveMin,
+		ExclusiveMaximum: s.ExclusiveMax,
+		MultipleOf:       s.MultipleOf,
+		MinLength:        s.MinLength,
+		MaxLength:        s.MaxLength,
+		Pattern:          s.Pattern,
+		MinProperties:    s.MinProps,
+		MaxProperties:    s.MaxProps,
+		Ref:              schemaRef.Ref,
 	}
 
 	for name, propRef := range s.Properties {
-		schema.Properties[name] = convertSchema(propRef)
+		schema.Properties[name] = convertSchemaVisiting(propRef, visiting)
+	}
+
+	schema.Items = convertSchemaVisiting(s.Items, visiting)
+	schema.Not = convertSchemaVisiting(s.Not, visiting)
+	schema.OneOf = convertSchemaList(s.OneOf, visiting)
+	schema.AnyOf = convertSchemaList(s.AnyOf, visiting)
+	schema.AllOf = convertSchemaList(s.AllOf, visiting)
+
+	if s.AdditionalProperties.Has != nil || s.AdditionalProperties.Schema != nil {
+		schema.AdditionalProperties = &AdditionalProperties{
+			Allowed: s.AdditionalProperties.Has,
+			Schema:  convertSchemaVisiting(s.AdditionalProperties.Schema, visiting),
+		}
+	}
+
+	if examples, ok := s.Extensions["examples"].([]interface{}); ok {
+		schema.Examples = examples
+	}
+
+	schema.Defs = convertDefs(s.Extensions, visiting)
+
+	if s.Discriminator != nil {
+		schema.Discriminator = &Discriminator{
+			PropertyName: s.Discriminator.PropertyName,
+			Mapping:      s.Discriminator.Mapping,
+		}
 	}
 
 	return schema
 }
+
+func convertSchemaList(refs openapi3.SchemaRefs, visiting map[*openapi3.Schema]bool) []*Schema {
+	if len(refs) == 0 {
+		return nil
+	}
+
+	schemas := make([]*Schema, 0, len(refs))
+	for _, ref := range refs {
+		if converted := convertSchemaVisiting(ref, visiting); converted != nil {
+			schemas = append(schemas, converted)
+		}
+	}
+
+	return schemas
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -127,13 +128,39 @@ func TestLoadFromURLInvalidURL(t *testing.T) {
 	}
 }
 
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest":
+			http.Redirect(w, r, "/v2/openapi.yaml", http.StatusFound)
+		case "/v2/openapi.yaml":
+			_, _ = w.Write([]byte("openapi: 3.0.3"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	data, finalURL, err := Fetch(server.URL + "/latest")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if string(data) != "openapi: 3.0.3" || finalURL.Path != "/v2/openapi.yaml" {
+		t.Errorf("Fetch() = %q, %v, want the redirected document", data, finalURL)
+	}
+
+	if _, _, err := Fetch(server.URL + "/missing"); err == nil || !strings.Contains(err.Error(), "404 Not Found") {
+		t.Errorf("Fetch() error = %v, want the status", err)
+	}
+}
+
 func TestConvertSpec(t *testing.T) {
 	data, err := os.ReadFile("../../example-petstore.yaml")
 	if err != nil {
 		t.Fatalf("Failed to read test file: %v", err)
 	}
 
-	spec, err := parseSpec(data)
+	spec, err := LoadFromData(data)
 	if err != nil {
 		t.Fatalf("Failed to parse spec: %v", err)
 	}
@@ -172,7 +199,7 @@ func TestConvertSpecOperations(t *testing.T) {
 		t.Fatalf("Failed to read test file: %v", err)
 	}
 
-	spec, err := parseSpec(data)
+	spec, err := LoadFromData(data)
 	if err != nil {
 		t.Fatalf("Failed to parse spec: %v", err)
 	}
@@ -217,19 +244,48 @@ func TestConvertSchemaWithNilRef(t *testing.T) {
 
 func TestParseSpecInvalidData(t *testing.T) {
 	invalidData := []byte("this is not valid yaml or json")
-	_, err := parseSpec(invalidData)
+	_, err := LoadFromData(invalidData)
 	if err == nil {
 		t.Error("Expected error for invalid data")
 	}
 }
 
+func TestLoadWithoutExampleValidation(t *testing.T) {
+	data := []byte(`openapi: 3.0.3
+info: {title: Pets, version: "1.0"}
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: integer}
+          example: ten
+      responses:
+        '200': {description: ok}
+`)
+
+	if _, err := LoadFromData(data); err == nil {
+		t.Error("Expected error for an example that doesn't match its schema")
+	}
+
+	spec, err := LoadFromData(data, WithoutExampleValidation())
+	if err != nil {
+		t.Fatalf("LoadFromData() error = %v", err)
+	}
+
+	if spec.Document() == nil {
+		t.Error("Expected the loaded document")
+	}
+}
+
 func TestServerConversion(t *testing.T) {
 	data, err := os.ReadFile("../../example-petstore.yaml")
 	if err != nil {
 		t.Fatalf("Failed to read test file: %v", err)
 	}
 
-	spec, err := parseSpec(data)
+	spec, err := LoadFromData(data)
 	if err != nil {
 		t.Fatalf("Failed to parse spec: %v", err)
 	}
@@ -250,7 +306,7 @@ func TestOperationTags(t *testing.T) {
 		t.Fatalf("Failed to read test file: %v", err)
 	}
 
-	spec, err := parseSpec(data)
+	spec, err := LoadFromData(data)
 	if err != nil {
 		t.Fatalf("Failed to parse spec: %v", err)
 	}
@@ -269,3 +325,377 @@ func TestOperationTags(t *testing.T) {
 		t.Log("Note: No tags found in operations (not an error, just informational)")
 	}
 }
+
+func TestSpecTags(t *testing.T) {
+	data := []byte(`
+openapi: 3.0.3
+info: {title: Test, version: "1.0"}
+tags:
+  - name: pets
+    description: Everything about pets
+  - name: store
+paths: {}
+`)
+
+	spec, err := LoadFromData(data)
+	if err != nil {
+		t.Fatalf("LoadFromData() error = %v", err)
+	}
+
+	expected := []Tag{{Name: "pets", Description: "Everything about pets"}, {Name: "store"}}
+	if len(spec.Tags) != len(expected) {
+		t.Fatalf("Tags = %+v, want %+v", spec.Tags, expected)
+	}
+
+	for i, tag := range expected {
+		if spec.Tags[i] != tag {
+			t.Errorf("Tags[%d] = %+v, want %+v", i, spec.Tags[i], tag)
+		}
+	}
+}
+
+func TestConvertSchemaFullModel(t *testing.T) {
+	data := []byte(`
+openapi: 3.0.3
+info:
+  title: Schema API
+  version: 1.0.0
+paths:
+  /nodes:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Node'
+      responses:
+        '200':
+          description: OK
+components:
+  schemas:
+    Node:
+      type: object
+      required: [name]
+      additionalProperties: false
+      properties:
+        name:
+          type: string
+          minLength: 1
+          maxLength: 64
+          pattern: '^[a-z]+$'
+        kind:
+          type: string
+          enum: [leaf, branch]
+          default: leaf
+        weight:
+          type: number
+          minimum: 0
+          maximum: 1
+          exclusiveMaximum: true
+          nullable: true
+        id:
+          type: integer
+          readOnly: true
+        children:
+          type: array
+          minItems: 1
+          items:
+            $ref: '#/components/schemas/Node'
+        shape:
+          oneOf:
+            - type: string
+            - type: integer
+        labels:
+          type: object
+          additionalProperties:
+            type: string
+`)
+
+	spec, err := LoadFromData(data)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	body := spec.Paths[0].Operations[0].RequestBody.Content["application/json"].Schema
+	if body == nil {
+		t.Fatal("Expected request body schema")
+	}
+
+	if body.Ref != "#/components/schemas/Node" {
+		t.Errorf("Expected ref to Node, got %q", body.Ref)
+	}
+
+	if body.AdditionalProperties == nil || body.AdditionalProperties.Allowed == nil || *body.AdditionalProperties.Allowed {
+		t.Error("Expected additionalProperties: false")
+	}
+
+	name := body.Properties["name"]
+	if name.MinLength != 1 || name.MaxLength == nil || *name.MaxLength != 64 || name.Pattern != "^[a-z]+$" {
+		t.Errorf("String constraints not converted: %+v", name)
+	}
+
+	kind := body.Properties["kind"]
+	if len(kind.Enum) != 2 || kind.Default != "leaf" {
+		t.Errorf("Enum/default not converted: %+v", kind)
+	}
+
+	weight := body.Properties["weight"]
+	if !weight.Nullable || weight.Minimum == nil || weight.Maximum == nil || !weight.ExclusiveMaximum {
+		t.Errorf("Number constraints not converted: %+v", weight)
+	}
+
+	if !body.Properties["id"].ReadOnly {
+		t.Error("Expected id to be readOnly")
+	}
+
+	children := body.Properties["children"]
+	if children.Items == nil || children.MinItems != 1 {
+		t.Fatalf("Array items not converted: %+v", children)
+	}
+
+	if !children.Items.Circular {
+		t.Error("Expected recursive reference to be marked circular")
+	}
+
+	if len(body.Properties["shape"].OneOf) != 2 {
+		t.Errorf("Expected 2 oneOf variants, got %d", len(body.Properties["shape"].OneOf))
+	}
+
+	labels := body.Properties["labels"]
+	if labels.AdditionalProperties == nil || labels.AdditionalProperties.Schema == nil || labels.AdditionalProperties.Schema.Type != "string" {
+		t.Error("Expected additionalProperties schema to be converted")
+	}
+}
+
+func TestParseSpecSwagger2(t *testing.T) {
+	data := []byte(`
+swagger: "2.0"
+info:
+  title: Legacy API
+  version: 2.1.0
+host: legacy.example.com
+basePath: /v1
+schemes: [https, http]
+consumes: [application/json]
+produces: [application/json]
+securityDefinitions:
+  apiKey:
+    type: apiKey
+    in: header
+    name: X-API-Key
+paths:
+  /pets:
+    post:
+      operationId: createPet
+      parameters:
+        - name: X-Request-Id
+          in: header
+          type: string
+        - name: body
+          in: body
+          required: true
+          schema:
+            $ref: '#/definitions/Pet'
+      responses:
+        "201":
+          description: Created
+          schema:
+            $ref: '#/definitions/Pet'
+  /pets/{id}/photo:
+    post:
+      operationId: uploadPhoto
+      consumes: [multipart/form-data]
+      parameters:
+        - name: id
+          in: path
+          required: true
+          type: integer
+        - name: file
+          in: formData
+          type: file
+          required: true
+        - name: caption
+          in: formData
+          type: string
+      responses:
+        "200":
+          description: OK
+definitions:
+  Pet:
+    type: object
+    required: [name]
+    properties:
+      name:
+        type: string
+      tags:
+        type: array
+        items:
+          type: string
+`)
+
+	spec, err := LoadFromData(data)
+	if err != nil {
+		t.Fatalf("Failed to parse Swagger 2.0 spec: %v", err)
+	}
+
+	if spec.OpenAPIVersion != "2.0" {
+		t.Errorf("Expected OpenAPIVersion 2.0, got %q", spec.OpenAPIVersion)
+	}
+
+	if spec.Title != "Legacy API" {
+		t.Errorf("Expected title 'Legacy API', got %q", spec.Title)
+	}
+
+	if len(spec.Servers) != 2 || spec.Servers[0].URL != "https://legacy.example.com/v1" {
+		t.Errorf("Expected servers built from host/basePath/schemes, got %+v", spec.Servers)
+	}
+
+	if spec.raw.Components.SecuritySchemes["apiKey"] == nil {
+		t.Error("Expected securityDefinitions to be converted to security schemes")
+	}
+
+	ops := make(map[string]Operation)
+	for _, path := range spec.Paths {
+		for _, op := range path.Operations {
+			ops[op.OperationID] = op
+		}
+	}
+
+	create := ops["createPet"]
+	if len(create.Parameters) != 1 || create.Parameters[0].In != "header" {
+		t.Errorf("Expected only the header parameter to remain, got %+v", create.Parameters)
+	}
+
+	if create.RequestBody == nil || create.RequestBody.Content["application/json"].Schema == nil {
+		t.Fatal("Expected body parameter to become a JSON request body")
+	}
+
+	if _, ok := create.RequestBody.Content["application/json"].Schema.Properties["tags"]; !ok {
+		t.Error("Expected request body schema to resolve the Pet definition")
+	}
+
+	upload := ops["uploadPhoto"]
+	if upload.RequestBody == nil {
+		t.Fatal("Expected formData parameters to become a request body")
+	}
+
+	form, ok := upload.RequestBody.Content["multipart/form-data"]
+	if !ok || form.Schema == nil || form.Schema.Properties["caption"] == nil {
+		t.Errorf("Expected multipart/form-data body with caption field, got %+v", upload.RequestBody.Content)
+	}
+}
+
+func TestParseSpecSwagger2WithoutHost(t *testing.T) {
+	data := []byte(`{
+		"swagger": "2.0",
+		"info": {"title": "No Host", "version": "1.0"},
+		"basePath": "/api",
+		"paths": {
+			"/ping": {"get": {"responses": {"200": {"description": "OK"}}}}
+		}
+	}`)
+
+	spec, err := LoadFromData(data)
+	if err != nil {
+		t.Fatalf("Failed to parse Swagger 2.0 spec: %v", err)
+	}
+
+	if len(spec.Servers) != 1 || spec.Servers[0].URL != "/api" {
+		t.Errorf("Expected basePath to become a relative server, got %+v", spec.Servers)
+	}
+}
+
+func TestConvertSecurity(t *testing.T) {
+	data := []byte(`
+openapi: 3.0.3
+info:
+  title: Secure API
+  version: 1.0.0
+security:
+  - apiKey: []
+paths:
+  /public:
+    get:
+      security: []
+      responses:
+        '200':
+          description: OK
+  /default:
+    get:
+      responses:
+        '200':
+          description: OK
+  /oauth:
+    get:
+      security:
+        - oauth: [read]
+        - basic: []
+      responses:
+        '200':
+          description: OK
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: query
+      name: api_key
+    basic:
+      type: http
+      scheme: basic
+    oauth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://auth.example.com/token
+          scopes:
+            read: Read access
+    oidc:
+      type: openIdConnect
+      openIdConnectUrl: https://auth.example.com/.well-known/openid-configuration
+`)
+
+	spec, err := LoadFromData(data)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	if len(spec.SecuritySchemes) != 4 {
+		t.Fatalf("Expected 4 security schemes, got %d", len(spec.SecuritySchemes))
+	}
+
+	apiKey := spec.SecuritySchemes["apiKey"]
+	if apiKey.Name != "apiKey" || apiKey.In != "query" || apiKey.ParamName != "api_key" {
+		t.Errorf("Unexpected apiKey scheme: %+v", apiKey)
+	}
+
+	oauth := spec.SecuritySchemes["oauth"]
+	if oauth.Flows == nil || oauth.Flows.ClientCredentials == nil || oauth.Flows.ClientCredentials.TokenURL != "https://auth.example.com/token" {
+		t.Errorf("Unexpected oauth flows: %+v", oauth.Flows)
+	}
+
+	if spec.SecuritySchemes["oidc"].OpenIDConnectURL == "" {
+		t.Error("Expected openIdConnect URL")
+	}
+
+	security := make(map[string][]SecurityRequirement)
+	for _, path := range spec.Paths {
+		security[path.Path] = path.Operations[0].Security
+	}
+
+	if s := security["/public"]; s == nil || len(s) != 0 {
+		t.Errorf("Expected explicit empty security for /public, got %#v", s)
+	}
+
+	if s := security["/default"]; len(s) != 1 || !hasScheme(s[0], "apiKey") {
+		t.Errorf("Expected /default to inherit document security, got %#v", s)
+	}
+
+	if s := security["/oauth"]; len(s) != 2 || s[0]["oauth"][0] != "read" {
+		t.Errorf("Expected /oauth to override security, got %#v", s)
+	}
+}
+
+func hasScheme(req SecurityRequirement, name string) bool {
+	_, ok := req[name]
+	return ok
+}
@@ -0,0 +1,199 @@
+package openapi
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// FindOperation looks an operation up by its operationId or by
+// "METHOD /path", where the path is the template as written in the spec.
+func (s *Spec) FindOperation(ref string) (*Path, *Operation, error) {
+	ref = strings.TrimSpace(ref)
+
+	method, path, hasPath := strings.Cut(ref, " ")
+	path = strings.TrimSpace(path)
+
+	for i := range s.Paths {
+		p := &s.Paths[i]
+		for j := range p.Operations {
+			op := &p.Operations[j]
+
+			if op.OperationID != "" && op.OperationID == ref {
+				return p, op, nil
+			}
+
+			if hasPath && p.Path == path && strings.EqualFold(op.Method, method) {
+				return p, op, nil
+			}
+		}
+	}
+
+	return nil, nil, fmt.Errorf("operation %q not found", ref)
+}
+
+// OperationMatch is an operation found for a concrete request path.
+type OperationMatch struct {
+	Path      *Path
+	Operation *Operation
+	// PathParams are the values of the template's path parameters.
+	PathParams map[string]string
+	// BasePath is the part of the request path in front of the template,
+	// usually the server's base path.
+	BasePath string
+}
+
+// MatchOperation finds the operation a request for method and urlPath would
+// be sent to. The template may match any tail of the path, so server base
+// paths don't need to be known. When several templates match, the one with
+// the most literal segments wins, so /pets/mine beats /pets/{petId}.
+func (s *Spec) MatchOperation(method, urlPath string) (*OperationMatch, error) {
+	segments := strings.Split(strings.Trim(urlPath, "/"), "/")
+
+	var (
+		best      *OperationMatch
+		bestScore [2]int
+		pathFound bool
+	)
+
+	for i := range s.Paths {
+		p := &s.Paths[i]
+
+		template := strings.Split(strings.Trim(p.Path, "/"), "/")
+		if len(template) > len(segments) {
+			continue
+		}
+
+		offset := len(segments) - len(template)
+
+		params, literals, ok := matchSegments(template, segments[offset:])
+		if !ok {
+			continue
+		}
+		pathFound = true
+
+		score := [2]int{literals, len(template)}
+		if best != nil && (score[0] < bestScore[0] || score[0] == bestScore[0] && score[1] <= bestScore[1]) {
+			continue
+		}
+
+		for j := range p.Operations {
+			if strings.EqualFold(p.Operations[j].Method, method) {
+				best = &OperationMatch{Path: p, Operation: &p.Operations[j], PathParams: params}
+				if offset > 0 {
+					best.BasePath = "/" + strings.Join(segments[:offset], "/")
+				}
+				bestScore = score
+				break
+			}
+		}
+	}
+
+	if best == nil {
+		if pathFound {
+			return nil, fmt.Errorf("path %s has no %s operation", urlPath, strings.ToUpper(method))
+		}
+		return nil, fmt.Errorf("no operation matches %s %s", strings.ToUpper(method), urlPath)
+	}
+
+	return best, nil
+}
+
+// matchSegments matches path segments against template segments, returning
+// the path parameter values and the number of segments without parameters.
+func matchSegments(template, segments []string) (map[string]string, int, bool) {
+	params := make(map[string]string)
+	literals := 0
+
+	for i, t := range template {
+		segment, err := url.PathUnescape(segments[i])
+		if err != nil {
+			segment = segments[i]
+		}
+
+		if !strings.Contains(t, "{") {
+			if t != segment {
+				return nil, 0, false
+			}
+			literals++
+			continue
+		}
+
+		re, names := segmentPattern(t)
+
+		m := re.FindStringSubmatch(segment)
+		if m == nil {
+			return nil, 0, false
+		}
+		for j, name := range names {
+			params[name] = m[j+1]
+		}
+	}
+
+	return params, literals, true
+}
+
+// segmentPattern turns a template segment such as "{id}.json" into a
+// regular expression capturing each parameter.
+func segmentPattern(t string) (*regexp.Regexp, []string) {
+	var (
+		pattern strings.Builder
+		names   []string
+	)
+
+	pattern.WriteString("^")
+
+	for t != "" {
+		start := strings.Index(t, "{")
+		end := strings.Index(t, "}")
+		if start < 0 || end < start {
+			pattern.WriteString(regexp.QuoteMeta(t))
+			break
+		}
+
+		pattern.WriteString(regexp.QuoteMeta(t[:start]))
+		pattern.WriteString("(.+?)")
+		names = append(names, t[start+1:end])
+		t = t[end+1:]
+	}
+
+	pattern.WriteString("$")
+
+	return regexp.MustCompile(pattern.String()), names
+}
+
+// ParamKey is what the value of param is keyed by in saved requests and
+// name=value flags: its name, or "in:name" like "query:id" when another
+// parameter of the operation has the same name.
+func (op *Operation) ParamKey(param Parameter) string {
+	if op.sharedName(param.Name) {
+		return param.In + ":" + param.Name
+	}
+	return param.Name
+}
+
+// ParamValue returns the value of param from values, keyed by ParamKey or
+// "in:name", along with the key it was found under.
+func (op *Operation) ParamValue(values map[string]string, param Parameter) (string, string, bool) {
+	for _, key := range []string{param.In + ":" + param.Name, op.ParamKey(param)} {
+		if value, ok := values[key]; ok {
+			return value, key, true
+		}
+	}
+
+	return "", "", false
+}
+
+// sharedName reports whether parameters in more than one location are
+// called name.
+func (op *Operation) sharedName(name string) bool {
+	count := 0
+	for _, param := range op.Parameters {
+		if param.Name == name {
+			count++
+		}
+	}
+
+	return count > 1
+}
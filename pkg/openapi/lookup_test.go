@@ -0,0 +1,152 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindOperation(t *testing.T) {
+	spec := &Spec{
+		Paths: []Path{
+			{Path: "/pets", Operations: []Operation{{Method: "GET", OperationID: "listPets"}, {Method: "POST"}}},
+			{Path: "/pets/{petId}", Operations: []Operation{{Method: "GET", OperationID: "showPetById"}}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		ref      string
+		wantPath string
+		wantOp   string
+		wantErr  bool
+	}{
+		{name: "by operationId", ref: "showPetById", wantPath: "/pets/{petId}", wantOp: "GET"},
+		{name: "by method and path", ref: "POST /pets", wantPath: "/pets", wantOp: "POST"},
+		{name: "method is case-insensitive", ref: "get /pets", wantPath: "/pets", wantOp: "GET"},
+		{name: "unknown operationId", ref: "deletePet", wantErr: true},
+		{name: "unknown method", ref: "DELETE /pets", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, op, err := spec.FindOperation(tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Expected error but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if path.Path != tt.wantPath || op.Method != tt.wantOp {
+				t.Errorf("FindOperation() = %s %s, want %s %s", op.Method, path.Path, tt.wantOp, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestMatchOperation(t *testing.T) {
+	spec := &Spec{
+		Paths: []Path{
+			{Path: "/pets", Operations: []Operation{{Method: "GET"}, {Method: "POST"}}},
+			{Path: "/pets/{petId}", Operations: []Operation{{Method: "GET"}, {Method: "DELETE"}}},
+			{Path: "/pets/mine", Operations: []Operation{{Method: "GET"}}},
+			{Path: "/files/{name}.{ext}", Operations: []Operation{{Method: "GET"}}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		urlPath    string
+		wantPath   string
+		wantParams map[string]string
+		wantBase   string
+		wantErr    string
+	}{
+		{name: "literal path", method: "GET", urlPath: "/pets", wantPath: "/pets"},
+		{name: "path parameter", method: "GET", urlPath: "/pets/10", wantPath: "/pets/{petId}", wantParams: map[string]string{"petId": "10"}},
+		{name: "escaped parameter", method: "DELETE", urlPath: "/pets/a%20b", wantPath: "/pets/{petId}", wantParams: map[string]string{"petId": "a b"}},
+		{name: "literal beats parameter", method: "get", urlPath: "/pets/mine", wantPath: "/pets/mine"},
+		{name: "parameter when the literal lacks the method", method: "DELETE", urlPath: "/pets/mine", wantPath: "/pets/{petId}", wantParams: map[string]string{"petId": "mine"}},
+		{name: "base path", method: "POST", urlPath: "/api/v3/pets/", wantPath: "/pets", wantBase: "/api/v3"},
+		{name: "several parameters in a segment", method: "GET", urlPath: "/files/report.tar.gz", wantPath: "/files/{name}.{ext}", wantParams: map[string]string{"name": "report", "ext": "tar.gz"}},
+		{name: "unknown method", method: "PUT", urlPath: "/pets", wantErr: "path /pets has no PUT operation"},
+		{name: "unknown path", method: "GET", urlPath: "/stores", wantErr: "no operation matches GET /stores"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, err := spec.MatchOperation(tt.method, tt.urlPath)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("MatchOperation() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("MatchOperation() error = %v", err)
+			}
+
+			if match.Path.Path != tt.wantPath || !strings.EqualFold(match.Operation.Method, tt.method) {
+				t.Errorf("MatchOperation() = %s %s, want %s %s", match.Operation.Method, match.Path.Path, tt.method, tt.wantPath)
+			}
+
+			if len(match.PathParams) != len(tt.wantParams) {
+				t.Errorf("PathParams = %v, want %v", match.PathParams, tt.wantParams)
+			}
+			for name, want := range tt.wantParams {
+				if match.PathParams[name] != want {
+					t.Errorf("PathParams[%s] = %q, want %q", name, match.PathParams[name], want)
+				}
+			}
+
+			if match.BasePath != tt.wantBase {
+				t.Errorf("BasePath = %q, want %q", match.BasePath, tt.wantBase)
+			}
+		})
+	}
+}
+
+func TestParamValue(t *testing.T) {
+	op := &Operation{Parameters: []Parameter{
+		{Name: "id", In: "path"},
+		{Name: "id", In: "query"},
+		{Name: "limit", In: "query"},
+	}}
+	pathID, queryID, limit := op.Parameters[0], op.Parameters[1], op.Parameters[2]
+
+	if got := op.ParamKey(queryID); got != "query:id" {
+		t.Errorf("ParamKey() = %q, want query:id for a shared name", got)
+	}
+	if got := op.ParamKey(limit); got != "limit" {
+		t.Errorf("ParamKey() = %q, want the bare name", got)
+	}
+
+	values := map[string]string{"path:id": "1", "query:id": "2", "id": "3", "query:limit": "10"}
+
+	tests := []struct {
+		param     Parameter
+		wantValue string
+		wantKey   string
+	}{
+		{pathID, "1", "path:id"},
+		{queryID, "2", "query:id"},
+		{limit, "10", "query:limit"},
+	}
+
+	for _, tt := range tests {
+		value, key, ok := op.ParamValue(values, tt.param)
+		if !ok || value != tt.wantValue || key != tt.wantKey {
+			t.Errorf("ParamValue(%s %s) = %q, %q, %v, want %q under %q", tt.param.In, tt.param.Name, value, key, ok, tt.wantValue, tt.wantKey)
+		}
+	}
+
+	if _, _, ok := op.ParamValue(map[string]string{"id": "3"}, pathID); ok {
+		t.Error("ParamValue() should not match a shared name without its location")
+	}
+}
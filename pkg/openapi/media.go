@@ -0,0 +1,66 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// IsJSON reports whether the content type is JSON, application/json or a
+// +json suffix type like application/problem+json.
+func IsJSON(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// SortedContentTypes lists the media types of a body with JSON first, as
+// that's what most APIs expect by default.
+func SortedContentTypes(content map[string]MediaType) []string {
+	types := make([]string, 0, len(content))
+	for contentType := range content {
+		types = append(types, contentType)
+	}
+
+	sort.Slice(types, func(i, j int) bool {
+		iJSON, jJSON := IsJSON(types[i]), IsJSON(types[j])
+		if iJSON != jJSON {
+			return iJSON
+		}
+		return types[i] < types[j]
+	})
+
+	return types
+}
+
+// FormatExample renders an example in a form that matches the content type:
+// a urlencoded form, plain text for strings, or indented JSON.
+func FormatExample(contentType string, example interface{}) (string, error) {
+	if example == nil {
+		return "", nil
+	}
+
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		if obj, ok := example.(map[string]interface{}); ok {
+			form := url.Values{}
+			for key, value := range obj {
+				form.Set(key, fmt.Sprint(value))
+			}
+			return form.Encode(), nil
+		}
+	}
+
+	if s, ok := example.(string); ok && !IsJSON(contentType) {
+		return s, nil
+	}
+
+	data, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode example: %w", err)
+	}
+
+	return string(data), nil
+}
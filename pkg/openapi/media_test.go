@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsJSON(t *testing.T) {
+	tests := []struct {
+		contentType string
+		expected    bool
+	}{
+		{"application/json", true},
+		{"application/json; charset=utf-8", true},
+		{"application/problem+json", true},
+		{"Application/JSON", true},
+		{"text/plain", false},
+		{"application/x-www-form-urlencoded", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsJSON(tt.contentType); got != tt.expected {
+			t.Errorf("IsJSON(%q) = %v, want %v", tt.contentType, got, tt.expected)
+		}
+	}
+}
+
+func TestSortedContentTypes(t *testing.T) {
+	content := map[string]MediaType{
+		"text/plain":                        {},
+		"application/xml":                   {},
+		"application/problem+json":          {},
+		"application/json":                  {},
+		"application/x-www-form-urlencoded": {},
+	}
+
+	expected := []string{"application/json", "application/problem+json", "application/x-www-form-urlencoded", "application/xml", "text/plain"}
+	if got := SortedContentTypes(content); !reflect.DeepEqual(got, expected) {
+		t.Errorf("SortedContentTypes() = %v, want %v", got, expected)
+	}
+}
+
+func TestFormatExample(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		example     interface{}
+		expected    string
+	}{
+		{"nil", "application/json", nil, ""},
+		{"json object", "application/json", map[string]interface{}{"name": "rex"}, "{\n  \"name\": \"rex\"\n}"},
+		{"json string", "application/json", "rex", `"rex"`},
+		{"form", "application/x-www-form-urlencoded", map[string]interface{}{"name": "rex", "age": 3}, "age=3&name=rex"},
+		{"text", "text/plain", "hello", "hello"},
+		{"text number", "text/plain", 42, "42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatExample(tt.contentType, tt.example)
+			if err != nil {
+				t.Fatalf("FormatExample() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("FormatExample() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oasdiff/yaml"
+)
+
+// openAPI31Keywords are 3.1 / JSON Schema 2020-12 keywords that kin-openapi
+// does not model and would otherwise reject as unknown sibling fields.
+var openAPI31Keywords = []string{
+	"$schema", "$id", "$anchor", "$comment", "$defs", "$dynamicAnchor", "$dynamicRef",
+	"const", "examples", "prefixItems", "contains", "minContains", "maxContains",
+	"if", "then", "else", "dependentRequired", "dependentSchemas",
+	"patternProperties", "propertyNames", "unevaluatedItems", "unevaluatedProperties",
+	"contentMediaType", "contentEncoding", "contentSchema",
+	"summary", "identifier", "pathItems", "jsonSchemaDialect",
+}
+
+// schemaValueKeys hold instance data rather than nested schemas, so the
+// normalizer must not rewrite anything below them.
+var schemaValueKeys = map[string]bool{
+	"example":  true,
+	"examples": true,
+	"default":  true,
+	"const":    true,
+	"enum":     true,
+}
+
+// parseOpenAPI31 rewrites the parts of a 3.1 document that kin-openapi can't
+// represent into their 3.0 equivalents, loads it, and then loads webhooks as
+// a separate set of path items against the same components.
+func parseOpenAPI31(data []byte) (*parsed, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	normalizeSchemas31(raw)
+
+	webhooks, _ := raw["webhooks"].(map[string]interface{})
+	delete(raw, "webhooks")
+
+	// Paths are optional in 3.1 but required by the 3.0 model.
+	if _, ok := raw["paths"]; !ok {
+		raw["paths"] = map[string]interface{}{}
+	}
+
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+
+	doc, err := loadRaw(loader, raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	p := &parsed{
+		loader:     loader,
+		doc:        doc,
+		version:    doc.OpenAPI,
+		validation: []openapi3.ValidationOption{openapi3.AllowExtraSiblingFields(openAPI31Keywords...)},
+	}
+
+	if len(webhooks) == 0 {
+		return p, nil
+	}
+
+	raw["paths"] = webhooks
+
+	webhookDoc, err := loadRaw(loader, raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhooks: %w", err)
+	}
+
+	p.webhooks = webhookDoc.Paths
+
+	return p, nil
+}
+
+func loadRaw(loader *openapi3.Loader, raw map[string]interface{}) (*openapi3.T, error) {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return loader.LoadFromData(data)
+}
+
+// normalizeSchemas31 walks the generic document and rewrites schema keywords
+// whose 3.1 form is incompatible with the 3.0 model:
+//   - "null" in a type array becomes nullable: true
+//   - numeric exclusiveMinimum/exclusiveMaximum become minimum/maximum plus
+//     the boolean flag
+func normalizeSchemas31(node interface{}) {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		normalizeTypeArray(v)
+		normalizeExclusiveBound(v, "exclusiveMinimum", "minimum")
+		normalizeExclusiveBound(v, "exclusiveMaximum", "maximum")
+
+		for key, child := range v {
+			if !schemaValueKeys[key] {
+				normalizeSchemas31(child)
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			normalizeSchemas31(child)
+		}
+	}
+}
+
+func normalizeTypeArray(schema map[string]interface{}) {
+	types, ok := schema["type"].([]interface{})
+	if !ok {
+		if t, isString := schema["type"].(string); isString && t == "null" {
+			delete(schema, "type")
+			schema["nullable"] = true
+		}
+		return
+	}
+
+	kept := make([]interface{}, 0, len(types))
+	for _, t := range types {
+		if t == "null" {
+			schema["nullable"] = true
+			continue
+		}
+		kept = append(kept, t)
+	}
+
+	if len(kept) == 0 {
+		delete(schema, "type")
+		return
+	}
+
+	schema["type"] = kept
+}
+
+func normalizeExclusiveBound(schema map[string]interface{}, exclusiveKey, boundKey string) {
+	bound, ok := schema[exclusiveKey].(float64)
+	if !ok {
+		return
+	}
+
+	schema[boundKey] = bound
+	schema[exclusiveKey] = true
+}
+
+// convertDefs converts the $defs of a 3.1 schema. The entries are not
+// resolved by the loader, so $refs inside them stay unresolved.
+func convertDefs(extensions map[string]interface{}, visiting map[*openapi3.Schema]bool) map[string]*Schema {
+	defs, ok := extensions["$defs"].(map[string]interface{})
+	if !ok || len(defs) == 0 {
+		return nil
+	}
+
+	result := make(map[string]*Schema, len(defs))
+	for name, def := range defs {
+		data, err := json.Marshal(def)
+		if err != nil {
+			continue
+		}
+
+		var s openapi3.Schema
+		if err := json.Unmarshal(data, &s); err != nil {
+			continue
+		}
+
+		if converted := convertSchemaVisiting(&openapi3.SchemaRef{Value: &s}, visiting); converted != nil {
+			result[name] = converted
+		}
+	}
+
+	return result
+}
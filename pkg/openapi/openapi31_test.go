@@ -0,0 +1,193 @@
+package openapi
+
+import "testing"
+
+const openAPI31Spec = `
+openapi: 3.1.0
+info:
+  title: Modern API
+  summary: Uses 3.1 features
+  version: 1.0.0
+paths:
+  /items:
+    get:
+      operationId: listItems
+      parameters:
+        - name: cursor
+          in: query
+          schema:
+            type: [string, "null"]
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+webhooks:
+  itemCreated:
+    post:
+      operationId: onItemCreated
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Item'
+      responses:
+        "200":
+          description: Acknowledged
+components:
+  schemas:
+    Item:
+      type: object
+      $defs:
+        Code:
+          type: string
+          pattern: '^[A-Z]+$'
+      properties:
+        kind:
+          const: item
+        id:
+          type: [integer, string]
+        score:
+          type: number
+          exclusiveMinimum: 0
+        tags:
+          type: array
+          items:
+            type: string
+          examples:
+            - [red, blue]
+        type:
+          type: string
+`
+
+func TestParseSpecOpenAPI31(t *testing.T) {
+	spec, err := LoadFromData([]byte(openAPI31Spec))
+	if err != nil {
+		t.Fatalf("Failed to parse 3.1 spec: %v", err)
+	}
+
+	if spec.OpenAPIVersion != "3.1.0" {
+		t.Errorf("Expected OpenAPIVersion 3.1.0, got %q", spec.OpenAPIVersion)
+	}
+
+	if len(spec.Paths) != 1 {
+		t.Fatalf("Expected 1 path, got %d", len(spec.Paths))
+	}
+
+	op := spec.Paths[0].Operations[0]
+	cursor := op.Parameters[0].Schema
+	if cursor.Type != "string" || !cursor.Nullable {
+		t.Errorf("Expected nullable string for [string, null], got type=%q nullable=%v", cursor.Type, cursor.Nullable)
+	}
+
+	item := op.Responses["200"].Content["application/json"].Schema
+	if item == nil {
+		t.Fatal("Expected response schema")
+	}
+
+	if item.Properties["kind"].Const != "item" {
+		t.Errorf("Expected const 'item', got %v", item.Properties["kind"].Const)
+	}
+
+	id := item.Properties["id"]
+	if len(id.Types) != 2 || id.Types[0] != "integer" || id.Types[1] != "string" {
+		t.Errorf("Expected multi-type [integer string], got %v", id.Types)
+	}
+
+	score := item.Properties["score"]
+	if score.Minimum == nil || *score.Minimum != 0 || !score.ExclusiveMinimum {
+		t.Errorf("Expected numeric exclusiveMinimum to become minimum+flag, got %+v", score)
+	}
+
+	if len(item.Properties["tags"].Examples) != 1 {
+		t.Errorf("Expected schema examples array, got %v", item.Properties["tags"].Examples)
+	}
+
+	if item.Properties["type"] == nil || item.Properties["type"].Type != "string" {
+		t.Error("Expected property named 'type' to be left intact")
+	}
+
+	code := item.Defs["Code"]
+	if code == nil || code.Pattern != "^[A-Z]+$" {
+		t.Errorf("Expected $defs to be converted, got %+v", item.Defs)
+	}
+
+	if len(spec.Webhooks) != 1 || spec.Webhooks[0].Path != "itemCreated" {
+		t.Fatalf("Expected itemCreated webhook, got %+v", spec.Webhooks)
+	}
+
+	hook := spec.Webhooks[0].Operations[0]
+	if hook.OperationID != "onItemCreated" || hook.RequestBody == nil {
+		t.Fatalf("Expected webhook operation with body, got %+v", hook)
+	}
+
+	if hook.RequestBody.Content["application/json"].Schema.Properties["id"] == nil {
+		t.Error("Expected webhook schema refs to resolve against components")
+	}
+}
+
+func TestParseSpecOpenAPI31WebhooksOnly(t *testing.T) {
+	data := []byte(`{
+		"openapi": "3.1.0",
+		"info": {"title": "Hooks", "version": "1"},
+		"webhooks": {
+			"ping": {"post": {"responses": {"200": {"description": "OK"}}}}
+		}
+	}`)
+
+	spec, err := LoadFromData(data)
+	if err != nil {
+		t.Fatalf("Failed to parse webhooks-only spec: %v", err)
+	}
+
+	if len(spec.Paths) != 0 {
+		t.Errorf("Expected no paths, got %d", len(spec.Paths))
+	}
+
+	if len(spec.Webhooks) != 1 {
+		t.Errorf("Expected 1 webhook, got %d", len(spec.Webhooks))
+	}
+}
+
+func TestNormalizeTypeArray(t *testing.T) {
+	tests := []struct {
+		name         string
+		schema       map[string]interface{}
+		wantType     interface{}
+		wantNullable bool
+	}{
+		{"nullable string", map[string]interface{}{"type": []interface{}{"string", "null"}}, []interface{}{"string"}, true},
+		{"only null", map[string]interface{}{"type": "null"}, nil, true},
+		{"plain string", map[string]interface{}{"type": "string"}, "string", false},
+		{"multi type", map[string]interface{}{"type": []interface{}{"integer", "string"}}, []interface{}{"integer", "string"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalizeTypeArray(tt.schema)
+
+			gotType := tt.schema["type"]
+			switch want := tt.wantType.(type) {
+			case nil:
+				if gotType != nil {
+					t.Errorf("type = %v, want none", gotType)
+				}
+			case string:
+				if gotType != want {
+					t.Errorf("type = %v, want %v", gotType, want)
+				}
+			case []interface{}:
+				got, ok := gotType.([]interface{})
+				if !ok || len(got) != len(want) {
+					t.Errorf("type = %v, want %v", gotType, want)
+				}
+			}
+
+			if nullable, _ := tt.schema["nullable"].(bool); nullable != tt.wantNullable {
+				t.Errorf("nullable = %v, want %v", nullable, tt.wantNullable)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package openapi
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ResolveURL substitutes server variables in the URL. Values that are
+// missing or empty fall back to the variable's default.
+func (s Server) ResolveURL(values map[string]string) string {
+	url := s.URL
+	for name, variable := range s.Variables {
+		value := values[name]
+		if value == "" {
+			value = variable.Default
+		}
+		url = strings.ReplaceAll(url, "{"+name+"}", value)
+	}
+
+	return url
+}
+
+// VariableNames lists the server variables in a stable order.
+func (s Server) VariableNames() []string {
+	names := make([]string, 0, len(s.Variables))
+	for name := range s.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ServersFor returns the servers an operation is sent to: its own override
+// when it has one, the document servers otherwise.
+func (s *Spec) ServersFor(op *Operation) []Server {
+	if op != nil && len(op.Servers) > 0 {
+		return op.Servers
+	}
+
+	return s.Servers
+}
+
+// resolveServers makes relative server URLs absolute against the URL the
+// spec was loaded from. A spec without servers defaults to "/", which is
+// the host it was loaded from.
+func (s *Spec) resolveServers(source *url.URL) {
+	if len(s.Servers) == 0 {
+		s.Servers = []Server{{URL: "/", Description: "Host the spec was loaded from"}}
+	}
+
+	resolveAll := func(servers []Server) {
+		for i := range servers {
+			servers[i].URL = resolveServerURL(source, servers[i].URL)
+		}
+	}
+
+	resolveAll(s.Servers)
+
+	for _, paths := range [][]Path{s.Paths, s.Webhooks} {
+		for i := range paths {
+			for j := range paths[i].Operations {
+				resolveAll(paths[i].Operations[j].Servers)
+			}
+		}
+	}
+}
+
+// resolveServerURL leaves absolute URLs alone, as well as URLs starting with
+// a variable such as "{base}/v1": the variable may hold a scheme and host,
+// which is only known once it's substituted.
+func resolveServerURL(base *url.URL, raw string) string {
+	if strings.Contains(raw, "://") || strings.HasPrefix(raw, "{") {
+		return raw
+	}
+
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	resolved := base.ResolveReference(ref)
+
+	// Build from the decoded path so {variable} placeholders aren't escaped.
+	return resolved.Scheme + "://" + resolved.Host + resolved.Path
+}
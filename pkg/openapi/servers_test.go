@@ -0,0 +1,195 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServerResolveURL(t *testing.T) {
+	server := Server{
+		URL: "https://{region}.api.example.com/{version}",
+		Variables: map[string]ServerVariable{
+			"region":  {Default: "eu", Enum: []string{"eu", "us"}},
+			"version": {Default: "v1"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		values   map[string]string
+		expected string
+	}{
+		{name: "defaults", values: nil, expected: "https://eu.api.example.com/v1"},
+		{name: "override", values: map[string]string{"region": "us"}, expected: "https://us.api.example.com/v1"},
+		{name: "empty value uses default", values: map[string]string{"region": "", "version": "v2"}, expected: "https://eu.api.example.com/v2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := server.ResolveURL(tt.values); got != tt.expected {
+				t.Errorf("ResolveURL() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if got := server.VariableNames(); !reflect.DeepEqual(got, []string{"region", "version"}) {
+		t.Errorf("VariableNames() = %v", got)
+	}
+}
+
+func TestServerOverrides(t *testing.T) {
+	data := []byte(`
+openapi: 3.0.3
+info: {title: Test, version: "1.0"}
+servers:
+  - url: https://{env}.example.com
+    variables:
+      env:
+        default: prod
+        enum: [prod, staging]
+        description: Deployment
+paths:
+  /pets:
+    servers:
+      - url: https://pets.example.com
+    get:
+      responses:
+        "200": {description: ok}
+    post:
+      servers:
+        - url: https://write.example.com
+      responses:
+        "200": {description: ok}
+  /store:
+    get:
+      responses:
+        "200": {description: ok}
+`)
+
+	spec, err := LoadFromData(data)
+	if err != nil {
+		t.Fatalf("LoadFromData() error = %v", err)
+	}
+
+	env := spec.Servers[0].Variables["env"]
+	if env.Default != "prod" || !reflect.DeepEqual(env.Enum, []string{"prod", "staging"}) || env.Description != "Deployment" {
+		t.Errorf("server variable = %+v", env)
+	}
+
+	servers := make(map[string]string)
+	for _, path := range spec.Paths {
+		for i := range path.Operations {
+			op := &path.Operations[i]
+			servers[op.Method+" "+path.Path] = spec.ServersFor(op)[0].URL
+		}
+	}
+
+	expected := map[string]string{
+		"GET /pets":  "https://pets.example.com",
+		"POST /pets": "https://write.example.com",
+		"GET /store": "https://{env}.example.com",
+	}
+
+	if !reflect.DeepEqual(servers, expected) {
+		t.Errorf("servers = %v, want %v", servers, expected)
+	}
+}
+
+func TestLoadFromURLResolvesServers(t *testing.T) {
+	specWithServers := func(servers string) string {
+		return `
+openapi: 3.0.3
+info: {title: Test, version: "1.0"}
+` + servers + `
+paths:
+  /pets:
+    get:
+      servers:
+        - url: ../pets
+      responses:
+        "200": {description: ok}
+`
+	}
+
+	tests := []struct {
+		name     string
+		servers  string
+		expected []string
+	}{
+		{name: "no servers", servers: "", expected: []string{"{host}/"}},
+		{name: "absolute path", servers: "servers: [{url: /api/v3}]", expected: []string{"{host}/api/v3"}},
+		{name: "relative path", servers: "servers: [{url: v1}]", expected: []string{"{host}/specs/v1"}},
+		{name: "variables kept", servers: "servers: [{url: '/{version}', variables: {version: {default: v2}}}]", expected: []string{"{host}/{version}"}},
+		{name: "absolute URL", servers: "servers: [{url: 'https://api.example.com'}]", expected: []string{"https://api.example.com"}},
+		{name: "leading variable kept", servers: "servers: [{url: '{base}/v1', variables: {base: {default: 'https://api.example.com'}}}]", expected: []string{"{base}/v1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "/specs/openapi.yaml", http.StatusFound)
+			})
+			mux.HandleFunc("/specs/openapi.yaml", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(specWithServers(tt.servers)))
+			})
+
+			server := httptest.NewServer(mux)
+			defer server.Close()
+
+			spec, err := LoadFromURL(server.URL + "/openapi.yaml")
+			if err != nil {
+				t.Fatalf("LoadFromURL() error = %v", err)
+			}
+
+			if spec.SourceURL != server.URL+"/specs/openapi.yaml" {
+				t.Errorf("SourceURL = %q, want the redirected URL", spec.SourceURL)
+			}
+
+			got := make([]string, 0, len(spec.Servers))
+			for _, s := range spec.Servers {
+				got = append(got, s.URL)
+			}
+
+			expected := make([]string, 0, len(tt.expected))
+			for _, e := range tt.expected {
+				expected = append(expected, strings.ReplaceAll(e, "{host}", server.URL))
+			}
+
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("Servers = %v, want %v", got, expected)
+			}
+
+			if op := spec.Paths[0].Operations[0]; op.Servers[0].URL != server.URL+"/pets" {
+				t.Errorf("operation server = %q, want %q", op.Servers[0].URL, server.URL+"/pets")
+			}
+		})
+	}
+}
+
+func TestLoadFromFileKeepsRelativeServers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	data := `
+openapi: 3.0.3
+info: {title: Test, version: "1.0"}
+servers: [{url: /api}]
+paths: {}
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile() error = %v", err)
+	}
+
+	if spec.SourceURL != "" || spec.Servers[0].URL != "/api" {
+		t.Errorf("local spec should keep servers as declared, got %q from %q", spec.Servers[0].URL, spec.SourceURL)
+	}
+}
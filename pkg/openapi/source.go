@@ -0,0 +1,215 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source maps locations in a spec document back to lines in the YAML or JSON
+// text it was read from.
+type Source struct {
+	root *yaml.Node
+	// swagger is set for Swagger 2.0 documents, which keep components
+	// elsewhere than the model they're converted to.
+	swagger bool
+}
+
+// Location is a 1-based position in a source document. Zero means unknown.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (l Location) String() string {
+	if l.Column == 0 {
+		return strconv.Itoa(l.Line)
+	}
+
+	return fmt.Sprintf("%d:%d", l.Line, l.Column)
+}
+
+func ParseSource(data []byte) (*Source, error) {
+	// JSON is YAML apart from tabs, which YAML doesn't allow as indentation.
+	// Outside of strings JSON only has them as whitespace, and inside strings
+	// they're escaped, so spaces keep every position the same.
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		data = bytes.ReplaceAll(data, []byte("\t"), []byte(" "))
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse document: %w", err)
+	}
+
+	source := &Source{root: &root}
+	if doc := resolve(&root); doc != nil {
+		if version, _ := child(doc, "swagger"); version != nil {
+			source.swagger = strings.HasPrefix(version.Value, "2.")
+		}
+	}
+
+	return source, nil
+}
+
+// Locate finds the node at the path of keys and indexes in the OpenAPI 3
+// model, like "paths", "/pets", "get". A mapping entry is located at its key.
+// When the path goes past what the document has, like into a $ref or a part
+// the loader made up, it gives the location of the deepest node that exists.
+func (s *Source) Locate(path ...string) Location {
+	path = s.sourcePath(path)
+	node := s.root
+	loc := Location{}
+
+	for _, segment := range path {
+		node = resolve(node)
+		if node == nil {
+			break
+		}
+
+		var key *yaml.Node
+		node, key = child(node, segment)
+		if node == nil {
+			break
+		}
+
+		if key == nil {
+			key = node
+		}
+		loc = Location{Line: key.Line, Column: key.Column}
+	}
+
+	if loc.Line == 0 {
+		if root := resolve(s.root); root != nil {
+			loc = Location{Line: root.Line, Column: root.Column}
+		}
+	}
+
+	return loc
+}
+
+// Pointer is the JSON pointer to the path of keys and indexes in the OpenAPI 3
+// model, pointing into the source document.
+func (s *Source) Pointer(path ...string) string {
+	return JSONPointer(s.sourcePath(path)...)
+}
+
+// swaggerComponents maps the OpenAPI 3 component sections to where Swagger
+// 2.0 documents keep them.
+var swaggerComponents = map[string]string{
+	"schemas":         "definitions",
+	"parameters":      "parameters",
+	"responses":       "responses",
+	"securitySchemes": "securityDefinitions",
+}
+
+// sourcePath points a path in the model into the source document. For
+// Swagger only components move; other parts are converted too freely to map,
+// so their locations are best effort.
+func (s *Source) sourcePath(path []string) []string {
+	if !s.swagger || len(path) < 2 || path[0] != "components" {
+		return path
+	}
+
+	section, ok := swaggerComponents[path[1]]
+	if !ok {
+		return path
+	}
+
+	return append([]string{section}, path[2:]...)
+}
+
+// Refs lists the $ref values in the document, in the order they appear.
+func (s *Source) Refs() []string {
+	var refs []string
+	for _, node := range s.refNodes() {
+		refs = append(refs, node.Value)
+	}
+
+	return refs
+}
+
+// refNodes lists the values of $ref keys in the document.
+func (s *Source) refNodes() []*yaml.Node {
+	var refs []*yaml.Node
+
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == "$ref" && node.Content[i+1].Kind == yaml.ScalarNode {
+					refs = append(refs, node.Content[i+1])
+				}
+			}
+		}
+
+		for _, c := range node.Content {
+			walk(c)
+		}
+	}
+	walk(s.root)
+
+	return refs
+}
+
+// resolve skips document and alias wrappers.
+func resolve(node *yaml.Node) *yaml.Node {
+	for node != nil {
+		switch node.Kind {
+		case yaml.DocumentNode:
+			if len(node.Content) == 0 {
+				return nil
+			}
+			node = node.Content[0]
+		case yaml.AliasNode:
+			node = node.Alias
+		default:
+			return node
+		}
+	}
+
+	return nil
+}
+
+// child returns the value at segment of a mapping or sequence, and the key
+// it's under for mappings.
+func child(node *yaml.Node, segment string) (*yaml.Node, *yaml.Node) {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == segment {
+				return node.Content[i+1], node.Content[i]
+			}
+		}
+	case yaml.SequenceNode:
+		if i, err := strconv.Atoi(segment); err == nil && i >= 0 && i < len(node.Content) {
+			return node.Content[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// At extends a path of keys and indexes into a document, leaving path itself
+// untouched so sibling paths can be built from it.
+func At(path []string, segments ...string) []string {
+	return append(slices.Clip(path), segments...)
+}
+
+// JSONPointer joins a path of keys and indexes into a JSON pointer, like
+// /paths/~1pets/get.
+func JSONPointer(path ...string) string {
+	var b strings.Builder
+
+	for _, segment := range path {
+		segment = strings.ReplaceAll(segment, "~", "~0")
+		segment = strings.ReplaceAll(segment, "/", "~1")
+		b.WriteString("/" + segment)
+	}
+
+	return b.String()
+}
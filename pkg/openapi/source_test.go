@@ -0,0 +1,81 @@
+package openapi
+
+import (
+	"slices"
+	"testing"
+)
+
+const sourceYAML = `openapi: 3.0.3
+info:
+  title: Pets
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: limit
+          in: query
+        - $ref: '#/components/parameters/Offset'
+components:
+  parameters:
+    Offset:
+      name: offset
+      in: query
+`
+
+func TestSourceLocate(t *testing.T) {
+	source, err := ParseSource([]byte(sourceYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path []string
+		want Location
+	}{
+		{"root", nil, Location{Line: 1, Column: 1}},
+		{"mapping key", []string{"info", "title"}, Location{Line: 3, Column: 3}},
+		{"key with slash", []string{"paths", "/pets", "get"}, Location{Line: 6, Column: 5}},
+		{"sequence item", []string{"paths", "/pets", "get", "parameters", "1"}, Location{Line: 10, Column: 11}},
+		{"past a reference", []string{"paths", "/pets", "get", "parameters", "1", "name"}, Location{Line: 10, Column: 11}},
+		{"missing key", []string{"paths", "/owners"}, Location{Line: 4, Column: 1}},
+		{"index out of range", []string{"paths", "/pets", "get", "parameters", "5"}, Location{Line: 7, Column: 7}},
+		{"missing document part", []string{"webhooks"}, Location{Line: 1, Column: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := source.Locate(tt.path...); got != tt.want {
+				t.Errorf("Locate(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSourceLocateJSON(t *testing.T) {
+	source, err := ParseSource([]byte("{\n\t\"openapi\": \"3.0.3\",\n\t\"paths\": {\n\t\t\"/pets\": {}\n\t}\n}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := source.Locate("paths", "/pets"), (Location{Line: 4, Column: 3}); got != want {
+		t.Errorf("Locate() = %v, want %v", got, want)
+	}
+}
+
+func TestSourceRefs(t *testing.T) {
+	source, err := ParseSource([]byte(sourceYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := source.Refs(), []string{"#/components/parameters/Offset"}; !slices.Equal(got, want) {
+		t.Errorf("Refs() = %q, want %q", got, want)
+	}
+}
+
+func TestJSONPointer(t *testing.T) {
+	if got, want := JSONPointer("paths", "/pets/{id}", "get", "a~b"), "/paths/~1pets~1{id}/get/a~0b"; got != want {
+		t.Errorf("JSONPointer() = %q, want %q", got, want)
+	}
+}
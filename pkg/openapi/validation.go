@@ -0,0 +1,288 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// Violation is a way a request or response breaks the contract of its
+// operation.
+type Violation struct {
+	// In and Name locate a request violation: the parameter's location and
+	// name, or "body" for the request body. Both are empty for responses.
+	In   string
+	Name string
+	// Path points at the offending value in the body, like $.items[0].name.
+	// It's empty when the violation isn't about a single value.
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	msg := v.Message
+	if v.Path != "" {
+		msg = v.Path + ": " + msg
+	}
+
+	switch v.In {
+	case "":
+		return msg
+	case "body":
+		return "request body: " + msg
+	default:
+		return fmt.Sprintf("%s parameter %q: %s", v.In, v.Name, msg)
+	}
+}
+
+// ValidateRequest checks a request for the operation at the path template
+// against the spec: required parameters, their types, formats, enums,
+// patterns and limits, and the body schema. pathParams holds the unescaped
+// path parameter values. Security requirements aren't checked, and neither
+// are bodies of media types that can't be decoded. It reports false when the
+// operation can't be looked up in the document, like webhooks.
+func (s *Spec) ValidateRequest(path string, req *http.Request, pathParams map[string]string) ([]Violation, bool) {
+	route := s.route(path, req.Method)
+	if route == nil {
+		return nil, false
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			MultiError:          true,
+			AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+			ExcludeRequestBody:  !canDecode(req.Header.Get("Content-Type")),
+			SkipSettingDefaults: true,
+		},
+	}
+
+	err := openapi3filter.ValidateRequest(context.Background(), input)
+	if err == nil {
+		return nil, true
+	}
+
+	return violations(err), true
+}
+
+// canDecode reports whether openapi3filter has a decoder for bodies of the
+// content type. Bodies without one are not checked.
+func canDecode(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return openapi3filter.RegisteredBodyDecoder(mediaType) != nil
+}
+
+// ValidateResponse checks a response of the operation at the path template
+// against the spec: the status code must be declared, directly or through a
+// range or default, the Content-Type must be declared for it, and the body
+// must match the schema. It reports false when there is nothing to check
+// against: the operation declares no responses or can't be looked up in the
+// document, like webhooks.
+func (s *Spec) ValidateResponse(path, method string, statusCode int, header http.Header, body []byte) ([]Violation, bool) {
+	route := s.route(path, method)
+	if route == nil || route.Operation.Responses.Len() == 0 {
+		return nil, false
+	}
+
+	response := route.Operation.Responses.Status(statusCode)
+	if response == nil {
+		response = route.Operation.Responses.Default()
+	}
+	if response == nil {
+		return []Violation{{Message: fmt.Sprintf("status %d is not declared for %s %s", statusCode, strings.ToUpper(method), path)}}, true
+	}
+
+	if response.Value != nil && len(response.Value.Content) > 0 {
+		contentType := header.Get("Content-Type")
+		if response.Value.Content.Get(contentType) == nil {
+			if contentType == "" {
+				return []Violation{{Message: fmt.Sprintf("response has no Content-Type, expected %s", declaredContentTypes(response.Value.Content))}}, true
+			}
+			return []Violation{{Message: fmt.Sprintf("Content-Type %q is not declared for status %d, expected %s", contentType, statusCode, declaredContentTypes(response.Value.Content))}}, true
+		}
+	}
+
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request: &http.Request{Method: strings.ToUpper(method), Header: http.Header{}},
+			Route:   route,
+		},
+		Status: statusCode,
+		Header: header,
+		Body:   io.NopCloser(bytes.NewReader(body)),
+		Options: &openapi3filter.Options{
+			MultiError:            true,
+			IncludeResponseStatus: true,
+			ExcludeResponseBody:   !canDecode(header.Get("Content-Type")),
+		},
+	}
+
+	err := openapi3filter.ValidateResponse(context.Background(), input)
+	if err == nil {
+		return nil, true
+	}
+
+	return violations(err), true
+}
+
+// route finds the operation in the source document, which openapi3filter
+// validates against.
+func (s *Spec) route(path, method string) *routers.Route {
+	if s.raw == nil || s.raw.Paths == nil {
+		return nil
+	}
+
+	pathItem := s.raw.Paths.Value(path)
+	if pathItem == nil {
+		return nil
+	}
+
+	op := pathItem.GetOperation(strings.ToUpper(method))
+	if op == nil {
+		return nil
+	}
+
+	return &routers.Route{
+		Spec:      s.raw,
+		Path:      path,
+		PathItem:  pathItem,
+		Method:    strings.ToUpper(method),
+		Operation: op,
+	}
+}
+
+func declaredContentTypes(content openapi3.Content) string {
+	types := make([]string, 0, len(content))
+	for contentType := range content {
+		types = append(types, contentType)
+	}
+	sort.Strings(types)
+
+	return strings.Join(types, ", ")
+}
+
+// ValidateValue lists the ways value breaks schema, like an example that
+// doesn't match the schema it illustrates.
+func ValidateValue(schema *openapi3.Schema, value interface{}) []Violation {
+	if err := schema.VisitJSON(value, openapi3.MultiErrors()); err != nil {
+		return violations(err)
+	}
+
+	return nil
+}
+
+// violations flattens the errors openapi3filter reports, which nest schema
+// errors inside multi errors inside request or response errors.
+func violations(err error) []Violation {
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var result []Violation
+		for _, inner := range e {
+			result = append(result, violations(inner)...)
+		}
+		return result
+	case *openapi3.SchemaError:
+		msg := e.Reason
+		if e.SchemaField == "format" && e.Schema != nil {
+			// The reason spells out the whole regular expression.
+			msg = fmt.Sprintf("value doesn't match the format %q", e.Schema.Format)
+		}
+		return []Violation{{Path: jsonPath(e.JSONPointer()), Message: msg}}
+	case *openapi3filter.ResponseError:
+		if e.Err == nil {
+			return []Violation{{Message: e.Reason}}
+		}
+		return withReason(e.Reason, violations(e.Err))
+	case *openapi3filter.RequestError:
+		vs := []Violation{{Message: e.Reason}}
+		if e.Err != nil {
+			vs = withReason(e.Reason, violations(e.Err))
+		}
+
+		for i := range vs {
+			switch {
+			case e.Parameter != nil:
+				vs[i].In, vs[i].Name = e.Parameter.In, e.Parameter.Name
+				// A parameter is a single value, only its items need a path.
+				if vs[i].Path == "$" {
+					vs[i].Path = ""
+				}
+			case e.RequestBody != nil:
+				vs[i].In = "body"
+			}
+		}
+
+		return vs
+	default:
+		return []Violation{{Message: err.Error()}}
+	}
+}
+
+// withReason adds the context to violations that don't point at a value,
+// like a body that isn't valid JSON at all.
+func withReason(reason string, vs []Violation) []Violation {
+	for i := range vs {
+		if vs[i].Path == "" && reason != "" && vs[i].Message != reason {
+			vs[i].Message = reason + ": " + vs[i].Message
+		}
+	}
+
+	return vs
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// jsonPath turns a JSON pointer into a JSONPath like $.items[0]["odd key"].
+func jsonPath(pointer []string) string {
+	var b strings.Builder
+
+	b.WriteString("$")
+
+	for _, segment := range pointer {
+		switch {
+		case isIndex(segment):
+			b.WriteString("[" + segment + "]")
+		case identifier.MatchString(segment):
+			b.WriteString("." + segment)
+		default:
+			b.WriteString("[" + strconv.Quote(segment) + "]")
+		}
+	}
+
+	return b.String()
+}
+
+func isIndex(segment string) bool {
+	if segment == "" {
+		return false
+	}
+
+	for _, r := range segment {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
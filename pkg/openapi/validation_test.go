@@ -0,0 +1,307 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+const validationTestSpec = `
+openapi: 3.0.3
+info:
+  title: Pets
+  version: "1.0"
+paths:
+  /pets/{petId}:
+    get:
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: A pet
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id, name]
+                properties:
+                  id:
+                    type: integer
+                  name:
+                    type: string
+                  tags:
+                    type: array
+                    items:
+                      type: string
+        4XX:
+          description: Client error
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  message:
+                    type: string
+  /health:
+    get:
+      responses:
+        default:
+          description: Anything
+  /pets:
+    post:
+      security:
+        - apiKey: []
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            minimum: 1
+            maximum: 100
+        - name: status
+          in: query
+          schema:
+            type: string
+            enum: [available, sold]
+        - name: X-Request-Id
+          in: header
+          required: true
+          schema:
+            type: string
+            pattern: "^[a-f0-9]{8}$"
+        - name: since
+          in: query
+          schema:
+            type: string
+            format: date-time
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                  maxLength: 5
+      responses:
+        "201":
+          description: Created
+components:
+  securitySchemes:
+    apiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+`
+
+func TestValidateResponse(t *testing.T) {
+	spec, err := LoadFromData([]byte(validationTestSpec))
+	if err != nil {
+		t.Fatalf("LoadFromData() error = %v", err)
+	}
+
+	jsonHeader := http.Header{"Content-Type": {"application/json; charset=utf-8"}}
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		header http.Header
+		body   string
+		want   []Violation
+		skip   bool
+	}{
+		{name: "valid", path: "/pets/{petId}", status: 200, header: jsonHeader, body: `{"id":1,"name":"rex"}`},
+		{name: "status range", path: "/pets/{petId}", status: 404, header: jsonHeader, body: `{"message":"not found"}`},
+		{name: "default covers any status", path: "/health", status: 503, body: "down"},
+		{name: "unknown operation is skipped", path: "/stores", status: 500, body: "oops", skip: true},
+		{
+			name:   "undeclared status",
+			path:   "/pets/{petId}",
+			status: 500,
+			header: jsonHeader,
+			want:   []Violation{{Message: "status 500 is not declared for GET /pets/{petId}"}},
+		},
+		{
+			name:   "undeclared content type",
+			path:   "/pets/{petId}",
+			status: 200,
+			header: http.Header{"Content-Type": {"text/html"}},
+			body:   "<html>",
+			want:   []Violation{{Message: `Content-Type "text/html" is not declared for status 200, expected application/json`}},
+		},
+		{
+			name:   "schema violations with paths",
+			path:   "/pets/{petId}",
+			status: 200,
+			header: jsonHeader,
+			body:   `{"id":"1","tags":["a",2]}`,
+			want: []Violation{
+				{Path: "$.id", Message: "value must be an integer"},
+				{Path: "$.tags[1]", Message: "value must be a string"},
+				{Path: "$.name", Message: `property "name" is missing`},
+			},
+		},
+		{
+			name:   "body that isn't JSON",
+			path:   "/pets/{petId}",
+			status: 200,
+			header: jsonHeader,
+			body:   `{"id":`,
+			want:   []Violation{{Message: "failed to decode response body: unexpected EOF"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, checked := spec.ValidateResponse(tt.path, "GET", tt.status, tt.header, []byte(tt.body))
+			if checked == tt.skip {
+				t.Errorf("ValidateResponse() checked = %v, want %v", checked, !tt.skip)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateResponse() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	schema := openapi3.NewObjectSchema().
+		WithProperty("id", openapi3.NewIntegerSchema()).
+		WithProperty("name", openapi3.NewStringSchema())
+	schema.Required = []string{"name"}
+
+	if got := ValidateValue(schema, map[string]interface{}{"name": "rex"}); len(got) != 0 {
+		t.Errorf("ValidateValue() of a valid value = %v", got)
+	}
+
+	got := ValidateValue(schema, map[string]interface{}{"id": "1"})
+
+	var msgs []string
+	for _, v := range got {
+		msgs = append(msgs, v.String())
+	}
+
+	want := []string{"$.id: value must be an integer", `$.name: property "name" is missing`}
+	if !slices.Equal(msgs, want) {
+		t.Errorf("ValidateValue() = %q, want %q", msgs, want)
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		pointer []string
+		want    string
+	}{
+		{pointer: nil, want: "$"},
+		{pointer: []string{"items", "0", "name"}, want: "$.items[0].name"},
+		{pointer: []string{"odd key", "x-y"}, want: `$["odd key"]["x-y"]`},
+	}
+
+	for _, tt := range tests {
+		if got := jsonPath(tt.pointer); got != tt.want {
+			t.Errorf("jsonPath(%v) = %q, want %q", tt.pointer, got, tt.want)
+		}
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	spec, err := LoadFromData([]byte(validationTestSpec))
+	if err != nil {
+		t.Fatalf("LoadFromData() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		url        string
+		header     http.Header
+		body       string
+		pathParams map[string]string
+		want       []Violation
+		skip       bool
+	}{
+		{
+			name:   "valid",
+			method: "POST",
+			path:   "/pets",
+			url:    "/pets?limit=10&status=sold&since=2024-01-02T03:04:05Z",
+			header: http.Header{"X-Request-Id": {"deadbeef"}, "Content-Type": {"application/json"}},
+			body:   `{"name":"rex"}`,
+		},
+		{
+			name:   "every kind of violation",
+			method: "POST",
+			path:   "/pets",
+			url:    "/pets?limit=0&status=lost&since=yesterday",
+			header: http.Header{"X-Request-Id": {"nope"}, "Content-Type": {"application/json"}},
+			body:   `{"name":"fluffy","id":1}`,
+			want: []Violation{
+				{In: "query", Name: "limit", Message: "number must be at least 1"},
+				{In: "query", Name: "status", Message: `value is not one of the allowed values ["available","sold"]`},
+				{In: "header", Name: "X-Request-Id", Message: `string doesn't match the regular expression "^[a-f0-9]{8}$"`},
+				{In: "query", Name: "since", Message: `value doesn't match the format "date-time"`},
+				{In: "body", Path: "$.name", Message: "maximum string length is 5"},
+			},
+		},
+		{
+			name:   "missing required values",
+			method: "POST",
+			path:   "/pets",
+			url:    "/pets?limit=abc",
+			want: []Violation{
+				{In: "query", Name: "limit", Message: `value abc: an invalid integer: invalid syntax`},
+				{In: "header", Name: "X-Request-Id", Message: "value is required but missing"},
+				{In: "body", Message: "value is required but missing"},
+			},
+		},
+		{
+			name:       "path parameter type",
+			method:     "GET",
+			path:       "/pets/{petId}",
+			url:        "/pets/abc",
+			pathParams: map[string]string{"petId": "abc"},
+			want:       []Violation{{In: "path", Name: "petId", Message: `value abc: an invalid integer: invalid syntax`}},
+		},
+		{
+			name:   "body that can't be decoded isn't checked",
+			method: "POST",
+			path:   "/pets",
+			url:    "/pets",
+			header: http.Header{"X-Request-Id": {"deadbeef"}, "Content-Type": {"application/x-custom"}},
+			body:   "whatever",
+		},
+		{name: "unknown operation is skipped", method: "GET", path: "/stores", url: "/stores", skip: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, "https://api.example.com"+tt.url, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.header != nil {
+				req.Header = tt.header
+			}
+
+			got, checked := spec.ValidateRequest(tt.path, req, tt.pathParams)
+			if checked == tt.skip {
+				t.Errorf("ValidateRequest() checked = %v, want %v", checked, !tt.skip)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateRequest() =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ksysoev/tapi/pkg/oauth"
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+// Credential attaches authentication data to an outgoing request. Apply may
+// block, e.g. to fetch or refresh a token, and fails the request on error.
+type Credential interface {
+	Apply(req *http.Request) error
+}
+
+type APIKey struct {
+	Name  string
+	In    string
+	Value string
+}
+
+func (k APIKey) Apply(req *http.Request) error {
+	switch k.In {
+	case "query":
+		req.URL.RawQuery = setQueryParam(req.URL.RawQuery, k.Name, k.Value)
+	case "cookie":
+		req.AddCookie(&http.Cookie{Name: k.Name, Value: k.Value})
+	default:
+		req.Header.Set(k.Name, k.Value)
+	}
+	return nil
+}
+
+// setQueryParam sets name in a raw query, replacing an existing value in
+// place. The rest of the query is kept as written, re-encoding it would undo
+// the serialization of parameter styles like spaceDelimited.
+func setQueryParam(rawQuery, name, value string) string {
+	param := url.QueryEscape(name) + "=" + url.QueryEscape(value)
+
+	var pairs []string
+	if rawQuery != "" {
+		pairs = strings.Split(rawQuery, "&")
+	}
+
+	kept := make([]string, 0, len(pairs)+1)
+	replaced := false
+	for _, pair := range pairs {
+		key, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil && unescaped == name {
+			if !replaced {
+				kept = append(kept, param)
+				replaced = true
+			}
+			continue
+		}
+		kept = append(kept, pair)
+	}
+
+	if !replaced {
+		kept = append(kept, param)
+	}
+
+	return strings.Join(kept, "&")
+}
+
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+func (b BasicAuth) Apply(req *http.Request) error {
+	req.SetBasicAuth(b.Username, b.Password)
+	return nil
+}
+
+// HTTPAuth sends "Authorization: <Scheme> <Token>", which covers bearer
+// tokens as well as less common HTTP schemes.
+type HTTPAuth struct {
+	Scheme string
+	Token  string
+}
+
+func (h HTTPAuth) Apply(req *http.Request) error {
+	req.Header.Set("Authorization", h.Scheme+" "+h.Token)
+	return nil
+}
+
+// Placeholders replaces credentials with ones that send a placeholder like
+// "<token>" in place of the secret, for requests that are shown or copied
+// rather than sent. Tokens aren't fetched for them either.
+func Placeholders(creds []Credential) []Credential {
+	placeholders := make([]Credential, 0, len(creds))
+
+	for _, cred := range creds {
+		switch c := cred.(type) {
+		case APIKey:
+			placeholders = append(placeholders, APIKey{Name: c.Name, In: c.In, Value: "<api-key>"})
+		case BasicAuth:
+			// The encoded value would hide the placeholder.
+			placeholders = append(placeholders, HTTPAuth{Scheme: "Basic", Token: "<base64 username:password>"})
+		case HTTPAuth:
+			placeholders = append(placeholders, HTTPAuth{Scheme: c.Scheme, Token: "<token>"})
+		default:
+			placeholders = append(placeholders, HTTPAuth{Scheme: "Bearer", Token: "<token>"})
+		}
+	}
+
+	return placeholders
+}
+
+// CredentialFields lists the values a user has to provide for the scheme.
+func CredentialFields(scheme openapi.SecurityScheme) []string {
+	switch {
+	case scheme.Type == "apiKey":
+		return []string{"value"}
+	case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+		return []string{"username", "password"}
+	case scheme.Type == "oauth2" && scheme.Flows != nil:
+		// A token can be pasted directly, or acquired with client credentials.
+		fields := []string{"token"}
+		if scheme.Flows.ClientCredentials != nil || scheme.Flows.AuthorizationCode != nil || scheme.Flows.Password != nil {
+			fields = append(fields, "client_id", "client_secret")
+		}
+		if scheme.Flows.Password != nil {
+			fields = append(fields, "username", "password")
+		}
+		return fields
+	default:
+		return []string{"token"}
+	}
+}
+
+// NewCredential builds a credential for the scheme from the values named by
+// CredentialFields. It returns nil when the required values are empty, and an
+// error when they can't be used with the scheme.
+func NewCredential(scheme openapi.SecurityScheme, values map[string]string) (Credential, error) {
+	switch {
+	case scheme.Type == "apiKey":
+		if values["value"] == "" {
+			return nil, nil
+		}
+		return APIKey{Name: scheme.ParamName, In: scheme.In, Value: values["value"]}, nil
+	case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+		if values["username"] == "" && values["password"] == "" {
+			return nil, nil
+		}
+		return BasicAuth{Username: values["username"], Password: values["password"]}, nil
+	case scheme.Type == "http" && scheme.Scheme != "" && !strings.EqualFold(scheme.Scheme, "bearer"):
+		if values["token"] == "" {
+			return nil, nil
+		}
+		return HTTPAuth{Scheme: scheme.Scheme, Token: values["token"]}, nil
+	case scheme.Type == "oauth2" && values["token"] == "" && values["client_id"] != "":
+		manager, err := oauth.NewManager(scheme, oauth.Config{
+			ClientID:     values["client_id"],
+			ClientSecret: values["client_secret"],
+			Username:     values["username"],
+			Password:     values["password"],
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up OAuth2: %w", err)
+		}
+		return manager, nil
+	default:
+		// bearer, oauth2 and openIdConnect all end up as a bearer token
+		if values["token"] == "" {
+			return nil, nil
+		}
+		return HTTPAuth{Scheme: "Bearer", Token: values["token"]}, nil
+	}
+}
+
+// ResolveCredentials picks the first security requirement that can be fully
+// satisfied with the available credentials and returns them. Requirements are
+// alternatives, so satisfying any one of them is enough. An empty requirement
+// makes auth optional and is only used when no other one is satisfied.
+func ResolveCredentials(requirements []openapi.SecurityRequirement, available map[string]Credential) []Credential {
+	for _, req := range requirements {
+		if len(req) == 0 {
+			continue
+		}
+
+		creds := make([]Credential, 0, len(req))
+		satisfied := true
+
+		for name := range req {
+			cred, ok := available[name]
+			if !ok || cred == nil {
+				satisfied = false
+				break
+			}
+			creds = append(creds, cred)
+		}
+
+		if satisfied {
+			return creds
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,299 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/oauth"
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+func TestCredentialApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		cred  Credential
+		check func(t *testing.T, r *http.Request)
+	}{
+		{
+			name: "api key in header",
+			cred: APIKey{Name: "X-API-Key", In: "header", Value: "secret"},
+			check: func(t *testing.T, r *http.Request) {
+				if got := r.Header.Get("X-API-Key"); got != "secret" {
+					t.Errorf("X-API-Key = %q, want secret", got)
+				}
+			},
+		},
+		{
+			name: "api key in query",
+			cred: APIKey{Name: "api_key", In: "query", Value: "secret"},
+			check: func(t *testing.T, r *http.Request) {
+				if got := r.URL.Query().Get("api_key"); got != "secret" {
+					t.Errorf("api_key query = %q, want secret", got)
+				}
+				if got := r.URL.Query().Get("page"); got != "2" {
+					t.Errorf("existing query parameter lost, page = %q", got)
+				}
+			},
+		},
+		{
+			name: "api key in cookie",
+			cred: APIKey{Name: "session", In: "cookie", Value: "abc"},
+			check: func(t *testing.T, r *http.Request) {
+				c, err := r.Cookie("session")
+				if err != nil || c.Value != "abc" {
+					t.Errorf("session cookie = %v, %v", c, err)
+				}
+			},
+		},
+		{
+			name: "basic auth",
+			cred: BasicAuth{Username: "user", Password: "pass"},
+			check: func(t *testing.T, r *http.Request) {
+				u, p, ok := r.BasicAuth()
+				if !ok || u != "user" || p != "pass" {
+					t.Errorf("BasicAuth() = %q, %q, %v", u, p, ok)
+				}
+			},
+		},
+		{
+			name: "bearer token",
+			cred: HTTPAuth{Scheme: "Bearer", Token: "tok"},
+			check: func(t *testing.T, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+					t.Errorf("Authorization = %q, want 'Bearer tok'", got)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "https://api.example.com/users?page=2", nil)
+			if err := tt.cred.Apply(req); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			tt.check(t, req)
+		})
+	}
+}
+
+func TestAPIKeyQueryKeepsSerialization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if want := "ids=3%204%205&sort=name&api_key=s%C3%A9cret%26x"; r.URL.RawQuery != want {
+			t.Errorf("query = %q, want %q", r.URL.RawQuery, want)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	r := Request{BaseURL: server.URL, Path: "/pets", Method: "GET"}
+	r.SetParam(openapi.Parameter{Name: "ids", In: "query", Style: "spaceDelimited", Explode: boolPtr(false), Schema: arraySchema}, "3,4,5")
+	r.SetParam(openapi.Parameter{Name: "sort", In: "query"}, "name")
+	r.Credentials = []Credential{APIKey{Name: "api_key", In: "query", Value: "sécret&x"}}
+
+	if resp, ok := Send(r)().(ResponseMsg); !ok || resp.StatusCode != http.StatusOK {
+		t.Errorf("Send() = %+v", resp)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.example.com/pets?api_key=old&b=a%20b&api_key=older", nil)
+	if err := (APIKey{Name: "api_key", In: "query", Value: "new"}).Apply(req); err != nil {
+		t.Fatal(err)
+	}
+	if want := "api_key=new&b=a%20b"; req.URL.RawQuery != want {
+		t.Errorf("query = %q, want the key replaced in place, %q", req.URL.RawQuery, want)
+	}
+}
+
+func TestNewCredential(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme openapi.SecurityScheme
+		values map[string]string
+		want   Credential
+	}{
+		{
+			name:   "api key",
+			scheme: openapi.SecurityScheme{Type: "apiKey", In: "header", ParamName: "X-Key"},
+			values: map[string]string{"value": "v"},
+			want:   APIKey{Name: "X-Key", In: "header", Value: "v"},
+		},
+		{
+			name:   "empty api key",
+			scheme: openapi.SecurityScheme{Type: "apiKey", In: "header", ParamName: "X-Key"},
+			values: map[string]string{"value": ""},
+			want:   nil,
+		},
+		{
+			name:   "basic",
+			scheme: openapi.SecurityScheme{Type: "http", Scheme: "basic"},
+			values: map[string]string{"username": "u", "password": "p"},
+			want:   BasicAuth{Username: "u", Password: "p"},
+		},
+		{
+			name:   "bearer",
+			scheme: openapi.SecurityScheme{Type: "http", Scheme: "bearer"},
+			values: map[string]string{"token": "t"},
+			want:   HTTPAuth{Scheme: "Bearer", Token: "t"},
+		},
+		{
+			name:   "custom http scheme",
+			scheme: openapi.SecurityScheme{Type: "http", Scheme: "Digest"},
+			values: map[string]string{"token": "t"},
+			want:   HTTPAuth{Scheme: "Digest", Token: "t"},
+		},
+		{
+			name:   "oauth2",
+			scheme: openapi.SecurityScheme{Type: "oauth2"},
+			values: map[string]string{"token": "t"},
+			want:   HTTPAuth{Scheme: "Bearer", Token: "t"},
+		},
+		{
+			name:   "openIdConnect",
+			scheme: openapi.SecurityScheme{Type: "openIdConnect"},
+			values: map[string]string{"token": "t"},
+			want:   HTTPAuth{Scheme: "Bearer", Token: "t"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCredential(tt.scheme, tt.values)
+			if err != nil {
+				t.Fatalf("NewCredential() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("NewCredential() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveCredentials(t *testing.T) {
+	available := map[string]Credential{
+		"apiKey": APIKey{Name: "X-Key", In: "header", Value: "v"},
+		"bearer": HTTPAuth{Scheme: "Bearer", Token: "t"},
+	}
+
+	tests := []struct {
+		name         string
+		requirements []openapi.SecurityRequirement
+		wantCount    int
+	}{
+		{"no requirements", nil, 0},
+		{"single satisfied", []openapi.SecurityRequirement{{"apiKey": nil}}, 1},
+		{"first unsatisfied falls back", []openapi.SecurityRequirement{{"oauth": {"read"}}, {"bearer": nil}}, 1},
+		{"combined requirement", []openapi.SecurityRequirement{{"apiKey": nil, "bearer": nil}}, 2},
+		{"partially satisfied combination", []openapi.SecurityRequirement{{"apiKey": nil, "oauth": nil}}, 0},
+		{"optional auth", []openapi.SecurityRequirement{{}}, 0},
+		{"optional auth listed first", []openapi.SecurityRequirement{{}, {"apiKey": nil}}, 1},
+		{"optional auth when nothing else is satisfied", []openapi.SecurityRequirement{{}, {"oauth": nil}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveCredentials(tt.requirements, available); len(got) != tt.wantCount {
+				t.Errorf("ResolveCredentials() returned %d credentials, want %d", len(got), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestSendAppliesCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q, want 'Bearer tok'", got)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	msg := Send(Request{
+		BaseURL:     server.URL,
+		Path:        "/secure",
+		Method:      "GET",
+		Credentials: []Credential{HTTPAuth{Scheme: "Bearer", Token: "tok"}},
+	})()
+
+	resp, ok := msg.(ResponseMsg)
+	if !ok || resp.Error != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("Send() = %+v", msg)
+	}
+}
+
+func TestOAuth2CredentialFields(t *testing.T) {
+	flow := &openapi.OAuthFlow{TokenURL: "https://auth.example.com/token"}
+	scheme := openapi.SecurityScheme{
+		Type:  "oauth2",
+		Flows: &openapi.OAuthFlows{ClientCredentials: flow, Password: flow},
+	}
+
+	fields := CredentialFields(scheme)
+	want := []string{"token", "client_id", "client_secret", "username", "password"}
+	if len(fields) != len(want) {
+		t.Fatalf("CredentialFields() = %v, want %v", fields, want)
+	}
+
+	cred, err := NewCredential(scheme, map[string]string{"client_id": "app", "client_secret": "s"})
+	if err != nil {
+		t.Fatalf("NewCredential() error = %v", err)
+	}
+	manager, ok := cred.(*oauth.Manager)
+	if !ok {
+		t.Fatalf("NewCredential() = %T, want *oauth.Manager", cred)
+	}
+
+	if manager.Flow() != oauth.FlowClientCredentials {
+		t.Errorf("Flow() = %q, want client_credentials", manager.Flow())
+	}
+
+	if cred, _ := NewCredential(scheme, map[string]string{"token": "t", "client_id": "app"}); cred != (HTTPAuth{Scheme: "Bearer", Token: "t"}) {
+		t.Errorf("NewCredential() with pasted token = %#v, want bearer", cred)
+	}
+}
+
+func TestNewCredentialOAuth2Error(t *testing.T) {
+	scheme := openapi.SecurityScheme{Name: "oauth", Type: "oauth2"}
+
+	cred, err := NewCredential(scheme, map[string]string{"client_id": "app"})
+	if err == nil || !strings.Contains(err.Error(), "has no OAuth2 flows") {
+		t.Errorf("NewCredential() error = %v, want missing flows", err)
+	}
+	if cred != nil {
+		t.Errorf("NewCredential() = %#v, want nil", cred)
+	}
+}
+
+func TestPlaceholders(t *testing.T) {
+	manager, err := oauth.NewManager(openapi.SecurityScheme{
+		Type:  "oauth2",
+		Flows: &openapi.OAuthFlows{ClientCredentials: &openapi.OAuthFlow{TokenURL: "https://auth.example.com/token"}},
+	}, oauth.Config{ClientID: "app", ClientSecret: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := Placeholders([]Credential{
+		APIKey{Name: "api_key", In: "query", Value: "secret"},
+		BasicAuth{Username: "user", Password: "pass"},
+		HTTPAuth{Scheme: "Digest", Token: "tok"},
+		manager,
+	})
+
+	want := []Credential{
+		APIKey{Name: "api_key", In: "query", Value: "<api-key>"},
+		HTTPAuth{Scheme: "Basic", Token: "<base64 username:password>"},
+		HTTPAuth{Scheme: "Digest", Token: "<token>"},
+		HTTPAuth{Scheme: "Bearer", Token: "<token>"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Placeholders() = %#v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("placeholder %d = %#v, want %#v", i, got[i], want[i])
+		}
+	}
+}
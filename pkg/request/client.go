@@ -2,10 +2,12 @@ package request
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,75 +20,156 @@ type ResponseMsg struct {
 	Headers    http.Header
 	Body       string
 	Error      error
+	// Request is the request that produced the response and Duration the
+	// time it took, including reading the body.
+	Request  Request
+	Duration time.Duration
 }
 
-func Send(baseURL, path, method string, params map[string]string, body string) tea.Cmd {
+// Request describes a single API call. Parameters are grouped by the
+// location the OpenAPI spec declares for them; path parameter values are
+// expected to be serialized and escaped already (see SetParam).
+type Request struct {
+	BaseURL    string
+	Path       string
+	Method     string
+	PathParams map[string]string
+	Query      url.Values
+	Headers    map[string]string
+	Cookies    map[string]string
+	Body       string
+	// ContentType is sent with a non-empty body, defaults to application/json.
+	ContentType string
+	Credentials []Credential
+}
+
+func Send(r Request) tea.Cmd {
 	return func() tea.Msg {
-		fullURL := buildURL(baseURL, path, params)
+		return Do(context.Background(), r)
+	}
+}
 
-		var reqBody io.Reader
-		if body != "" {
-			reqBody = bytes.NewBufferString(body)
-		}
+// Do sends the request and waits for the response. Failures are reported in
+// ResponseMsg.Error rather than returned, so the result can be shown as is.
+func Do(ctx context.Context, r Request) ResponseMsg {
+	start := time.Now()
 
-		req, err := http.NewRequest(method, fullURL, reqBody)
-		if err != nil {
-			return ResponseMsg{Error: fmt.Errorf("failed to create request: %w", err)}
-		}
+	resp := do(ctx, r)
+	resp.Request = r
+	resp.Duration = time.Since(start)
 
-		if body != "" {
-			req.Header.Set("Content-Type", "application/json")
-		}
-		req.Header.Set("Accept", "application/json")
+	return resp
+}
 
-		client := &http.Client{
-			Timeout: 30 * time.Second,
+// URL returns the full request URL with path parameters and query string.
+func (r Request) URL() string {
+	return buildURL(r)
+}
+
+// HTTPRequest builds the request Do sends: the full URL, headers, cookies,
+// body and applied credentials.
+func (r Request) HTTPRequest(ctx context.Context) (*http.Request, error) {
+	fullURL := buildURL(r)
+
+	var reqBody io.Reader
+	if r.Body != "" {
+		reqBody = bytes.NewBufferString(r.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.Method, fullURL, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if r.Body != "" {
+		contentType := r.ContentType
+		if contentType == "" {
+			contentType = "application/json"
 		}
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
 
-		resp, err := client.Do(req)
-		if err != nil {
-			return ResponseMsg{Error: fmt.Errorf("request failed: %w", err)}
+	for _, name := range sortedKeys(r.Headers) {
+		if value := r.Headers[name]; value != "" {
+			req.Header.Set(name, value)
 		}
-		defer func() { _ = resp.Body.Close() }()
+	}
 
-		respBody, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return ResponseMsg{Error: fmt.Errorf("failed to read response: %w", err)}
+	for _, name := range sortedKeys(r.Cookies) {
+		if value := r.Cookies[name]; value != "" {
+			req.AddCookie(&http.Cookie{Name: name, Value: value})
 		}
+	}
 
-		return ResponseMsg{
-			StatusCode: resp.StatusCode,
-			Status:     resp.Status,
-			Headers:    resp.Header,
-			Body:       string(respBody),
+	for _, cred := range r.Credentials {
+		if err := cred.Apply(req); err != nil {
+			return nil, fmt.Errorf("failed to authorize request: %w", err)
 		}
 	}
+
+	return req, nil
 }
 
-func buildURL(baseURL, path string, params map[string]string) string {
-	fullPath := strings.TrimSuffix(baseURL, "/") + path
+func do(ctx context.Context, r Request) ResponseMsg {
+	req, err := r.HTTPRequest(ctx)
+	if err != nil {
+		return ResponseMsg{Error: err}
+	}
 
-	// Handle path parameters
-	pathParams := make(map[string]bool)
-	for key, value := range params {
-		placeholder := fmt.Sprintf("{%s}", key)
-		if strings.Contains(fullPath, placeholder) {
-			fullPath = strings.ReplaceAll(fullPath, placeholder, value)
-			pathParams[key] = true
-		}
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ResponseMsg{Error: fmt.Errorf("request failed: %w", err)}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ResponseMsg{Error: fmt.Errorf("failed to read response: %w", err)}
+	}
+
+	return ResponseMsg{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Headers:    resp.Header,
+		Body:       string(respBody),
+	}
+}
+
+func buildURL(r Request) string {
+	fullPath := strings.TrimSuffix(r.BaseURL, "/") + r.Path
+
+	for key, value := range r.PathParams {
+		fullPath = strings.ReplaceAll(fullPath, fmt.Sprintf("{%s}", key), value)
 	}
 
-	// Handle query parameters
 	queryParams := url.Values{}
-	for key, value := range params {
-		if !pathParams[key] && value != "" {
-			queryParams.Add(key, value)
+	for key, values := range r.Query {
+		for _, value := range values {
+			if value != "" {
+				queryParams.Add(key, value)
+			}
 		}
 	}
 
 	if len(queryParams) > 0 {
-		fullPath += "?" + queryParams.Encode()
+		// spaceDelimited values must keep their spaces as %20, not "+"
+		fullPath += "?" + strings.ReplaceAll(queryParams.Encode(), "+", "%20")
 	}
 
 	return fullPath
 }
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
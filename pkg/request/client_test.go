@@ -1,10 +1,14 @@
 package request
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
 )
 
 func TestBuildURL(t *testing.T) {
@@ -12,63 +16,63 @@ func TestBuildURL(t *testing.T) {
 		name     string
 		baseURL  string
 		path     string
-		params   map[string]string
+		pathArgs map[string]string
+		query    url.Values
 		expected string
 	}{
 		{
 			name:     "simple path",
 			baseURL:  "https://api.example.com",
 			path:     "/users",
-			params:   map[string]string{},
 			expected: "https://api.example.com/users",
 		},
 		{
 			name:     "path parameter",
 			baseURL:  "https://api.example.com",
 			path:     "/users/{id}",
-			params:   map[string]string{"id": "123"},
+			pathArgs: map[string]string{"id": "123"},
 			expected: "https://api.example.com/users/123",
 		},
 		{
 			name:     "multiple path parameters",
 			baseURL:  "https://api.example.com",
 			path:     "/users/{userId}/posts/{postId}",
-			params:   map[string]string{"userId": "123", "postId": "456"},
+			pathArgs: map[string]string{"userId": "123", "postId": "456"},
 			expected: "https://api.example.com/users/123/posts/456",
 		},
 		{
 			name:     "query parameters",
 			baseURL:  "https://api.example.com",
 			path:     "/users",
-			params:   map[string]string{"page": "1", "limit": "10"},
+			query:    url.Values{"page": {"1"}, "limit": {"10"}},
 			expected: "https://api.example.com/users?limit=10&page=1",
 		},
 		{
 			name:     "mixed path and query parameters",
 			baseURL:  "https://api.example.com",
 			path:     "/users/{id}",
-			params:   map[string]string{"id": "123", "include": "posts"},
+			pathArgs: map[string]string{"id": "123"},
+			query:    url.Values{"include": {"posts"}},
 			expected: "https://api.example.com/users/123?include=posts",
 		},
 		{
 			name:     "base URL with trailing slash",
 			baseURL:  "https://api.example.com/",
 			path:     "/users",
-			params:   map[string]string{},
 			expected: "https://api.example.com/users",
 		},
 		{
 			name:     "empty query parameter",
 			baseURL:  "https://api.example.com",
 			path:     "/users",
-			params:   map[string]string{"page": "1", "empty": ""},
+			query:    url.Values{"page": {"1"}, "empty": {""}},
 			expected: "https://api.example.com/users?page=1",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := buildURL(tt.baseURL, tt.path, tt.params)
+			got := buildURL(Request{BaseURL: tt.baseURL, Path: tt.path, PathParams: tt.pathArgs, Query: tt.query})
 			if got != tt.expected {
 				t.Errorf("buildURL() = %v, want %v", got, tt.expected)
 			}
@@ -81,7 +85,8 @@ func TestSend(t *testing.T) {
 		name           string
 		method         string
 		path           string
-		params         map[string]string
+		pathArgs       map[string]string
+		query          url.Values
 		body           string
 		serverResponse func(w http.ResponseWriter, r *http.Request)
 		wantErr        bool
@@ -90,7 +95,6 @@ func TestSend(t *testing.T) {
 			name:   "successful GET request",
 			method: "GET",
 			path:   "/users",
-			params: map[string]string{},
 			body:   "",
 			serverResponse: func(w http.ResponseWriter, r *http.Request) {
 				if r.Method != "GET" {
@@ -105,7 +109,6 @@ func TestSend(t *testing.T) {
 			name:   "successful POST request with body",
 			method: "POST",
 			path:   "/users",
-			params: map[string]string{},
 			body:   `{"name":"John"}`,
 			serverResponse: func(w http.ResponseWriter, r *http.Request) {
 				if r.Method != "POST" {
@@ -121,11 +124,11 @@ func TestSend(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:   "request with path parameter",
-			method: "GET",
-			path:   "/users/{id}",
-			params: map[string]string{"id": "123"},
-			body:   "",
+			name:     "request with path parameter",
+			method:   "GET",
+			path:     "/users/{id}",
+			pathArgs: map[string]string{"id": "123"},
+			body:     "",
 			serverResponse: func(w http.ResponseWriter, r *http.Request) {
 				if r.URL.Path != "/users/123" {
 					t.Errorf("Expected path /users/123, got %s", r.URL.Path)
@@ -139,7 +142,7 @@ func TestSend(t *testing.T) {
 			name:   "request with query parameters",
 			method: "GET",
 			path:   "/users",
-			params: map[string]string{"page": "1", "limit": "10"},
+			query:  url.Values{"page": {"1"}, "limit": {"10"}},
 			body:   "",
 			serverResponse: func(w http.ResponseWriter, r *http.Request) {
 				page := r.URL.Query().Get("page")
@@ -156,7 +159,6 @@ func TestSend(t *testing.T) {
 			name:   "server error response",
 			method: "GET",
 			path:   "/error",
-			params: map[string]string{},
 			body:   "",
 			serverResponse: func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(http.StatusInternalServerError)
@@ -165,11 +167,11 @@ func TestSend(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:   "DELETE request",
-			method: "DELETE",
-			path:   "/users/{id}",
-			params: map[string]string{"id": "123"},
-			body:   "",
+			name:     "DELETE request",
+			method:   "DELETE",
+			path:     "/users/{id}",
+			pathArgs: map[string]string{"id": "123"},
+			body:     "",
 			serverResponse: func(w http.ResponseWriter, r *http.Request) {
 				if r.Method != "DELETE" {
 					t.Errorf("Expected DELETE request, got %s", r.Method)
@@ -185,7 +187,14 @@ func TestSend(t *testing.T) {
 			server := httptest.NewServer(http.HandlerFunc(tt.serverResponse))
 			defer server.Close()
 
-			cmd := Send(server.URL, tt.path, tt.method, tt.params, tt.body)
+			cmd := Send(Request{
+				BaseURL:    server.URL,
+				Path:       tt.path,
+				Method:     tt.method,
+				PathParams: tt.pathArgs,
+				Query:      tt.query,
+				Body:       tt.body,
+			})
 			msg := cmd()
 
 			responseMsg, ok := msg.(ResponseMsg)
@@ -205,7 +214,7 @@ func TestSend(t *testing.T) {
 }
 
 func TestSendInvalidURL(t *testing.T) {
-	cmd := Send("http://invalid-url-that-does-not-exist-12345.com", "/test", "GET", nil, "")
+	cmd := Send(Request{BaseURL: "http://invalid-url-that-does-not-exist-12345.com", Path: "/test", Method: "GET"})
 	msg := cmd()
 
 	responseMsg, ok := msg.(ResponseMsg)
@@ -224,7 +233,7 @@ func TestSendReturnsTeaCmd(t *testing.T) {
 	}))
 	defer server.Close()
 
-	cmd := Send(server.URL, "/test", "GET", nil, "")
+	cmd := Send(Request{BaseURL: server.URL, Path: "/test", Method: "GET"})
 
 	if cmd == nil {
 		t.Fatal("Expected non-nil tea.Cmd")
@@ -243,6 +252,101 @@ func TestSendHeadersSet(t *testing.T) {
 	}))
 	defer server.Close()
 
-	cmd := Send(server.URL, "/test", "GET", nil, "")
+	cmd := Send(Request{BaseURL: server.URL, Path: "/test", Method: "GET"})
 	cmd()
 }
+
+func TestRequestSetParam(t *testing.T) {
+	var r Request
+	r.SetParam(openapi.Parameter{Name: "id", In: "path"}, "1")
+	r.SetParam(openapi.Parameter{Name: "page", In: "query"}, "2")
+	r.SetParam(openapi.Parameter{Name: "X-Request-Id", In: "header"}, "abc")
+	r.SetParam(openapi.Parameter{Name: "session", In: "cookie"}, "s")
+
+	if r.PathParams["id"] != "1" || r.Query.Get("page") != "2" || r.Headers["X-Request-Id"] != "abc" || r.Cookies["session"] != "s" {
+		t.Errorf("SetParam() placed values incorrectly: %+v", r)
+	}
+}
+
+func TestSendHeaderAndCookieParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Request-Id"); got != "abc" {
+			t.Errorf("X-Request-Id header = %q, want abc", got)
+		}
+
+		if c, err := r.Cookie("session"); err != nil || c.Value != "s" {
+			t.Errorf("session cookie = %v, %v", c, err)
+		}
+
+		if _, err := r.Cookie("empty"); err == nil {
+			t.Error("empty cookie should not be sent")
+		}
+
+		if r.URL.RawQuery != "" {
+			t.Errorf("header/cookie params leaked into query: %q", r.URL.RawQuery)
+		}
+
+		if got := r.Header.Get("Accept"); got != "application/xml" {
+			t.Errorf("Accept header = %q, explicit header should override default", got)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	msg := Send(Request{
+		BaseURL: server.URL,
+		Path:    "/test",
+		Method:  "GET",
+		Headers: map[string]string{"X-Request-Id": "abc", "Accept": "application/xml"},
+		Cookies: map[string]string{"session": "s", "empty": ""},
+	})()
+
+	if resp := msg.(ResponseMsg); resp.Error != nil {
+		t.Errorf("Send() error = %v", resp.Error)
+	}
+}
+
+func TestSendContentType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Content-Type"); got != "application/x-www-form-urlencoded" {
+			t.Errorf("Content-Type = %q, want application/x-www-form-urlencoded", got)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp := Do(context.Background(), Request{
+		BaseURL:     server.URL,
+		Path:        "/test",
+		Method:      "POST",
+		Body:        "name=rex",
+		ContentType: "application/x-www-form-urlencoded",
+	})
+
+	if resp.Error != nil {
+		t.Errorf("Do() error = %v", resp.Error)
+	}
+}
+
+func TestDoRecordsRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req := Request{BaseURL: server.URL, Path: "/pets/{id}", Method: "GET", PathParams: map[string]string{"id": "1"}}
+
+	resp := Do(context.Background(), req)
+	if resp.Error != nil {
+		t.Fatalf("Do() error = %v", resp.Error)
+	}
+
+	if resp.Request.URL() != server.URL+"/pets/1" {
+		t.Errorf("Request.URL() = %q", resp.Request.URL())
+	}
+
+	if resp.Duration <= 0 {
+		t.Errorf("Duration = %v, want it measured", resp.Duration)
+	}
+}
@@ -0,0 +1,322 @@
+package request
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+type valueKind int
+
+const (
+	kindScalar valueKind = iota
+	kindArray
+	kindObject
+)
+
+type field struct {
+	key   string
+	value string
+}
+
+// paramValue is a user-entered parameter value parsed according to the
+// parameter schema.
+type paramValue struct {
+	kind   valueKind
+	scalar string
+	items  []string
+	fields []field
+}
+
+// SetParam serializes value according to the parameter's location, style and
+// explode settings and stores it on the request. Array and object values can
+// be entered as JSON or as comma-separated lists ("a,b" or "k=v,k2=v2").
+// Empty values are only kept for path parameters.
+func (r *Request) SetParam(param openapi.Parameter, value string) {
+	if value == "" && param.In != "path" {
+		return
+	}
+
+	style, explode := serializationStyle(param)
+	v := parseValue(param.Schema, value)
+
+	switch param.In {
+	case "path":
+		if r.PathParams == nil {
+			r.PathParams = make(map[string]string)
+		}
+		r.PathParams[param.Name] = serializePath(param.Name, v, style, explode)
+	case "header":
+		if r.Headers == nil {
+			r.Headers = make(map[string]string)
+		}
+		r.Headers[param.Name] = serializeSimple(v, explode, noEscape)
+	case "cookie":
+		if r.Cookies == nil {
+			r.Cookies = make(map[string]string)
+		}
+		r.Cookies[param.Name] = serializeSimple(v, false, noEscape)
+	default:
+		if r.Query == nil {
+			r.Query = make(url.Values)
+		}
+		addQuery(r.Query, param.Name, v, style, explode)
+	}
+}
+
+// serializationStyle resolves the style/explode defaults the spec defines
+// for each parameter location.
+func serializationStyle(param openapi.Parameter) (string, bool) {
+	style := param.Style
+	if style == "" {
+		switch param.In {
+		case "path", "header":
+			style = "simple"
+		default:
+			style = "form"
+		}
+	}
+
+	explode := style == "form"
+	if param.Explode != nil {
+		explode = *param.Explode
+	}
+
+	return style, explode
+}
+
+func parseValue(schema *openapi.Schema, raw string) paramValue {
+	if schema == nil {
+		return paramValue{kind: kindScalar, scalar: raw}
+	}
+
+	switch schema.Type {
+	case "array":
+		if items, ok := parseJSONArray(raw); ok {
+			return paramValue{kind: kindArray, items: items}
+		}
+
+		items := make([]string, 0)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		return paramValue{kind: kindArray, items: items}
+	case "object":
+		if fields, ok := parseJSONObject(raw); ok {
+			return paramValue{kind: kindObject, fields: fields}
+		}
+
+		fields := make([]field, 0)
+		for _, pair := range strings.Split(raw, ",") {
+			key, value, found := strings.Cut(pair, "=")
+			if !found {
+				return paramValue{kind: kindScalar, scalar: raw}
+			}
+			fields = append(fields, field{key: strings.TrimSpace(key), value: strings.TrimSpace(value)})
+		}
+
+		return paramValue{kind: kindObject, fields: fields}
+	default:
+		return paramValue{kind: kindScalar, scalar: raw}
+	}
+}
+
+func parseJSONArray(raw string) ([]string, bool) {
+	var elems []json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &elems); err != nil {
+		return nil, false
+	}
+
+	items := make([]string, 0, len(elems))
+	for _, elem := range elems {
+		items = append(items, jsonScalar(elem))
+	}
+
+	return items, true
+}
+
+// parseJSONObject decodes a flat JSON object keeping the key order the user
+// typed.
+func parseJSONObject(raw string) ([]field, bool) {
+	dec := json.NewDecoder(strings.NewReader(raw))
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, false
+	}
+
+	fields := make([]field, 0)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+
+		key, ok := tok.(string)
+		if !ok {
+			return nil, false
+		}
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, false
+		}
+
+		fields = append(fields, field{key: key, value: jsonScalar(value)})
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return nil, false
+	}
+
+	return fields, true
+}
+
+// jsonScalar renders a JSON value as parameter text: strings lose their
+// quotes, everything else is kept as compact JSON.
+func jsonScalar(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+
+	return buf.String()
+}
+
+func noEscape(s string) string {
+	return s
+}
+
+// serializeSimple renders the "simple" style, also used for headers and
+// cookies: comma-separated items, object members as k=v when exploded.
+func serializeSimple(v paramValue, explode bool, escape func(string) string) string {
+	switch v.kind {
+	case kindArray:
+		return joinEscaped(v.items, ",", escape)
+	case kindObject:
+		return joinFields(v.fields, explode, ",", escape)
+	default:
+		return escape(v.scalar)
+	}
+}
+
+func serializePath(name string, v paramValue, style string, explode bool) string {
+	escape := url.PathEscape
+
+	switch style {
+	case "label":
+		sep := ","
+		if explode {
+			sep = "."
+		}
+
+		switch v.kind {
+		case kindArray:
+			return "." + joinEscaped(v.items, sep, escape)
+		case kindObject:
+			return "." + joinFields(v.fields, explode, sep, escape)
+		default:
+			return "." + escape(v.scalar)
+		}
+	case "matrix":
+		switch v.kind {
+		case kindArray:
+			if explode {
+				parts := make([]string, 0, len(v.items))
+				for _, item := range v.items {
+					parts = append(parts, ";"+name+"="+escape(item))
+				}
+				return strings.Join(parts, "")
+			}
+			return ";" + name + "=" + joinEscaped(v.items, ",", escape)
+		case kindObject:
+			if explode {
+				parts := make([]string, 0, len(v.fields))
+				for _, f := range v.fields {
+					parts = append(parts, ";"+escape(f.key)+"="+escape(f.value))
+				}
+				return strings.Join(parts, "")
+			}
+			return ";" + name + "=" + joinFields(v.fields, false, ",", escape)
+		default:
+			if v.scalar == "" {
+				return ";" + name
+			}
+			return ";" + name + "=" + escape(v.scalar)
+		}
+	default:
+		return serializeSimple(v, explode, escape)
+	}
+}
+
+func addQuery(query url.Values, name string, v paramValue, style string, explode bool) {
+	sep := ","
+	switch style {
+	case "spaceDelimited":
+		sep = " "
+	case "pipeDelimited":
+		sep = "|"
+	case "deepObject":
+		if v.kind == kindObject {
+			for _, f := range v.fields {
+				query.Add(name+"["+f.key+"]", f.value)
+			}
+			return
+		}
+	}
+
+	switch v.kind {
+	case kindArray:
+		if explode {
+			for _, item := range v.items {
+				query.Add(name, item)
+			}
+			return
+		}
+		query.Add(name, strings.Join(v.items, sep))
+	case kindObject:
+		if explode {
+			for _, f := range v.fields {
+				query.Add(f.key, f.value)
+			}
+			return
+		}
+		query.Add(name, joinFields(v.fields, false, sep, noEscape))
+	default:
+		query.Add(name, v.scalar)
+	}
+}
+
+func joinEscaped(items []string, sep string, escape func(string) string) string {
+	escaped := make([]string, 0, len(items))
+	for _, item := range items {
+		escaped = append(escaped, escape(item))
+	}
+
+	return strings.Join(escaped, sep)
+}
+
+// joinFields renders object members as "k=v" pairs when exploded, or as a
+// flat "k,v" list otherwise.
+func joinFields(fields []field, explode bool, sep string, escape func(string) string) string {
+	parts := make([]string, 0, len(fields)*2)
+	for _, f := range fields {
+		if explode {
+			parts = append(parts, escape(f.key)+"="+escape(f.value))
+		} else {
+			parts = append(parts, escape(f.key), escape(f.value))
+		}
+	}
+
+	return strings.Join(parts, sep)
+}
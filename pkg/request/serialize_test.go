@@ -0,0 +1,124 @@
+package request
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+var (
+	arraySchema  = &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "integer"}}
+	objectSchema = &openapi.Schema{Type: "object"}
+)
+
+func TestSetParamPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		style    string
+		explode  *bool
+		schema   *openapi.Schema
+		value    string
+		expected string
+	}{
+		{name: "simple scalar", value: "5", expected: "5"},
+		{name: "simple escapes reserved characters", value: "a/b c", expected: "a%2Fb%20c"},
+		{name: "simple array", schema: arraySchema, value: "3,4,5", expected: "3,4,5"},
+		{name: "simple array from JSON", schema: arraySchema, value: "[3, 4, 5]", expected: "3,4,5"},
+		{name: "simple object", schema: objectSchema, value: `{"role":"admin","firstName":"Alex"}`, expected: "role,admin,firstName,Alex"},
+		{name: "simple object exploded", explode: boolPtr(true), schema: objectSchema, value: "role=admin,firstName=Alex", expected: "role=admin,firstName=Alex"},
+		{name: "label scalar", style: "label", value: "5", expected: ".5"},
+		{name: "label array", style: "label", schema: arraySchema, value: "3,4,5", expected: ".3,4,5"},
+		{name: "label array exploded", style: "label", explode: boolPtr(true), schema: arraySchema, value: "3,4,5", expected: ".3.4.5"},
+		{name: "label object exploded", style: "label", explode: boolPtr(true), schema: objectSchema, value: "role=admin,firstName=Alex", expected: ".role=admin.firstName=Alex"},
+		{name: "matrix scalar", style: "matrix", value: "5", expected: ";id=5"},
+		{name: "matrix empty", style: "matrix", value: "", expected: ";id"},
+		{name: "matrix array", style: "matrix", schema: arraySchema, value: "3,4,5", expected: ";id=3,4,5"},
+		{name: "matrix array exploded", style: "matrix", explode: boolPtr(true), schema: arraySchema, value: "3,4,5", expected: ";id=3;id=4;id=5"},
+		{name: "matrix object", style: "matrix", schema: objectSchema, value: "role=admin,firstName=Alex", expected: ";id=role,admin,firstName,Alex"},
+		{name: "matrix object exploded", style: "matrix", explode: boolPtr(true), schema: objectSchema, value: "role=admin,firstName=Alex", expected: ";role=admin;firstName=Alex"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r Request
+			r.SetParam(openapi.Parameter{Name: "id", In: "path", Style: tt.style, Explode: tt.explode, Schema: tt.schema}, tt.value)
+
+			if got := r.PathParams["id"]; got != tt.expected {
+				t.Errorf("PathParams[id] = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetParamQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		style    string
+		explode  *bool
+		schema   *openapi.Schema
+		value    string
+		expected string
+	}{
+		{name: "form scalar", value: "a b", expected: "id=a%20b"},
+		{name: "form array exploded by default", schema: arraySchema, value: "3,4,5", expected: "id=3&id=4&id=5"},
+		{name: "form array", explode: boolPtr(false), schema: arraySchema, value: "3,4,5", expected: "id=3%2C4%2C5"},
+		{name: "form object exploded by default", schema: objectSchema, value: "role=admin,firstName=Alex", expected: "firstName=Alex&role=admin"},
+		{name: "form object", explode: boolPtr(false), schema: objectSchema, value: "role=admin,firstName=Alex", expected: "id=role%2Cadmin%2CfirstName%2CAlex"},
+		{name: "spaceDelimited array", style: "spaceDelimited", explode: boolPtr(false), schema: arraySchema, value: "3,4,5", expected: "id=3%204%205"},
+		{name: "pipeDelimited array", style: "pipeDelimited", explode: boolPtr(false), schema: arraySchema, value: "3,4,5", expected: "id=3%7C4%7C5"},
+		{name: "deepObject", style: "deepObject", explode: boolPtr(true), schema: objectSchema, value: `{"role":"admin","age":30}`, expected: "id%5Bage%5D=30&id%5Brole%5D=admin"},
+		{name: "empty value is omitted", value: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Request{BaseURL: "https://api.example.com", Path: "/users"}
+			r.SetParam(openapi.Parameter{Name: "id", In: "query", Style: tt.style, Explode: tt.explode, Schema: tt.schema}, tt.value)
+
+			u, err := url.Parse(buildURL(r))
+			if err != nil {
+				t.Fatalf("buildURL() returned an invalid URL: %v", err)
+			}
+
+			if u.RawQuery != tt.expected {
+				t.Errorf("query = %q, want %q", u.RawQuery, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSetParamHeaderAndCookie(t *testing.T) {
+	var r Request
+	r.SetParam(openapi.Parameter{Name: "X-Ids", In: "header", Schema: arraySchema}, "[1,2]")
+	r.SetParam(openapi.Parameter{Name: "X-Filter", In: "header", Explode: boolPtr(true), Schema: objectSchema}, "a=1,b=2")
+	r.SetParam(openapi.Parameter{Name: "ids", In: "cookie", Schema: arraySchema}, "1,2")
+
+	if got := r.Headers["X-Ids"]; got != "1,2" {
+		t.Errorf("X-Ids = %q, want 1,2", got)
+	}
+
+	if got := r.Headers["X-Filter"]; got != "a=1,b=2" {
+		t.Errorf("X-Filter = %q, want a=1,b=2", got)
+	}
+
+	if got := r.Cookies["ids"]; got != "1,2" {
+		t.Errorf("ids cookie = %q, want 1,2", got)
+	}
+}
+
+func TestBuildURLEscapedPathParam(t *testing.T) {
+	var r Request
+	r.BaseURL = "https://api.example.com"
+	r.Path = "/files/{name}"
+	r.SetParam(openapi.Parameter{Name: "name", In: "path"}, "docs/read me?.txt")
+
+	expected := "https://api.example.com/files/docs%2Fread%20me%3F.txt"
+	if got := buildURL(r); got != expected {
+		t.Errorf("buildURL() = %q, want %q", got, expected)
+	}
+}
@@ -0,0 +1,227 @@
+// Package snippet renders a request as a command or code that sends the
+// same request from somewhere else: curl, HTTPie, Go, Python or JavaScript.
+package snippet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+const (
+	FormatCurl   = "curl"
+	FormatHTTPie = "httpie"
+	FormatGo     = "go"
+	FormatPython = "python"
+	FormatFetch  = "fetch"
+)
+
+// Formats lists the supported formats in the order they're offered.
+var Formats = []string{FormatCurl, FormatHTTPie, FormatGo, FormatPython, FormatFetch}
+
+// header is a single header line, headers with several values are repeated.
+type header struct {
+	name  string
+	value string
+}
+
+// Render builds the request exactly as request.Do would send it, including
+// credentials, and renders it in the given format.
+func Render(format string, r request.Request) (string, error) {
+	req, err := r.HTTPRequest(context.Background())
+	if err != nil {
+		return "", err
+	}
+
+	url := req.URL.String()
+	headers := sortedHeaders(req.Header)
+
+	switch format {
+	case FormatCurl:
+		return renderCurl(req.Method, url, headers, r.Body), nil
+	case FormatHTTPie:
+		return renderHTTPie(req.Method, url, headers, r.Body), nil
+	case FormatGo:
+		return renderGo(req.Method, url, headers, r.Body), nil
+	case FormatPython:
+		return renderPython(req.Method, url, headers, r.Body), nil
+	case FormatFetch:
+		return renderFetch(req.Method, url, headers, r.Body), nil
+	default:
+		return "", fmt.Errorf("unsupported snippet format %q, use %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+func sortedHeaders(h http.Header) []header {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := make([]header, 0, len(names))
+	for _, name := range names {
+		for _, value := range h[name] {
+			headers = append(headers, header{name: name, value: value})
+		}
+	}
+
+	return headers
+}
+
+// joinValues merges repeated headers into one comma separated value, for
+// formats that take headers as a map.
+func joinValues(headers []header) []header {
+	joined := make([]header, 0, len(headers))
+	for _, h := range headers {
+		if n := len(joined); n > 0 && joined[n-1].name == h.name {
+			joined[n-1].value += ", " + h.value
+			continue
+		}
+		joined = append(joined, h)
+	}
+
+	return joined
+}
+
+// shellQuote wraps s in single quotes for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// jsonString quotes s as a double-quoted string literal, which is valid in
+// both Python and JavaScript.
+func jsonString(s string) string {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func renderCurl(method, url string, headers []header, body string) string {
+	lines := []string{"curl"}
+	if method != http.MethodGet || body != "" {
+		lines[0] += " -X " + method
+	}
+	lines[0] += " " + shellQuote(url)
+
+	for _, h := range headers {
+		lines = append(lines, "-H "+shellQuote(h.name+": "+h.value))
+	}
+
+	if body != "" {
+		lines = append(lines, "--data-raw "+shellQuote(body))
+	}
+
+	return strings.Join(lines, " \\\n  ")
+}
+
+func renderHTTPie(method, url string, headers []header, body string) string {
+	lines := []string{"http"}
+	if body != "" {
+		lines[0] += " --raw " + shellQuote(body)
+	}
+	lines[0] += " " + method + " " + shellQuote(url)
+
+	for _, h := range headers {
+		lines = append(lines, shellQuote(h.name+":"+h.value))
+	}
+
+	return strings.Join(lines, " \\\n  ")
+}
+
+func renderGo(method, url string, headers []header, body string) string {
+	var b strings.Builder
+
+	b.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n")
+	if body != "" {
+		b.WriteString("\t\"strings\"\n")
+	}
+	b.WriteString(")\n\nfunc main() {\n")
+
+	bodyArg := "nil"
+	if body != "" {
+		fmt.Fprintf(&b, "\tbody := strings.NewReader(%s)\n", strconv.Quote(body))
+		bodyArg = "body"
+	}
+
+	fmt.Fprintf(&b, "\treq, err := http.NewRequest(%s, %s, %s)\n", strconv.Quote(method), strconv.Quote(url), bodyArg)
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+
+	for _, h := range headers {
+		fmt.Fprintf(&b, "\treq.Header.Add(%s, %s)\n", strconv.Quote(h.name), strconv.Quote(h.value))
+	}
+
+	b.WriteString("\n\tresp, err := http.DefaultClient.Do(req)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tdefer resp.Body.Close()\n\n")
+	b.WriteString("\tdata, err := io.ReadAll(resp.Body)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n\n")
+	b.WriteString("\tfmt.Println(resp.Status)\n")
+	b.WriteString("\tfmt.Println(string(data))\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+func renderPython(method, url string, headers []header, body string) string {
+	var b strings.Builder
+
+	b.WriteString("import requests\n\n")
+	b.WriteString("response = requests.request(\n")
+	fmt.Fprintf(&b, "    %s,\n", jsonString(method))
+	fmt.Fprintf(&b, "    %s,\n", jsonString(url))
+
+	if len(headers) > 0 {
+		b.WriteString("    headers={\n")
+		for _, h := range joinValues(headers) {
+			fmt.Fprintf(&b, "        %s: %s,\n", jsonString(h.name), jsonString(h.value))
+		}
+		b.WriteString("    },\n")
+	}
+
+	if body != "" {
+		fmt.Fprintf(&b, "    data=%s,\n", jsonString(body))
+	}
+
+	b.WriteString(")\n\n")
+	b.WriteString("print(response.status_code)\n")
+	b.WriteString("print(response.text)\n")
+
+	return b.String()
+}
+
+func renderFetch(method, url string, headers []header, body string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "const response = await fetch(%s, {\n", jsonString(url))
+	fmt.Fprintf(&b, "  method: %s,\n", jsonString(method))
+
+	if len(headers) > 0 {
+		b.WriteString("  headers: {\n")
+		for _, h := range joinValues(headers) {
+			fmt.Fprintf(&b, "    %s: %s,\n", jsonString(h.name), jsonString(h.value))
+		}
+		b.WriteString("  },\n")
+	}
+
+	if body != "" {
+		fmt.Fprintf(&b, "  body: %s,\n", jsonString(body))
+	}
+
+	b.WriteString("});\n\n")
+	b.WriteString("console.log(response.status);\n")
+	b.WriteString("console.log(await response.text());\n")
+
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package snippet
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+func testRequest() request.Request {
+	return request.Request{
+		BaseURL:     "https://api.example.com",
+		Path:        "/pets/{id}",
+		Method:      "POST",
+		PathParams:  map[string]string{"id": "1"},
+		Query:       map[string][]string{"tag": {"a b"}},
+		Headers:     map[string]string{"X-Note": "it's"},
+		Body:        `{"name": "rex"}`,
+		Credentials: []request.Credential{request.APIKey{Name: "X-API-Key", In: "header", Value: "secret"}},
+	}
+}
+
+func TestRenderCurl(t *testing.T) {
+	got, err := Render(FormatCurl, testRequest())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	expected := `curl -X POST 'https://api.example.com/pets/1?tag=a%20b' \
+  -H 'Accept: application/json' \
+  -H 'Content-Type: application/json' \
+  -H 'X-Api-Key: secret' \
+  -H 'X-Note: it'\''s' \
+  --data-raw '{"name": "rex"}'`
+
+	if got != expected {
+		t.Errorf("Render() =\n%s\nwant\n%s", got, expected)
+	}
+
+	get, _ := Render(FormatCurl, request.Request{BaseURL: "https://api.example.com", Path: "/pets", Method: "GET"})
+	if get != "curl 'https://api.example.com/pets' \\\n  -H 'Accept: application/json'" {
+		t.Errorf("GET without body should omit -X, got\n%s", get)
+	}
+}
+
+func TestRenderHTTPie(t *testing.T) {
+	got, err := Render(FormatHTTPie, testRequest())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`http --raw '{"name": "rex"}' POST 'https://api.example.com/pets/1?tag=a%20b'`,
+		`'X-Api-Key:secret'`,
+		`'X-Note:it'\''s'`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderGo(t *testing.T) {
+	got, err := Render(FormatGo, testRequest())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	formatted, err := format.Source([]byte(got))
+	if err != nil {
+		t.Fatalf("Go snippet doesn't parse: %v\n%s", err, got)
+	}
+	if string(formatted) != got {
+		t.Errorf("Go snippet isn't gofmt'ed:\n%s", got)
+	}
+
+	for _, want := range []string{
+		`strings.NewReader("{\"name\": \"rex\"}")`,
+		`http.NewRequest("POST", "https://api.example.com/pets/1?tag=a%20b", body)`,
+		`req.Header.Add("X-Api-Key", "secret")`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() missing %q:\n%s", want, got)
+		}
+	}
+
+	noBody, _ := Render(FormatGo, request.Request{BaseURL: "https://api.example.com", Path: "/pets", Method: "GET"})
+	if _, err := format.Source([]byte(noBody)); err != nil || strings.Contains(noBody, "strings") {
+		t.Errorf("Go snippet without body should not import strings:\n%s", noBody)
+	}
+}
+
+func TestRenderPythonAndFetch(t *testing.T) {
+	python, err := Render(FormatPython, testRequest())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`"POST",`,
+		`"https://api.example.com/pets/1?tag=a%20b",`,
+		`"X-Note": "it's",`,
+		`data="{\"name\": \"rex\"}",`,
+	} {
+		if !strings.Contains(python, want) {
+			t.Errorf("python snippet missing %q:\n%s", want, python)
+		}
+	}
+
+	fetch, err := Render(FormatFetch, testRequest())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`await fetch("https://api.example.com/pets/1?tag=a%20b", {`,
+		`method: "POST",`,
+		`"X-Api-Key": "secret",`,
+		`body: "{\"name\": \"rex\"}",`,
+	} {
+		if !strings.Contains(fetch, want) {
+			t.Errorf("fetch snippet missing %q:\n%s", want, fetch)
+		}
+	}
+}
+
+func TestRenderUnsupportedFormat(t *testing.T) {
+	if _, err := Render("wget", testRequest()); err == nil || !strings.Contains(err.Error(), "curl, httpie, go, python, fetch") {
+		t.Errorf("Render() error = %v", err)
+	}
+}
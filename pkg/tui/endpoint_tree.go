@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// groupMode controls how the endpoints list is organized.
+type groupMode int
+
+const (
+	groupFlat groupMode = iota
+	groupTags
+	groupPrefix
+)
+
+// The untagged and webhooks groups are keyed apart from tags and path
+// prefixes so a tag or prefix with the same name keeps its own group.
+const (
+	untaggedGroup = "untagged"
+	webhooksGroup = "webhooks"
+
+	specialGroupKey = "\x00"
+)
+
+func (g groupMode) String() string {
+	switch g {
+	case groupTags:
+		return "tags"
+	case groupPrefix:
+		return "path prefix"
+	default:
+		return "flat"
+	}
+}
+
+func (g groupMode) next() groupMode {
+	return (g + 1) % 3
+}
+
+type endpointGroup struct {
+	key         string
+	name        string
+	description string
+	endpoints   []endpoint
+}
+
+// listRow is a single line of the endpoints list: either a group header or
+// an endpoint. Flat lists only contain endpoint rows.
+type listRow struct {
+	group    *endpointGroup
+	endpoint *endpoint
+	match    *endpointMatch
+	// groupKey is the key of the group an endpoint row is listed under.
+	groupKey string
+}
+
+// buildRows lays out the visible rows for the current grouping, collapsed
+// groups and search filter. Search results are always flat so they stay
+// ordered by relevance.
+func (m *Model) buildRows() {
+	m.rows = make([]listRow, 0, len(m.endpointsList))
+
+	if m.groupMode == groupFlat || m.searchInput.Value() != "" {
+		for i := range m.endpointsList {
+			row := listRow{endpoint: &m.endpointsList[i]}
+			if i < len(m.searchMatches) {
+				row.match = &m.searchMatches[i]
+			}
+			m.rows = append(m.rows, row)
+		}
+		return
+	}
+
+	for _, group := range m.groupEndpoints() {
+		m.rows = append(m.rows, listRow{group: group})
+
+		if m.collapsed[m.collapseKey(group.key)] {
+			continue
+		}
+
+		for i := range group.endpoints {
+			m.rows = append(m.rows, listRow{endpoint: &group.endpoints[i], groupKey: group.key})
+		}
+	}
+}
+
+// groupEndpoints groups all endpoints for the current mode. Tag groups follow
+// the order of the spec's top-level tags, with tags that are only used on
+// operations after them and untagged operations last. Webhooks always get a
+// group of their own.
+func (m Model) groupEndpoints() []*endpointGroup {
+	groups := make([]*endpointGroup, 0)
+	byName := make(map[string]*endpointGroup)
+
+	add := func(key, name, description string) *endpointGroup {
+		if g, ok := byName[key]; ok {
+			return g
+		}
+		g := &endpointGroup{key: key, name: name, description: description}
+		byName[key] = g
+		groups = append(groups, g)
+		return g
+	}
+
+	if m.groupMode == groupTags {
+		for _, tag := range m.spec.Tags {
+			add(tag.Name, tag.Name, tag.Description)
+		}
+
+		undeclared := make([]string, 0)
+		for _, e := range m.allEndpoints {
+			for _, tag := range e.operation.Tags {
+				if _, ok := byName[tag]; !ok && !e.webhook {
+					undeclared = append(undeclared, tag)
+				}
+			}
+		}
+		sort.Strings(undeclared)
+
+		for _, tag := range undeclared {
+			add(tag, tag, "")
+		}
+	}
+
+	var untagged, webhooks []endpoint
+	for _, e := range m.allEndpoints {
+		switch {
+		case e.webhook:
+			webhooks = append(webhooks, e)
+		case m.groupMode == groupPrefix:
+			prefix := pathPrefix(e.path.Path)
+			g := add(prefix, prefix, "")
+			g.endpoints = append(g.endpoints, e)
+		case len(e.operation.Tags) == 0:
+			untagged = append(untagged, e)
+		default:
+			for _, tag := range e.operation.Tags {
+				byName[tag].endpoints = append(byName[tag].endpoints, e)
+			}
+		}
+	}
+
+	if len(untagged) > 0 {
+		add(specialGroupKey+untaggedGroup, untaggedGroup, "Operations without tags").endpoints = untagged
+	}
+
+	if len(webhooks) > 0 {
+		add(specialGroupKey+webhooksGroup, webhooksGroup, "").endpoints = webhooks
+	}
+
+	result := make([]*endpointGroup, 0, len(groups))
+	for _, g := range groups {
+		if len(g.endpoints) > 0 {
+			result = append(result, g)
+		}
+	}
+
+	return result
+}
+
+// pathPrefix returns the first segment of a path, e.g. "/pets" for
+// "/pets/{petId}".
+func pathPrefix(path string) string {
+	trimmed := strings.TrimPrefix(path, "/")
+	if i := strings.Index(trimmed, "/"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+
+	return "/" + trimmed
+}
+
+func (m Model) collapseKey(groupKey string) string {
+	return fmt.Sprintf("%d/%s", m.groupMode, groupKey)
+}
+
+func (m Model) currentRow() *listRow {
+	if m.selectedEndpoint < 0 || m.selectedEndpoint >= len(m.rows) {
+		return nil
+	}
+
+	return &m.rows[m.selectedEndpoint]
+}
+
+// setGroupMode switches grouping while keeping the selected endpoint
+// selected when it's still visible.
+func (m *Model) setGroupMode(mode groupMode) {
+	var selected *endpoint
+	if row := m.currentRow(); row != nil {
+		selected = row.endpoint
+	}
+
+	m.groupMode = mode
+	m.buildRows()
+	m.selectedEndpoint = 0
+
+	if selected == nil {
+		return
+	}
+
+	for i, row := range m.rows {
+		if row.endpoint != nil && row.endpoint.operation == selected.operation {
+			m.selectedEndpoint = i
+			return
+		}
+	}
+}
+
+// setCollapsed collapses or expands the group with groupKey and selects its
+// header.
+func (m *Model) setCollapsed(groupKey string, collapsed bool) {
+	if m.collapsed == nil {
+		m.collapsed = make(map[string]bool)
+	}
+	m.collapsed[m.collapseKey(groupKey)] = collapsed
+	m.buildRows()
+
+	for i, row := range m.rows {
+		if row.group != nil && row.group.key == groupKey {
+			m.selectedEndpoint = i
+			return
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+func createTaggedTestSpec() *openapi.Spec {
+	return &openapi.Spec{
+		Title:   "Tagged API",
+		Version: "1.0.0",
+		Tags: []openapi.Tag{
+			{Name: "store", Description: "Access to orders\nMore details"},
+			{Name: "pets", Description: "Everything about pets"},
+			{Name: "unused"},
+		},
+		Paths: []openapi.Path{
+			{Path: "/health", Operations: []openapi.Operation{{Method: "GET"}}},
+			{Path: "/pets", Operations: []openapi.Operation{
+				{Method: "GET", Tags: []string{"pets"}},
+				{Method: "POST", Tags: []string{"pets", "admin"}},
+			}},
+			{Path: "/pets/{petId}", Operations: []openapi.Operation{{Method: "GET", Tags: []string{"pets"}}}},
+			{Path: "/store/orders", Operations: []openapi.Operation{{Method: "GET", Tags: []string{"store"}}}},
+		},
+		Webhooks: []openapi.Path{
+			{Path: "newPet", Operations: []openapi.Operation{{Method: "POST", Tags: []string{"pets"}}}},
+		},
+	}
+}
+
+// rowLabels describes the visible rows as "[group]" or "METHOD path".
+func rowLabels(m Model) []string {
+	labels := make([]string, 0, len(m.rows))
+	for _, row := range m.rows {
+		if row.group != nil {
+			labels = append(labels, "["+row.group.name+"]")
+		} else {
+			labels = append(labels, row.endpoint.String())
+		}
+	}
+	return labels
+}
+
+func TestGroupByTags(t *testing.T) {
+	m := NewModel(createTaggedTestSpec())
+	m.height = 40
+	m.setGroupMode(groupTags)
+
+	expected := []string{
+		"[store]", "GET /store/orders",
+		"[pets]", "GET /pets", "POST /pets", "GET /pets/{petId}",
+		"[admin]", "POST /pets",
+		"[untagged]", "GET /health",
+		"[webhooks]", "POST newPet",
+	}
+
+	if got := rowLabels(m); strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("rows = %v, want %v", got, expected)
+	}
+
+	rendered := m.renderEndpoints()
+	if !strings.Contains(rendered, "Access to orders") || strings.Contains(rendered, "More details") {
+		t.Error("renderEndpoints() should show the first line of tag descriptions")
+	}
+}
+
+func TestGroupByPathPrefix(t *testing.T) {
+	m := NewModel(createTaggedTestSpec())
+	m.setGroupMode(groupPrefix)
+
+	expected := []string{
+		"[/health]", "GET /health",
+		"[/pets]", "GET /pets", "POST /pets", "GET /pets/{petId}",
+		"[/store]", "GET /store/orders",
+		"[webhooks]", "POST newPet",
+	}
+
+	if got := rowLabels(m); strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("rows = %v, want %v", got, expected)
+	}
+}
+
+func TestGroupNamedLikeSpecialGroups(t *testing.T) {
+	spec := &openapi.Spec{
+		Title: "Hooks API",
+		Paths: []openapi.Path{
+			{Path: "/health", Operations: []openapi.Operation{{Method: "GET"}}},
+			{Path: "/webhooks", Operations: []openapi.Operation{{Method: "GET", Tags: []string{"webhooks"}}}},
+			{Path: "/webhooks/{id}", Operations: []openapi.Operation{{Method: "DELETE", Tags: []string{"untagged"}}}},
+		},
+		Webhooks: []openapi.Path{
+			{Path: "newPet", Operations: []openapi.Operation{{Method: "POST"}}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		mode     groupMode
+		expected []string
+	}{
+		{
+			name: "tags",
+			mode: groupTags,
+			expected: []string{
+				"[untagged]", "DELETE /webhooks/{id}",
+				"[webhooks]", "GET /webhooks",
+				"[untagged]", "GET /health",
+				"[webhooks]", "POST newPet",
+			},
+		},
+		{
+			name: "path prefix",
+			mode: groupPrefix,
+			expected: []string{
+				"[/health]", "GET /health",
+				"[/webhooks]", "GET /webhooks", "DELETE /webhooks/{id}",
+				"[webhooks]", "POST newPet",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewModel(spec)
+			m.height = 40
+			m.setGroupMode(tt.mode)
+
+			if got := rowLabels(m); strings.Join(got, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("rows = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+
+	m := NewModel(spec)
+	m.height = 40
+	m.setGroupMode(groupTags)
+	m.setCollapsed(specialGroupKey+webhooksGroup, true)
+
+	labels := strings.Join(rowLabels(m), "|")
+	if strings.Contains(labels, "POST newPet") || !strings.Contains(labels, "GET /webhooks") {
+		t.Errorf("collapsing the webhooks group should keep the webhooks tag expanded, got %v", labels)
+	}
+}
+
+func TestGroupModeToggleKeepsSelection(t *testing.T) {
+	m := NewModel(createTaggedTestSpec())
+
+	// select GET /pets/{petId} in the flat list
+	for m.getCurrentEndpoint().String() != "GET /pets/{petId}" {
+		m.selectedEndpoint++
+	}
+
+	press := func(key string) {
+		updated, _ := m.handleEndpointsKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(Model)
+	}
+
+	for _, mode := range []groupMode{groupTags, groupPrefix, groupFlat} {
+		press("t")
+
+		if m.groupMode != mode {
+			t.Fatalf("groupMode = %v, want %v", m.groupMode, mode)
+		}
+
+		if e := m.getCurrentEndpoint(); e == nil || e.String() != "GET /pets/{petId}" {
+			t.Errorf("%v: selection lost after switching grouping", mode)
+		}
+	}
+}
+
+func TestGroupCollapseExpand(t *testing.T) {
+	m := NewModel(createTaggedTestSpec())
+	m.height = 40
+	m.setGroupMode(groupTags)
+
+	press := func(msg tea.KeyMsg) {
+		updated, _ := m.handleEndpointsKeys(msg)
+		m = updated.(Model)
+	}
+
+	// move into the pets group and collapse it from an endpoint row
+	m.selectedEndpoint = 3
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+
+	if row := m.currentRow(); row.group == nil || row.group.name != "pets" {
+		t.Fatalf("h on an endpoint should select its group header, got %+v", row)
+	}
+
+	if strings.Contains(strings.Join(rowLabels(m), "|"), "GET /pets/{petId}") {
+		t.Error("collapsed group should hide its endpoints")
+	}
+
+	if !strings.Contains(m.renderEndpoints(), "▸") {
+		t.Error("collapsed group should render a collapsed marker")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	if !strings.Contains(strings.Join(rowLabels(m), "|"), "GET /pets/{petId}") {
+		t.Error("l on a collapsed header should expand it")
+	}
+
+	if m.currentView != viewEndpoints {
+		t.Error("l on a header should not open operation details")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if strings.Contains(strings.Join(rowLabels(m), "|"), "GET /pets/{petId}") {
+		t.Error("enter on a header should toggle it")
+	}
+
+	if m.getCurrentEndpoint() != nil {
+		t.Error("getCurrentEndpoint() should be nil on a group header")
+	}
+}
+
+func TestSearchIgnoresGrouping(t *testing.T) {
+	m := NewModel(createTaggedTestSpec())
+	m.setGroupMode(groupTags)
+	m = typeSearch(m, "orders")
+
+	if got := rowLabels(m); len(got) != 1 || got[0] != "GET /store/orders" {
+		t.Errorf("search rows = %v, want a flat list of matches", got)
+	}
+
+	updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+
+	if m.rows[0].group == nil {
+		t.Error("clearing the search should bring the groups back")
+	}
+}
+
+func TestPathPrefix(t *testing.T) {
+	tests := map[string]string{
+		"/pets/{petId}": "/pets",
+		"/pets":         "/pets",
+		"/":             "/",
+		"newPet":        "/newPet",
+	}
+
+	for path, expected := range tests {
+		if got := pathPrefix(path); got != expected {
+			t.Errorf("pathPrefix(%q) = %q, want %q", path, got, expected)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"github.com/ksysoev/tapi/pkg/environment"
+)
+
+// WithEnvironments makes the configured environments available for
+// switching, starting with active. An empty active starts without one.
+func WithEnvironments(cfg *environment.Config, active string) Option {
+	return func(m *Model) {
+		if cfg == nil {
+			return
+		}
+		m.environments = cfg.Environments
+		m.envNames = cfg.Names()
+		m.activeEnv = active
+	}
+}
+
+func (m Model) envVars() map[string]string {
+	return m.environments[m.activeEnv]
+}
+
+// interpolate fills {{name}} placeholders from the active environment.
+func (m Model) interpolate(s string) string {
+	return environment.Interpolate(s, m.envVars())
+}
+
+// cycleEnvironment switches to the next environment, going through "no
+// environment" after the last one.
+func (m *Model) cycleEnvironment() {
+	if len(m.envNames) == 0 {
+		return
+	}
+
+	next := 0
+	for i, name := range m.envNames {
+		if name == m.activeEnv {
+			next = i + 1
+		}
+	}
+
+	m.activeEnv = ""
+	if next < len(m.envNames) {
+		m.activeEnv = m.envNames[next]
+	}
+
+	// Credentials may take their values from the environment.
+	m.buildCredentials()
+}
@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ksysoev/tapi/pkg/environment"
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+func TestCycleEnvironment(t *testing.T) {
+	cfg := &environment.Config{Environments: map[string]map[string]string{
+		"prod": {environment.BaseURLVar: "https://api.example.com"},
+		"dev":  {environment.BaseURLVar: "http://localhost:8080"},
+	}}
+
+	m := NewModel(createServersTestSpec(), WithEnvironments(cfg, "dev"))
+
+	expected := []struct {
+		env  string
+		base string
+	}{
+		{env: "prod", base: "https://api.example.com"},
+		{env: "", base: "https://api.example.com"},
+		{env: "dev", base: "http://localhost:8080"},
+	}
+
+	for _, e := range expected {
+		m = pressKeys(m, runes("E"))
+		if m.activeEnv != e.env {
+			t.Fatalf("activeEnv = %q, want %q", m.activeEnv, e.env)
+		}
+		if got := m.baseURL(nil); got != e.base {
+			t.Errorf("baseURL() = %q in env %q, want %q", got, e.env, e.base)
+		}
+	}
+
+	if !strings.Contains(m.renderHeader(), "env: dev") {
+		t.Error("renderHeader() should show the active environment")
+	}
+
+	m.customServer = "http://{{host}}"
+	m.environments["dev"]["host"] = "custom.local"
+	if got := m.baseURL(nil); got != "http://custom.local" {
+		t.Errorf("baseURL() = %q, custom server should win and be interpolated", got)
+	}
+}
+
+func TestSendRequestInterpolatesEnvironment(t *testing.T) {
+	var gotPath, gotHeader, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotPath, gotHeader, gotBody = r.URL.Path, r.Header.Get("X-Tenant"), string(body)
+	}))
+	defer server.Close()
+
+	spec := createBodyTestSpec("https://unused.example.com")
+	spec.Paths[0].Path = "/pets/{owner}"
+	spec.Paths[0].Operations[0].Parameters = []openapi.Parameter{
+		{Name: "owner", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}},
+		{Name: "X-Tenant", In: "header", Schema: &openapi.Schema{Type: "string"}},
+	}
+
+	cfg := &environment.Config{Environments: map[string]map[string]string{
+		"dev": {environment.BaseURLVar: server.URL, "owner": "alice", "tenantId": "42"},
+	}}
+
+	m := NewModel(spec, WithEnvironments(cfg, "dev"))
+	m.setupRequestBuilder()
+	m.inputs[0].SetValue("{{owner}}")
+	m.inputs[1].SetValue("{{ tenantId }}")
+	m.bodyEditor.SetValue(`{"tenant": {{tenantId}}}`)
+
+	if err := m.bodyError(); err != nil {
+		t.Fatalf("bodyError() = %v, the interpolated body is valid JSON", err)
+	}
+
+	msg := m.sendRequest()()
+	if resp, ok := msg.(request.ResponseMsg); !ok || resp.Error != nil {
+		t.Fatalf("sendRequest() = %+v", msg)
+	}
+
+	if gotPath != "/pets/alice" || gotHeader != "42" || gotBody != `{"tenant": 42}` {
+		t.Errorf("request = path %q, header %q, body %q", gotPath, gotHeader, gotBody)
+	}
+}
+
+func TestCredentialsInterpolateEnvironment(t *testing.T) {
+	cfg := &environment.Config{Environments: map[string]map[string]string{
+		"dev":  {"apiKey": "dev-key"},
+		"prod": {"apiKey": "prod-key"},
+	}}
+
+	m := NewModel(createSecuredTestSpec(), WithEnvironments(cfg, "dev"))
+	m.openAuthorize()
+	m = typeText(m, "{{apiKey}}")
+	m.saveAuthorization()
+
+	if cred := m.credentials["apiKey"]; cred != (request.APIKey{Name: "X-API-Key", In: "header", Value: "dev-key"}) {
+		t.Errorf("credential = %#v, want the dev key", cred)
+	}
+
+	m = pressKeys(m, runes("E"))
+	if m.activeEnv != "prod" {
+		t.Fatalf("activeEnv = %q, want prod", m.activeEnv)
+	}
+
+	if cred := m.credentials["apiKey"]; cred != (request.APIKey{Name: "X-API-Key", In: "header", Value: "prod-key"}) {
+		t.Errorf("credential = %#v after switching, want the prod key", cred)
+	}
+
+	m.openAuthorize()
+	if got := m.authFields[0].input.Value(); got != "{{apiKey}}" {
+		t.Errorf("apiKey field = %q, want the placeholder kept", got)
+	}
+}
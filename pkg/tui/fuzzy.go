@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// fuzzyMatch reports whether all runes of pattern appear in text in order,
+// ignoring case and whitespace in the pattern. The score rewards matches
+// that are consecutive or start a word, and positions holds the rune
+// indexes of the matched characters in text.
+func fuzzyMatch(pattern, text string) (int, []int, bool) {
+	needle := make([]rune, 0, len(pattern))
+	for _, r := range strings.ToLower(pattern) {
+		if !unicode.IsSpace(r) {
+			needle = append(needle, r)
+		}
+	}
+
+	if len(needle) == 0 {
+		return 0, nil, true
+	}
+
+	haystack := []rune(text)
+	positions := make([]int, 0, len(needle))
+	score := 0
+	prev := -2
+
+	for i := 0; i < len(haystack) && len(positions) < len(needle); i++ {
+		if unicode.ToLower(haystack[i]) != needle[len(positions)] {
+			continue
+		}
+
+		score++
+		if i == prev+1 {
+			score += 5
+		}
+		if i == 0 || isWordBoundary(haystack[i-1], haystack[i]) {
+			score += 3
+		}
+
+		positions = append(positions, i)
+		prev = i
+	}
+
+	if len(positions) < len(needle) {
+		return 0, nil, false
+	}
+
+	// Prefer matches packed closely together over ones spread across the text.
+	score -= (positions[len(positions)-1] - positions[0] + 1 - len(positions)) / 3
+
+	return score, positions, true
+}
+
+func isWordBoundary(prev, cur rune) bool {
+	if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+		return true
+	}
+
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name          string
+		pattern       string
+		text          string
+		wantOK        bool
+		wantPositions []int
+	}{
+		{name: "empty pattern", pattern: "", text: "GET /users", wantOK: true},
+		{name: "subsequence", pattern: "gus", text: "GET /users", wantOK: true, wantPositions: []int{0, 5, 6}},
+		{name: "case-insensitive", pattern: "USERS", text: "GET /users", wantOK: true, wantPositions: []int{5, 6, 7, 8, 9}},
+		{name: "spaces in pattern are ignored", pattern: "get us", text: "GET /users", wantOK: true, wantPositions: []int{0, 1, 2, 5, 6}},
+		{name: "out of order", pattern: "sug", text: "GET /users", wantOK: false},
+		{name: "unicode", pattern: "é", text: "Café", wantOK: true, wantPositions: []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, positions, ok := fuzzyMatch(tt.pattern, tt.text)
+			if ok != tt.wantOK {
+				t.Fatalf("fuzzyMatch() ok = %v, want %v", ok, tt.wantOK)
+			}
+
+			if !reflect.DeepEqual(positions, tt.wantPositions) {
+				t.Errorf("fuzzyMatch() positions = %v, want %v", positions, tt.wantPositions)
+			}
+		})
+	}
+}
+
+func TestFuzzyMatchScoring(t *testing.T) {
+	consecutive, _, _ := fuzzyMatch("pet", "/pets")
+	scattered, _, _ := fuzzyMatch("pet", "/places/events/tags")
+
+	if consecutive <= scattered {
+		t.Errorf("consecutive match score %d should beat scattered %d", consecutive, scattered)
+	}
+
+	boundary, _, _ := fuzzyMatch("pi", "getPetById")
+	inner, _, _ := fuzzyMatch("pi", "shipping")
+
+	if boundary <= inner {
+		t.Errorf("word boundary match score %d should beat inner %d", boundary, inner)
+	}
+}
@@ -2,36 +2,25 @@ package tui
 
 import "github.com/ksysoev/tapi/pkg/openapi"
 
-func (m Model) getCurrentPath() *openapi.Path {
-	if m.selectedEndpoint < 0 || m.selectedEndpoint >= len(m.endpointsList) {
-		return nil
+// getCurrentEndpoint returns the endpoint on the selected row, or nil when a
+// group header is selected.
+func (m Model) getCurrentEndpoint() *endpoint {
+	if row := m.currentRow(); row != nil {
+		return row.endpoint
 	}
+	return nil
+}
 
-	idx := 0
-	for i, path := range m.spec.Paths {
-		for range path.Operations {
-			if idx == m.selectedEndpoint {
-				return &m.spec.Paths[i]
-			}
-			idx++
-		}
+func (m Model) getCurrentPath() *openapi.Path {
+	if e := m.getCurrentEndpoint(); e != nil {
+		return e.path
 	}
 	return nil
 }
 
 func (m Model) getCurrentOperation() *openapi.Operation {
-	if m.selectedEndpoint < 0 || m.selectedEndpoint >= len(m.endpointsList) {
-		return nil
-	}
-
-	idx := 0
-	for _, path := range m.spec.Paths {
-		for j := range path.Operations {
-			if idx == m.selectedEndpoint {
-				return &path.Operations[j]
-			}
-			idx++
-		}
+	if e := m.getCurrentEndpoint(); e != nil {
+		return e.operation
 	}
 	return nil
 }
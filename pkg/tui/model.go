@@ -3,13 +3,15 @@ package tui
 import (
 	"fmt"
 	"sort"
-	"strings"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ksysoev/tapi/internal/styles"
+	"github.com/ksysoev/tapi/pkg/collection"
+	"github.com/ksysoev/tapi/pkg/history"
 	"github.com/ksysoev/tapi/pkg/openapi"
 	"github.com/ksysoev/tapi/pkg/request"
 )
@@ -22,58 +24,155 @@ const (
 	viewRequestBuilder
 	viewResponse
 	viewHelp
+	viewAuthorize
+	viewServers
+	viewHistory
+	viewCollection
+	viewExport
+	viewImportCurl
 )
 
 type Model struct {
 	spec              *openapi.Spec
 	currentView       view
-	endpointsList     []string
+	allEndpoints      []endpoint
+	endpointsList     []endpoint
+	searching         bool
+	searchInput       textinput.Model
+	searchMatches     []endpointMatch
+	groupMode         groupMode
+	rows              []listRow
+	collapsed         map[string]bool
 	selectedEndpoint  int
 	width             int
 	height            int
 	viewport          viewport.Model
 	inputs            []textinput.Model
 	focusedInput      int
+	hasBody           bool
+	bodyEditor        textarea.Model
+	contentTypes      []string
+	contentTypeIndex  int
 	lastResponse      string
+	lastRequest       *request.Request
 	showHelp          bool
+	authFields        []authField
+	focusedAuthField  int
+	authReturnView    view
+	authValues        map[string]map[string]string
+	authErrors        map[string]error
+	credentials       map[string]request.Credential
+	selectedServer    int
+	serverVars        map[string]string
+	customServer      string
+	serverCursor      int
+	serverInputs      []serverInput
+	serverFocus       int
+	serverReturnView  view
+	environments      map[string]map[string]string
+	envNames          []string
+	activeEnv         string
+	historyStore      *history.Store
+	historyEntries    []history.Entry
+	historyCursor     int
+	historyOperation  string
+	historyReturnView view
+	historyErr        error
+	// responseFromHistory is set while the response view shows a past
+	// response, so going back returns to the history.
+	responseFromHistory bool
+	// requestHeaders are extra headers of a loaded saved request.
+	requestHeaders       map[string]string
+	collectionPath       string
+	collection           *collection.Collection
+	collectionCursor     int
+	collectionReturnView view
+	collectionErr        error
+	builderStatus        string
+	// showViolations is set once sending was stopped because the request
+	// doesn't match the spec.
+	showViolations   bool
+	savingRequest    bool
+	saveInput        textinput.Model
+	exportRequest    request.Request
+	exportFormat     int
+	exportViewport   viewport.Model
+	exportReturnView view
+	exportStatus     string
+	importInput      textarea.Model
+	importErr        error
+	// bodies keeps the body of each media type the user switched away from.
+	bodies map[string]string
+	// operationServers is the server picked for operations that declare
+	// their own servers.
+	operationServers map[*openapi.Operation]int
 }
 
-func NewModel(spec *openapi.Spec) Model {
-	endpoints := make([]string, 0)
-	for _, path := range spec.Paths {
-		for _, op := range path.Operations {
-			endpoints = append(endpoints, fmt.Sprintf("%s %s", op.Method, path.Path))
-		}
-	}
+// endpoint is a single operation in the endpoints list, pointing back into
+// the spec it came from.
+type endpoint struct {
+	path      *openapi.Path
+	operation *openapi.Operation
+	webhook   bool
+}
 
-	// Sort endpoints by path first, then by method
-	sort.Slice(endpoints, func(i, j int) bool {
-		partsI := strings.Fields(endpoints[i])
-		partsJ := strings.Fields(endpoints[j])
-		
-		if len(partsI) < 2 || len(partsJ) < 2 {
-			return endpoints[i] < endpoints[j]
-		}
-		
-		pathI := strings.Join(partsI[1:], " ")
-		pathJ := strings.Join(partsJ[1:], " ")
-		
-		if pathI == pathJ {
-			return partsI[0] < partsJ[0]
-		}
-		return pathI < pathJ
-	})
+func (e endpoint) String() string {
+	return fmt.Sprintf("%s %s", e.operation.Method, e.path.Path)
+}
+
+func NewModel(spec *openapi.Spec, opts ...Option) Model {
+	endpoints := collectEndpoints(spec.Paths, false)
+	endpoints = append(endpoints, collectEndpoints(spec.Webhooks, true)...)
 
 	vp := viewport.New(80, 20)
 	vp.Style = styles.PanelStyle
 
-	return Model{
+	search := textinput.New()
+	search.Prompt = "/"
+	search.Placeholder = "method, path, operationId, summary or tag"
+	search.Width = 50
+
+	m := Model{
 		spec:             spec,
 		currentView:      viewEndpoints,
+		allEndpoints:     endpoints,
 		endpointsList:    endpoints,
 		selectedEndpoint: 0,
 		viewport:         vp,
+		searchInput:      search,
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+
+	m.buildRows()
+
+	return m
+}
+
+// collectEndpoints flattens paths into endpoints sorted by path first, then
+// by method.
+func collectEndpoints(paths []openapi.Path, webhook bool) []endpoint {
+	endpoints := make([]endpoint, 0)
+	for i := range paths {
+		for j := range paths[i].Operations {
+			endpoints = append(endpoints, endpoint{
+				path:      &paths[i],
+				operation: &paths[i].Operations[j],
+				webhook:   webhook,
+			})
+		}
 	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		pathI, pathJ := endpoints[i].path.Path, endpoints[j].path.Path
+		if pathI == pathJ {
+			return endpoints[i].operation.Method < endpoints[j].operation.Method
+		}
+		return pathI < pathJ
+	})
+
+	return endpoints
 }
 
 func (m Model) Init() tea.Cmd {
@@ -89,17 +188,49 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.height = msg.Height
 		m.viewport.Width = msg.Width - 4
 		m.viewport.Height = msg.Height - 10
+		m.exportViewport.Width = msg.Width - 4
+		m.exportViewport.Height = msg.Height - 12
 	case request.ResponseMsg:
+		m.recordHistory(msg)
+		m.responseFromHistory = false
 		m.lastResponse = msg.Body
+		m.lastRequest = &msg.Request
 		m.currentView = viewResponse
 		m.viewport.SetContent(m.formatResponse(msg))
 		m.viewport.YOffset = 0
+	case clipboardMsg:
+		m.exportStatus = msg.status()
+	case requestInvalidMsg:
+		m.showViolations = true
 	}
 
 	return m, nil
 }
 
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.currentView == viewEndpoints && m.searching && msg.String() != "ctrl+c" {
+		return m.handleSearchKeys(msg)
+	}
+
+	if m.currentView == viewRequestBuilder && m.savingRequest && msg.String() != "ctrl+c" {
+		return m.handleSaveRequestKeys(msg)
+	}
+
+	// Everything typed or pasted goes into the editor, "?" and "q" included.
+	if m.currentView == viewImportCurl && msg.String() != "ctrl+c" && msg.String() != "esc" {
+		return m.handleImportCurlKeys(msg)
+	}
+
+	// Cancelling goes back to where the Authorize screen was opened from.
+	if m.currentView == viewAuthorize && msg.String() == "esc" {
+		return m.handleAuthorizeKeys(msg)
+	}
+
+	// The same for the server picker.
+	if m.currentView == viewServers && msg.String() == "esc" {
+		return m.handleServersKeys(msg)
+	}
+
 	switch msg.String() {
 	case "ctrl+c", "q":
 		if m.currentView == viewEndpoints {
@@ -109,6 +240,10 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.showHelp = !m.showHelp
 		return m, nil
 	case "esc":
+		if m.currentView == viewEndpoints && m.searchInput.Value() != "" {
+			m.clearSearch()
+			return m, nil
+		}
 		if m.currentView != viewEndpoints {
 			m.currentView = viewEndpoints
 			m.showHelp = false
@@ -126,6 +261,16 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleRequestBuilderKeys(msg)
 	case viewResponse:
 		return m.handleResponseKeys(msg)
+	case viewAuthorize:
+		return m.handleAuthorizeKeys(msg)
+	case viewServers:
+		return m.handleServersKeys(msg)
+	case viewHistory:
+		return m.handleHistoryKeys(msg)
+	case viewCollection:
+		return m.handleCollectionKeys(msg)
+	case viewExport:
+		return m.handleExportKeys(msg)
 	}
 
 	return m, nil
@@ -150,6 +295,18 @@ func (m Model) View() string {
 		content = m.renderRequestBuilder()
 	case viewResponse:
 		content = m.viewport.View()
+	case viewAuthorize:
+		content = m.renderAuthorize()
+	case viewServers:
+		content = m.renderServers()
+	case viewHistory:
+		content = m.renderHistory()
+	case viewCollection:
+		content = m.renderCollection()
+	case viewExport:
+		content = m.renderExport()
+	case viewImportCurl:
+		content = m.renderImportCurl()
 	}
 
 	if m.showHelp {
@@ -161,7 +318,14 @@ func (m Model) View() string {
 
 func (m Model) renderHeader() string {
 	title := styles.TitleStyle.Render("🚀 TAPI - Terminal API Explorer")
-	subtitle := styles.SubtitleStyle.Render(fmt.Sprintf("%s v%s", m.spec.Title, m.spec.Version))
+	info := fmt.Sprintf("%s v%s", m.spec.Title, m.spec.Version)
+	if server := m.baseURL(m.getCurrentOperation()); server != "" {
+		info += " • " + server
+	}
+	if m.activeEnv != "" {
+		info += " • env: " + m.activeEnv
+	}
+	subtitle := styles.SubtitleStyle.Render(info)
 
 	return lipgloss.JoinVertical(lipgloss.Left, title, subtitle, "")
 }
@@ -170,13 +334,33 @@ func (m Model) renderFooter() string {
 	var keys string
 	switch m.currentView {
 	case viewEndpoints:
-		keys = "j/k: navigate • enter: select • ?: help • q: quit"
+		keys = "j/k: navigate • enter: select • /: search • t: grouping • s: server • E: env • H: history • C: collection • i: import curl • a: authorize • ?: help • q: quit"
+		if m.searching {
+			keys = "type to filter • ↑/↓: navigate • enter: confirm • esc: clear search"
+		}
 	case viewOperationDetails:
-		keys = "j/k: scroll • e: execute • h: back • ?: help • esc: exit"
+		keys = "j/k: scroll • e: execute • s: server • E: env • H: history • C: collection • a: authorize • h: back • ?: help • esc: exit"
 	case viewRequestBuilder:
-		keys = "tab: next field • ctrl+s: send request • h: back • esc: cancel"
+		keys = "tab: next field • ctrl+t: content type • ctrl+o: save to collection • ctrl+x: export • ctrl+s: send request • esc: cancel"
+		if m.savingRequest {
+			keys = "enter: save • esc: cancel"
+		} else if m.showViolations {
+			keys = "tab: next field • ctrl+s: send request • ctrl+g: send anyway • esc: cancel"
+		}
 	case viewResponse:
-		keys = "j/k: scroll • h: back • esc: exit"
+		keys = "j/k: scroll • x: export • H: history • h: back • esc: exit"
+	case viewAuthorize:
+		keys = "tab: next field • enter/ctrl+s: save • esc: cancel"
+	case viewServers:
+		keys = "j/k: choose server • tab: edit variables • enter/ctrl+s: use server • esc: cancel"
+	case viewCollection:
+		keys = "j/k: navigate • enter: load into request builder • r: run • h: back • esc: exit"
+	case viewExport:
+		keys = "tab/←/→: format • j/k: scroll • y: copy to clipboard • h: back • esc: exit"
+	case viewImportCurl:
+		keys = "paste a curl command • ctrl+s: import • esc: cancel"
+	case viewHistory:
+		keys = "j/k: navigate • enter: show response • r: replay • o: this operation/all • h: back • esc: exit"
 	}
 
 	return styles.HelpStyle.Render(keys)
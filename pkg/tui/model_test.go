@@ -89,8 +89,8 @@ func TestNewModel(t *testing.T) {
 	}
 
 	expected := "GET /users"
-	if model.endpointsList[0] != expected {
-		t.Errorf("NewModel() first endpoint = %q, want %q", model.endpointsList[0], expected)
+	if model.endpointsList[0].String() != expected {
+		t.Errorf("NewModel() first endpoint = %q, want %q", model.endpointsList[0].String(), expected)
 	}
 }
 
@@ -301,3 +301,51 @@ func TestRenderFooter(t *testing.T) {
 		})
 	}
 }
+
+func TestNewModelWithWebhooks(t *testing.T) {
+	spec := createTestSpec()
+	spec.Webhooks = []openapi.Path{
+		{
+			Path: "userCreated",
+			Operations: []openapi.Operation{
+				{Method: "POST", Summary: "User created event"},
+			},
+		},
+	}
+
+	model := NewModel(spec)
+
+	if len(model.endpointsList) != 4 {
+		t.Fatalf("NewModel() endpointsList length = %d, want 4", len(model.endpointsList))
+	}
+
+	last := model.endpointsList[3]
+	if !last.webhook || last.String() != "POST userCreated" {
+		t.Errorf("NewModel() expected webhook listed last, got %q (webhook=%v)", last.String(), last.webhook)
+	}
+
+	for _, e := range model.endpointsList[:3] {
+		if e.webhook {
+			t.Errorf("NewModel() path operation %q marked as webhook", e.String())
+		}
+	}
+}
+
+func TestNewModelSortsEndpoints(t *testing.T) {
+	spec := &openapi.Spec{
+		Paths: []openapi.Path{
+			{Path: "/b", Operations: []openapi.Operation{{Method: "POST", Summary: "b-post"}, {Method: "GET", Summary: "b-get"}}},
+			{Path: "/a", Operations: []openapi.Operation{{Method: "GET", Summary: "a-get"}}},
+		},
+	}
+
+	model := NewModel(spec)
+
+	expected := []string{"a-get", "b-get", "b-post"}
+	for i, want := range expected {
+		model.selectedEndpoint = i
+		if op := model.getCurrentOperation(); op == nil || op.Summary != want {
+			t.Errorf("getCurrentOperation() at %d = %v, want %q", i, op, want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/internal/styles"
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+// requestInvalidMsg reports that sending was stopped because the request
+// doesn't match the spec.
+type requestInvalidMsg struct{}
+
+// requestViolations checks req against the current operation. Credentials
+// are left out, they aren't part of the check and applying them may need a
+// token request.
+func (m Model) requestViolations(req request.Request) []openapi.Violation {
+	path := m.getCurrentPath()
+	if path == nil {
+		return nil
+	}
+
+	req.Credentials = nil
+
+	httpReq, err := req.HTTPRequest(context.Background())
+	if err != nil {
+		return []openapi.Violation{{Message: err.Error()}}
+	}
+
+	pathParams := make(map[string]string, len(req.PathParams))
+	for name, value := range req.PathParams {
+		if unescaped, err := url.PathUnescape(value); err == nil {
+			value = unescaped
+		}
+		pathParams[name] = value
+	}
+
+	violations, _ := m.spec.ValidateRequest(path.Path, httpReq, pathParams)
+
+	return violations
+}
+
+// visibleViolations are shown in the request builder once sending was
+// stopped, and follow the form as it's fixed.
+func (m Model) visibleViolations() []openapi.Violation {
+	if !m.showViolations {
+		return nil
+	}
+
+	req, ok := m.buildRequest()
+	if !ok {
+		return nil
+	}
+
+	return m.requestViolations(req)
+}
+
+// sendRequestAnyway sends the request built from the form even when it
+// doesn't match the spec.
+func (m Model) sendRequestAnyway() tea.Cmd {
+	req, ok := m.buildRequest()
+	if !ok {
+		return nil
+	}
+
+	return request.Send(req)
+}
+
+// renderFieldViolations lists the violations for a parameter, or for the
+// body when in is "body", under its input.
+func renderFieldViolations(violations []openapi.Violation, in, name string) string {
+	var b strings.Builder
+
+	for _, v := range violations {
+		if v.In != in || v.Name != name {
+			continue
+		}
+
+		msg := v.Message
+		if v.Path != "" {
+			msg = v.Path + " " + msg
+		}
+		b.WriteString(styles.ErrorStyle.Render("✗ " + msg))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func renderValidationStatus(violations []openapi.Violation) string {
+	if len(violations) == 0 {
+		return styles.SuccessStyle.Render("✓ Request matches the spec") + "\n"
+	}
+
+	var b strings.Builder
+
+	b.WriteString(styles.ErrorStyle.Render("✗ Request doesn't match the spec, fix the highlighted fields or press Ctrl+G to send anyway"))
+	b.WriteString("\n")
+	b.WriteString(renderFieldViolations(violations, "", ""))
+
+	return b.String()
+}
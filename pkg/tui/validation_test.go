@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+const validationTestSpec = `openapi: 3.0.3
+info:
+  title: Pets
+  version: 1.0.0
+servers:
+  - url: SERVER
+paths:
+  /pets/{petId}:
+    put:
+      parameters:
+        - name: petId
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: status
+          in: query
+          schema:
+            type: string
+            enum: [available, sold]
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                  maxLength: 5
+      responses:
+        '200':
+          description: ok
+`
+
+func createValidationTestModel(t *testing.T, serverURL string) Model {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(file, []byte(strings.Replace(validationTestSpec, "SERVER", serverURL, 1)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := openapi.LoadFromFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(spec)
+	m.width, m.height = 120, 60
+	m.currentView = viewRequestBuilder
+	m.setupRequestBuilder()
+
+	return m
+}
+
+func TestSendRequestValidation(t *testing.T) {
+	sent := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		sent++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	m := createValidationTestModel(t, server.URL)
+	m.inputs[0].SetValue("abc")
+	m.inputs[1].SetValue("lost")
+	m.bodyEditor.SetValue(`{"name": "snowball"}`)
+
+	updated, cmd := m.handleRequestBuilderKeys(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+
+	msg := cmd()
+	if _, ok := msg.(requestInvalidMsg); !ok {
+		t.Fatalf("ctrl+s = %T, want requestInvalidMsg", msg)
+	}
+
+	updated, _ = m.Update(msg)
+	m = updated.(Model)
+
+	if !m.showViolations || sent != 0 {
+		t.Fatalf("showViolations = %v, sent = %d, want the request held back", m.showViolations, sent)
+	}
+
+	view := m.renderRequestBuilder()
+	for _, want := range []string{"✗ value abc: an invalid integer", "Request doesn't match the spec", "✗ value is not one of the allowed values", "$.name", "maximum string length is 5"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("renderRequestBuilder() missing %q in:\n%s", want, view)
+		}
+	}
+
+	if !strings.Contains(m.renderFooter(), "ctrl+g: send anyway") {
+		t.Errorf("renderFooter() = %q, want the send anyway key", m.renderFooter())
+	}
+
+	_, cmd = m.handleRequestBuilderKeys(tea.KeyMsg{Type: tea.KeyCtrlG})
+	if resp, ok := cmd().(request.ResponseMsg); !ok || resp.Error != nil || sent != 1 {
+		t.Fatalf("ctrl+g = %+v, sent = %d, want the request sent anyway", resp, sent)
+	}
+
+	m.inputs[0].SetValue("7")
+	m.inputs[1].SetValue("sold")
+	m.bodyEditor.SetValue(`{"name": "rex"}`)
+
+	if view := m.renderRequestBuilder(); !strings.Contains(view, "✓ Request matches the spec") {
+		t.Errorf("renderRequestBuilder() should follow the fixes:\n%s", view)
+	}
+
+	if resp, ok := m.sendRequest()().(request.ResponseMsg); !ok || resp.Error != nil || sent != 2 {
+		t.Errorf("sendRequest() = %+v, sent = %d, want a valid request sent", resp, sent)
+	}
+
+	m.setupRequestBuilder()
+	if m.showViolations {
+		t.Error("setupRequestBuilder() should reset showViolations")
+	}
+}
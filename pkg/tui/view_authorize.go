@@ -0,0 +1,221 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/internal/styles"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+// authField is a single credential input on the Authorize screen.
+type authField struct {
+	scheme string
+	key    string
+	input  textinput.Model
+}
+
+func (m Model) handleAuthorizeKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "tab", "down":
+		if len(m.authFields) > 0 {
+			m.focusedAuthField = (m.focusedAuthField + 1) % len(m.authFields)
+			return m, m.focusAuthField()
+		}
+	case "shift+tab", "up":
+		if len(m.authFields) > 0 {
+			m.focusedAuthField--
+			if m.focusedAuthField < 0 {
+				m.focusedAuthField = len(m.authFields) - 1
+			}
+			return m, m.focusAuthField()
+		}
+	case "enter":
+		if m.focusedAuthField < len(m.authFields)-1 {
+			m.focusedAuthField++
+			return m, m.focusAuthField()
+		}
+		m.saveAuthorization()
+		return m, nil
+	case "ctrl+s":
+		m.saveAuthorization()
+		return m, nil
+	case "esc":
+		m.currentView = m.authReturnView
+		m.showHelp = false
+		return m, nil
+	}
+
+	if len(m.authFields) > 0 {
+		var cmd tea.Cmd
+		field := &m.authFields[m.focusedAuthField]
+		field.input, cmd = field.input.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// openAuthorize switches to the Authorize screen, remembering where to go
+// back to once credentials are saved.
+func (m *Model) openAuthorize() tea.Cmd {
+	m.authReturnView = m.currentView
+	m.currentView = viewAuthorize
+	m.setupAuthorize()
+
+	if len(m.authFields) == 0 {
+		return nil
+	}
+	return m.authFields[0].input.Focus()
+}
+
+func (m *Model) setupAuthorize() {
+	m.authFields = make([]authField, 0)
+	m.focusedAuthField = 0
+	m.authErrors = nil
+
+	for _, name := range m.securitySchemeNames() {
+		scheme := m.spec.SecuritySchemes[name]
+		for _, key := range request.CredentialFields(scheme) {
+			ti := textinput.New()
+			ti.Placeholder = key
+			ti.CharLimit = 4096
+			ti.Width = 50
+			ti.Prompt = fmt.Sprintf("%s: ", key)
+			ti.SetValue(m.authValues[name][key])
+			if key == "password" || key == "client_secret" {
+				ti.EchoMode = textinput.EchoPassword
+			}
+			m.authFields = append(m.authFields, authField{scheme: name, key: key, input: ti})
+		}
+	}
+}
+
+func (m *Model) focusAuthField() tea.Cmd {
+	var cmd tea.Cmd
+	for i := range m.authFields {
+		if i == m.focusedAuthField {
+			cmd = m.authFields[i].input.Focus()
+		} else {
+			m.authFields[i].input.Blur()
+		}
+	}
+	return cmd
+}
+
+// saveAuthorization turns the entered values into credentials. Schemes left
+// empty lose any credential they had before.
+func (m *Model) saveAuthorization() {
+	values := make(map[string]map[string]string)
+	for _, field := range m.authFields {
+		if values[field.scheme] == nil {
+			values[field.scheme] = make(map[string]string)
+		}
+		values[field.scheme][field.key] = strings.TrimSpace(field.input.Value())
+	}
+
+	m.authValues = values
+	m.buildCredentials()
+
+	// Stay on the screen so the values can be fixed.
+	if len(m.authErrors) > 0 {
+		return
+	}
+
+	m.currentView = m.authReturnView
+}
+
+// buildCredentials creates the credentials from the values entered on the
+// Authorize screen, with {{name}} placeholders filled from the active
+// environment.
+func (m *Model) buildCredentials() {
+	m.credentials = make(map[string]request.Credential)
+	m.authErrors = make(map[string]error)
+
+	for name, schemeValues := range m.authValues {
+		values := make(map[string]string, len(schemeValues))
+		for key, value := range schemeValues {
+			values[key] = m.interpolate(value)
+		}
+
+		cred, err := request.NewCredential(m.spec.SecuritySchemes[name], values)
+		if err != nil {
+			m.authErrors[name] = err
+			continue
+		}
+		if cred != nil {
+			m.credentials[name] = cred
+		}
+	}
+}
+
+func (m Model) securitySchemeNames() []string {
+	names := make([]string, 0, len(m.spec.SecuritySchemes))
+	for name := range m.spec.SecuritySchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (m Model) renderAuthorize() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render("Authorize"))
+	b.WriteString("\n\n")
+
+	if len(m.spec.SecuritySchemes) == 0 {
+		b.WriteString(styles.HelpStyle.Render("This API doesn't define any security schemes"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	lastScheme := ""
+	for i, field := range m.authFields {
+		if field.scheme != lastScheme {
+			lastScheme = field.scheme
+			scheme := m.spec.SecuritySchemes[field.scheme]
+
+			kind := scheme.Type
+			switch {
+			case scheme.Type == "apiKey":
+				kind = fmt.Sprintf("apiKey, %s %q", scheme.In, scheme.ParamName)
+			case scheme.Scheme != "":
+				kind = fmt.Sprintf("%s, %s", scheme.Type, scheme.Scheme)
+			}
+
+			b.WriteString(styles.LabelStyle.Render(field.scheme))
+			b.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf("(%s)", kind)))
+			if _, ok := m.credentials[field.scheme]; ok {
+				b.WriteString(styles.SuccessStyle.Render(" ✓ authorized"))
+			}
+			b.WriteString("\n")
+
+			if err := m.authErrors[field.scheme]; err != nil {
+				b.WriteString(styles.ErrorStyle.Render("✗ " + err.Error()))
+				b.WriteString("\n")
+			}
+
+			if scheme.Description != "" {
+				b.WriteString(scheme.Description)
+				b.WriteString("\n")
+			}
+		}
+
+		if i == m.focusedAuthField {
+			b.WriteString(styles.FocusedInputStyle.Render(field.input.View()))
+		} else {
+			b.WriteString(styles.InputStyle.Render(field.input.View()))
+		}
+		b.WriteString("\n")
+	}
+
+	// Help to fix issue that content is not possible to scroll down fully
+	b.WriteString("\n\n\n\n")
+
+	return b.String()
+}
@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+func createSecuredTestSpec() *openapi.Spec {
+	spec := createTestSpec()
+	spec.SecuritySchemes = map[string]openapi.SecurityScheme{
+		"apiKey": {Name: "apiKey", Type: "apiKey", In: "header", ParamName: "X-API-Key"},
+		"basic":  {Name: "basic", Type: "http", Scheme: "basic"},
+	}
+	spec.Paths[0].Operations[0].Security = []openapi.SecurityRequirement{{"apiKey": nil}}
+
+	return spec
+}
+
+func typeText(m Model, text string) Model {
+	for _, r := range text {
+		updated, _ := m.handleAuthorizeKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestOpenAuthorize(t *testing.T) {
+	model := NewModel(createSecuredTestSpec())
+
+	updatedModel, _ := model.handleEndpointsKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	m := updatedModel.(Model)
+
+	if m.currentView != viewAuthorize {
+		t.Fatalf("currentView = %v, want %v", m.currentView, viewAuthorize)
+	}
+
+	// apiKey: value, basic: username + password
+	if len(m.authFields) != 3 {
+		t.Fatalf("authFields = %d, want 3", len(m.authFields))
+	}
+
+	if m.authFields[0].scheme != "apiKey" || m.authFields[1].key != "username" {
+		t.Errorf("unexpected field order: %+v", m.authFields)
+	}
+}
+
+func TestAuthorizeSaveCredentials(t *testing.T) {
+	model := NewModel(createSecuredTestSpec())
+	model.width = 100
+	model.height = 50
+	model.openAuthorize()
+
+	m := typeText(model, "secret")
+
+	updatedModel, _ := m.handleAuthorizeKeys(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updatedModel.(Model)
+
+	if m.currentView != viewEndpoints {
+		t.Errorf("currentView = %v, want return to %v", m.currentView, viewEndpoints)
+	}
+
+	cred, ok := m.credentials["apiKey"]
+	if !ok {
+		t.Fatal("expected apiKey credential to be saved")
+	}
+
+	if cred != (request.APIKey{Name: "X-API-Key", In: "header", Value: "secret"}) {
+		t.Errorf("credential = %#v", cred)
+	}
+
+	if _, ok := m.credentials["basic"]; ok {
+		t.Error("empty basic credentials should not be saved")
+	}
+
+	m.openAuthorize()
+	if got := m.authFields[0].input.Value(); got != "secret" {
+		t.Errorf("reopened apiKey field = %q, want saved value", got)
+	}
+
+	if !strings.Contains(m.renderAuthorize(), "authorized") {
+		t.Error("renderAuthorize() should mark authorized schemes")
+	}
+}
+
+func TestAuthorizeNavigation(t *testing.T) {
+	model := NewModel(createSecuredTestSpec())
+	model.openAuthorize()
+
+	updatedModel, _ := model.handleAuthorizeKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m := updatedModel.(Model)
+
+	if m.focusedAuthField != 1 || m.currentView != viewAuthorize {
+		t.Errorf("enter should advance focus, got field %d view %v", m.focusedAuthField, m.currentView)
+	}
+
+	updatedModel, _ = m.handleAuthorizeKeys(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m = updatedModel.(Model)
+	updatedModel, _ = m.handleAuthorizeKeys(tea.KeyMsg{Type: tea.KeyShiftTab})
+	m = updatedModel.(Model)
+
+	if m.focusedAuthField != 2 {
+		t.Errorf("shift+tab should wrap around, got field %d", m.focusedAuthField)
+	}
+}
+
+func TestAuthorizeCancel(t *testing.T) {
+	model := NewModel(createSecuredTestSpec())
+	model.currentView = viewOperationDetails
+	model.openAuthorize()
+
+	m := typeText(model, "secret")
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updatedModel.(Model)
+
+	if m.currentView != viewOperationDetails {
+		t.Errorf("currentView = %v, want return to %v", m.currentView, viewOperationDetails)
+	}
+
+	if _, ok := m.credentials["apiKey"]; ok {
+		t.Error("cancelling should not save credentials")
+	}
+}
+
+func TestAuthorizeShowsCredentialErrors(t *testing.T) {
+	spec := createTestSpec()
+	spec.SecuritySchemes = map[string]openapi.SecurityScheme{
+		"oauth": {Name: "oauth", Type: "oauth2", Flows: &openapi.OAuthFlows{
+			ClientCredentials: &openapi.OAuthFlow{TokenURL: "token"},
+		}},
+	}
+
+	model := NewModel(spec)
+	model.openAuthorize()
+
+	// token, client_id, client_secret
+	updatedModel, _ := model.handleAuthorizeKeys(tea.KeyMsg{Type: tea.KeyTab})
+	m := typeText(updatedModel.(Model), "app")
+
+	updatedModel, _ = m.handleAuthorizeKeys(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updatedModel.(Model)
+
+	if m.currentView != viewAuthorize {
+		t.Errorf("currentView = %v, want to stay on %v", m.currentView, viewAuthorize)
+	}
+
+	if _, ok := m.credentials["oauth"]; ok {
+		t.Error("credential that failed to set up should not be saved")
+	}
+
+	if !strings.Contains(m.renderAuthorize(), "failed to set up OAuth2") {
+		t.Errorf("renderAuthorize() should show the error, got %q", m.renderAuthorize())
+	}
+}
+
+func TestRenderAuthorizeWithoutSchemes(t *testing.T) {
+	model := NewModel(createTestSpec())
+	model.openAuthorize()
+
+	if !strings.Contains(model.renderAuthorize(), "doesn't define any security schemes") {
+		t.Error("renderAuthorize() should explain missing security schemes")
+	}
+}
+
+func TestOperationDetailsSecurity(t *testing.T) {
+	model := NewModel(createSecuredTestSpec())
+	model.credentials = map[string]request.Credential{"apiKey": request.APIKey{}}
+
+	details := model.getOperationDetails()
+	if !strings.Contains(details, "Security") || !strings.Contains(details, "apiKey") {
+		t.Errorf("getOperationDetails() should list security requirements, got %q", details)
+	}
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/internal/styles"
+	"github.com/ksysoev/tapi/pkg/collection"
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+// WithCollection saves requests to and loads them from the collection file
+// at path.
+func WithCollection(path string) Option {
+	return func(m *Model) {
+		m.collectionPath = path
+	}
+}
+
+// startSaveRequest asks for a name to save the request builder under,
+// suggesting the operationId.
+func (m *Model) startSaveRequest() tea.Cmd {
+	op, path := m.getCurrentOperation(), m.getCurrentPath()
+	if op == nil || path == nil || m.collectionPath == "" {
+		return nil
+	}
+
+	ti := textinput.New()
+	ti.Prompt = "Save as: "
+	ti.CharLimit = 128
+	ti.Width = 50
+	ti.SetValue(operationRef(op, path))
+
+	m.saveInput = ti
+	m.savingRequest = true
+	m.builderStatus = ""
+
+	return m.saveInput.Focus()
+}
+
+func (m Model) handleSaveRequestKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.savingRequest = false
+		return m, nil
+	case "enter":
+		name := strings.TrimSpace(m.saveInput.Value())
+		if name == "" {
+			return m, nil
+		}
+
+		m.savingRequest = false
+		if err := m.saveRequest(name); err != nil {
+			m.builderStatus = "✗ " + err.Error()
+		} else {
+			m.builderStatus = fmt.Sprintf("✓ Saved %q to %s", name, m.collectionPath)
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.saveInput, cmd = m.saveInput.Update(msg)
+
+	return m, cmd
+}
+
+// saveRequest stores the request builder's values in the collection file.
+// Empty parameters are left out.
+func (m Model) saveRequest(name string) error {
+	op, path := m.getCurrentOperation(), m.getCurrentPath()
+
+	saved := collection.Request{
+		Name:      name,
+		Operation: operationRef(op, path),
+		Headers:   m.requestHeaders,
+	}
+
+	for i, input := range m.inputs {
+		if i < len(op.Parameters) && input.Value() != "" {
+			if saved.Params == nil {
+				saved.Params = make(map[string]string)
+			}
+			saved.Params[op.ParamKey(op.Parameters[i])] = input.Value()
+		}
+	}
+
+	if m.hasBody {
+		saved.Body = m.bodyEditor.Value()
+		saved.ContentType = m.selectedContentType()
+	}
+
+	c, err := collection.Load(m.collectionPath)
+	if err != nil {
+		return err
+	}
+
+	c.Put(saved)
+
+	return c.Save(m.collectionPath)
+}
+
+// operationRef identifies an operation by its operationId, falling back to
+// "METHOD /path".
+func operationRef(op *openapi.Operation, path *openapi.Path) string {
+	if op.OperationID != "" {
+		return op.OperationID
+	}
+
+	return op.Method + " " + path.Path
+}
+
+func (m Model) handleCollectionKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var requests []collection.Request
+	if m.collection != nil {
+		requests = m.collection.Requests
+	}
+
+	switch msg.String() {
+	case "j", "down":
+		if m.collectionCursor < len(requests)-1 {
+			m.collectionCursor++
+		}
+	case "k", "up":
+		if m.collectionCursor > 0 {
+			m.collectionCursor--
+		}
+	case "g":
+		m.collectionCursor = 0
+	case "G":
+		if len(requests) > 0 {
+			m.collectionCursor = len(requests) - 1
+		}
+	case "enter", "l", "right":
+		if m.collectionCursor < len(requests) {
+			m.collectionErr = m.loadSavedRequest(requests[m.collectionCursor])
+		}
+	case "r":
+		if m.collectionCursor < len(requests) {
+			m.collectionErr = m.loadSavedRequest(requests[m.collectionCursor])
+			if m.collectionErr == nil {
+				return m, m.sendRequest()
+			}
+		}
+	case "h", "left":
+		m.currentView = m.collectionReturnView
+	}
+
+	return m, nil
+}
+
+// openCollection loads the collection file and shows the saved requests.
+func (m *Model) openCollection() tea.Cmd {
+	m.collectionReturnView = m.currentView
+	m.currentView = viewCollection
+	m.collectionCursor = 0
+	m.collection = nil
+	m.collectionErr = nil
+
+	if m.collectionPath != "" {
+		m.collection, m.collectionErr = collection.Load(m.collectionPath)
+	}
+
+	return nil
+}
+
+// loadSavedRequest opens the request builder filled with a saved request.
+func (m *Model) loadSavedRequest(r collection.Request) error {
+	_, op, err := m.spec.FindOperation(r.Operation)
+	if err != nil {
+		return fmt.Errorf("request %q: %w", r.Name, err)
+	}
+
+	values := func(param openapi.Parameter) (string, bool) {
+		value, _, ok := op.ParamValue(r.Params, param)
+		return value, ok
+	}
+
+	return m.openRequestBuilder(op, values, r.Body, r.ContentType, r.Headers)
+}
+
+func (m Model) renderCollection() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render("Collection"))
+	b.WriteString(styles.SubtitleStyle.Render(m.collectionPath))
+	b.WriteString("\n\n")
+
+	if m.collectionErr != nil {
+		b.WriteString(styles.ErrorStyle.Render(m.collectionErr.Error()))
+		b.WriteString("\n\n")
+	}
+
+	if m.collection == nil || len(m.collection.Requests) == 0 {
+		b.WriteString(styles.HelpStyle.Render("No saved requests, press Ctrl+O in the request builder to save one"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	requests := m.collection.Requests
+
+	start := 0
+	end := len(requests)
+
+	maxVisible := m.height - 15
+	if end-start > maxVisible {
+		if m.collectionCursor > maxVisible/2 {
+			start = m.collectionCursor - maxVisible/2
+		}
+		if end-start > maxVisible {
+			end = start + maxVisible
+		}
+	}
+
+	for i := start; i < end && i < len(requests); i++ {
+		r := requests[i]
+		line := fmt.Sprintf("%s  %s", styles.LabelStyle.Render(r.Name), styles.HelpStyle.UnsetPadding().Render(r.Operation))
+
+		if i == m.collectionCursor {
+			b.WriteString(styles.SelectedItemStyle.Render("▶ " + line))
+		} else {
+			b.WriteString(styles.ItemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	// Help to fix issue that content is not possible to scroll down fully
+	b.WriteString("\n\n\n\n")
+
+	return b.String()
+}
@@ -0,0 +1,169 @@
+package tui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/pkg/collection"
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+func createCollectionTestModel(t *testing.T, serverURL string) (Model, string) {
+	t.Helper()
+
+	spec := createBodyTestSpec(serverURL)
+	spec.Paths[0].Operations[0].OperationID = "createPet"
+	spec.Paths[0].Operations[0].Parameters = []openapi.Parameter{
+		{Name: "X-Tenant", In: "header", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "dryRun", In: "query", Schema: &openapi.Schema{Type: "boolean"}},
+	}
+	spec.Paths = append(spec.Paths, openapi.Path{Path: "/store", Operations: []openapi.Operation{{Method: "GET"}}})
+
+	path := filepath.Join(t.TempDir(), "spec.collection.yaml")
+	m := NewModel(spec, WithCollection(path))
+	m.height = 40
+
+	return m, path
+}
+
+func TestSaveRequestToCollection(t *testing.T) {
+	m, path := createCollectionTestModel(t, "https://api.example.com")
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEnter}, runes("e"))
+	if m.currentView != viewRequestBuilder {
+		t.Fatalf("expected the request builder, got view %v", m.currentView)
+	}
+	m.inputs[0].SetValue("{{tenantId}}")
+	m.bodyEditor.SetValue(`{"name":"rex"}`)
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyCtrlO})
+	if !m.savingRequest || m.saveInput.Value() != "createPet" {
+		t.Fatalf("ctrl+o should ask for a name, suggesting the operationId, got %q", m.saveInput.Value())
+	}
+
+	// esc cancels the prompt without leaving the request builder
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.savingRequest || m.currentView != viewRequestBuilder {
+		t.Fatalf("esc should only close the prompt, view %v", m.currentView)
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyCtrlO}, tea.KeyMsg{Type: tea.KeyCtrlU}, runes("new pet"), tea.KeyMsg{Type: tea.KeyEnter})
+	if !strings.Contains(m.renderRequestBuilder(), `Saved "new pet"`) {
+		t.Errorf("renderRequestBuilder() should confirm the save, status %q", m.builderStatus)
+	}
+
+	c, err := collection.Load(path)
+	if err != nil || len(c.Requests) != 1 {
+		t.Fatalf("Load() = %+v, %v", c, err)
+	}
+
+	saved := c.Requests[0]
+	if saved.Name != "new pet" || saved.Operation != "createPet" || saved.Body != `{"name":"rex"}` || saved.ContentType != "application/json" {
+		t.Errorf("saved = %+v", saved)
+	}
+
+	if saved.Params["X-Tenant"] != "{{tenantId}}" || len(saved.Params) != 1 {
+		t.Errorf("saved params = %v, want only non-empty ones", saved.Params)
+	}
+}
+
+func TestCollectionViewLoadAndRun(t *testing.T) {
+	var gotHeader, gotTrace, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader, gotTrace, gotQuery = r.Header.Get("X-Tenant"), r.Header.Get("X-Trace"), r.URL.RawQuery
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	m, path := createCollectionTestModel(t, server.URL)
+
+	c := &collection.Collection{Requests: []collection.Request{
+		{Name: "missing", Operation: "deletePet"},
+		{
+			Name:      "create",
+			Operation: "POST /pets",
+			Params:    map[string]string{"X-Tenant": "42", "dryRun": "true"},
+			Headers:   map[string]string{"X-Trace": "abc"},
+			Body:      `{"name":"saved"}`,
+		},
+	}}
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	m.selectedEndpoint = 1
+	m = pressKeys(m, runes("C"))
+	if m.currentView != viewCollection || !strings.Contains(m.renderCollection(), "create") {
+		t.Fatalf("C should list the saved requests, got view %v", m.currentView)
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.collectionErr == nil || m.currentView != viewCollection {
+		t.Errorf("loading an unknown operation should report an error, got %v", m.collectionErr)
+	}
+
+	m = pressKeys(m, runes("j"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.currentView != viewRequestBuilder {
+		t.Fatalf("enter should load the request into the builder, got view %v", m.currentView)
+	}
+
+	if op := m.getCurrentOperation(); op.Method != "POST" || m.inputs[0].Value() != "42" || m.bodyEditor.Value() != `{"name":"saved"}` {
+		t.Errorf("loaded %s with inputs %q, body %q", op.Method, m.inputs[0].Value(), m.bodyEditor.Value())
+	}
+
+	if !strings.Contains(m.renderRequestBuilder(), "X-Trace: abc") {
+		t.Error("renderRequestBuilder() should show the saved headers")
+	}
+
+	m.currentView = viewEndpoints
+	m = pressKeys(m, runes("C"), runes("j"))
+	_, cmd := m.handleKeyPress(runes("r"))
+	if cmd == nil {
+		t.Fatal("r should send the saved request")
+	}
+
+	if resp, ok := cmd().(request.ResponseMsg); !ok || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("run = %+v", resp)
+	}
+
+	if gotHeader != "42" || gotTrace != "abc" || gotQuery != "dryRun=true" {
+		t.Errorf("request header %q, trace %q, query %q", gotHeader, gotTrace, gotQuery)
+	}
+}
+
+func TestCollectionSharedParamNames(t *testing.T) {
+	m, path := createCollectionTestModel(t, "https://api.example.com")
+	op := &m.spec.Paths[0].Operations[0]
+	op.Parameters = append(op.Parameters, openapi.Parameter{Name: "dryRun", In: "header", Schema: &openapi.Schema{Type: "boolean"}})
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEnter}, runes("e"))
+	m.inputs[1].SetValue("true")
+	m.inputs[2].SetValue("false")
+
+	if err := m.saveRequest("dry run"); err != nil {
+		t.Fatalf("saveRequest() error = %v", err)
+	}
+
+	c, err := collection.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved := c.Requests[0]
+	if saved.Params["query:dryRun"] != "true" || saved.Params["header:dryRun"] != "false" {
+		t.Fatalf("saved params = %v, want shared names keyed by location", saved.Params)
+	}
+
+	if err := m.loadSavedRequest(saved); err != nil {
+		t.Fatalf("loadSavedRequest() error = %v", err)
+	}
+
+	if m.inputs[1].Value() != "true" || m.inputs[2].Value() != "false" {
+		t.Errorf("loaded inputs %q and %q", m.inputs[1].Value(), m.inputs[2].Value())
+	}
+}
@@ -2,16 +2,27 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ksysoev/tapi/internal/styles"
 )
 
+// endpointMatch records where a search query matched an endpoint. Field is
+// empty when it matched the "METHOD path" line itself.
+type endpointMatch struct {
+	field     string
+	text      string
+	positions []int
+}
+
 func (m Model) handleEndpointsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	row := m.currentRow()
+
 	switch msg.String() {
 	case "j", "down":
-		if m.selectedEndpoint < len(m.endpointsList)-1 {
+		if m.selectedEndpoint < len(m.rows)-1 {
 			m.selectedEndpoint++
 		}
 	case "k", "up":
@@ -21,8 +32,45 @@ func (m Model) handleEndpointsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "g":
 		m.selectedEndpoint = 0
 	case "G":
-		m.selectedEndpoint = len(m.endpointsList) - 1
+		if len(m.rows) > 0 {
+			m.selectedEndpoint = len(m.rows) - 1
+		}
+	case "/":
+		m.searching = true
+		return m, m.searchInput.Focus()
+	case "t":
+		m.setGroupMode(m.groupMode.next())
+	case "a":
+		return m, m.openAuthorize()
+	case "s":
+		return m, m.openServers()
+	case "E":
+		m.cycleEnvironment()
+	case "H":
+		return m, m.openHistory()
+	case "C":
+		return m, m.openCollection()
+	case "i":
+		return m, m.openImportCurl()
+	case "h", "left":
+		switch {
+		case row == nil:
+		case row.group != nil:
+			m.setCollapsed(row.group.key, true)
+		case row.groupKey != "":
+			m.setCollapsed(row.groupKey, true)
+		}
 	case "enter", "l", "right":
+		if row != nil && row.group != nil {
+			collapsed := m.collapsed[m.collapseKey(row.group.key)]
+			if msg.String() == "enter" || collapsed {
+				m.setCollapsed(row.group.key, !collapsed)
+			}
+			return m, nil
+		}
+		if m.getCurrentEndpoint() == nil {
+			return m, nil
+		}
 		m.currentView = viewOperationDetails
 		m.viewport.SetContent(m.getOperationDetails())
 		m.viewport.GotoTop()
@@ -30,14 +78,131 @@ func (m Model) handleEndpointsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.clearSearch()
+		return m, nil
+	case "enter":
+		m.searching = false
+		m.searchInput.Blur()
+		return m, nil
+	case "down", "ctrl+n":
+		if m.selectedEndpoint < len(m.rows)-1 {
+			m.selectedEndpoint++
+		}
+		return m, nil
+	case "up", "ctrl+p":
+		if m.selectedEndpoint > 0 {
+			m.selectedEndpoint--
+		}
+		return m, nil
+	}
+
+	query := m.searchInput.Value()
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+
+	if m.searchInput.Value() != query {
+		m.applySearch()
+	}
+
+	return m, cmd
+}
+
+func (m *Model) clearSearch() {
+	m.searching = false
+	m.searchInput.Blur()
+	m.searchInput.SetValue("")
+	m.applySearch()
+}
+
+// applySearch filters the endpoints list down to fuzzy matches of the search
+// query, best matches first. Selection jumps to the top result.
+func (m *Model) applySearch() {
+	m.selectedEndpoint = 0
+	m.searchMatches = nil
+
+	defer m.buildRows()
+
+	query := m.searchInput.Value()
+	if strings.TrimSpace(query) == "" {
+		m.endpointsList = m.allEndpoints
+		return
+	}
+
+	type result struct {
+		endpoint endpoint
+		match    endpointMatch
+		score    int
+	}
+
+	results := make([]result, 0)
+	for _, e := range m.allEndpoints {
+		candidates := []endpointMatch{
+			{text: e.String()},
+			{field: "operationId", text: e.operation.OperationID},
+			{field: "summary", text: e.operation.Summary},
+			{field: "tags", text: strings.Join(e.operation.Tags, ", ")},
+		}
+
+		var best result
+		found := false
+		for _, c := range candidates {
+			if c.text == "" {
+				continue
+			}
+
+			score, positions, ok := fuzzyMatch(query, c.text)
+			if ok && (!found || score > best.score) {
+				c.positions = positions
+				best = result{endpoint: e, match: c, score: score}
+				found = true
+			}
+		}
+
+		if found {
+			results = append(results, best)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	m.endpointsList = make([]endpoint, 0, len(results))
+	m.searchMatches = make([]endpointMatch, 0, len(results))
+	for _, r := range results {
+		m.endpointsList = append(m.endpointsList, r.endpoint)
+		m.searchMatches = append(m.searchMatches, r.match)
+	}
+}
+
 func (m Model) renderEndpoints() string {
 	var b strings.Builder
 
 	b.WriteString(styles.TitleStyle.Render("Endpoints"))
+	if m.groupMode != groupFlat {
+		b.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf("grouped by %s", m.groupMode)))
+	}
 	b.WriteString("\n\n")
 
+	filtered := m.searchInput.Value() != ""
+	if m.searching || filtered {
+		b.WriteString(m.searchInput.View())
+		b.WriteString(styles.HelpStyle.UnsetPadding().Render(
+			fmt.Sprintf("  %d/%d", len(m.endpointsList), len(m.allEndpoints))))
+		b.WriteString("\n\n")
+
+		if len(m.endpointsList) == 0 {
+			b.WriteString(styles.HelpStyle.Render("No endpoints match"))
+			b.WriteString("\n")
+		}
+	}
+
 	start := 0
-	end := len(m.endpointsList)
+	end := len(m.rows)
 
 	maxVisible := m.height - 15
 	if end-start > maxVisible {
@@ -49,24 +214,30 @@ func (m Model) renderEndpoints() string {
 		}
 	}
 
-	for i := start; i < end && i < len(m.endpointsList); i++ {
-		parts := strings.Fields(m.endpointsList[i])
-		if len(parts) >= 2 {
-			method := parts[0]
-			path := strings.Join(parts[1:], " ")
-
-			line := fmt.Sprintf("%s %s",
-				styles.MethodStyle(method).Render(method),
-				path,
-			)
+	for i := start; i < end && i < len(m.rows); i++ {
+		row := m.rows[i]
 
-			if i == m.selectedEndpoint {
-				b.WriteString(styles.SelectedItemStyle.Render("▶ " + line))
-			} else {
-				b.WriteString(styles.ItemStyle.Render(line))
+		var line string
+		switch {
+		case row.group != nil:
+			line = m.renderGroupHeader(row.group)
+		case row.groupKey != "":
+			line = "  " + renderEndpointLine(*row.endpoint, nil)
+		default:
+			if !filtered && row.endpoint.webhook && (i == 0 || !m.rows[i-1].endpoint.webhook) {
+				b.WriteString("\n")
+				b.WriteString(styles.LabelStyle.Render("Webhooks"))
+				b.WriteString("\n")
 			}
-			b.WriteString("\n")
+			line = renderEndpointLine(*row.endpoint, row.match)
+		}
+
+		if i == m.selectedEndpoint {
+			b.WriteString(styles.SelectedItemStyle.Render("▶ " + line))
+		} else {
+			b.WriteString(styles.ItemStyle.Render(line))
 		}
+		b.WriteString("\n")
 	}
 
 	// Help to fix issue that content is not possible to scroll down fully
@@ -74,3 +245,86 @@ func (m Model) renderEndpoints() string {
 
 	return b.String()
 }
+
+func (m Model) renderGroupHeader(group *endpointGroup) string {
+	marker := "▾"
+	if m.collapsed[m.collapseKey(group.key)] {
+		marker = "▸"
+	}
+
+	line := fmt.Sprintf("%s %s (%d)", marker, styles.LabelStyle.Render(group.name), len(group.endpoints))
+	if group.description != "" {
+		line += " " + styles.HelpStyle.UnsetPadding().Render(firstLine(group.description))
+	}
+
+	return line
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
+
+// renderEndpointLine renders "METHOD path", highlighting the characters a
+// search matched. Matches in other fields are shown after the path.
+func renderEndpointLine(e endpoint, match *endpointMatch) string {
+	method := e.operation.Method
+	methodStyle := styles.MethodStyle(method)
+
+	if match == nil {
+		return fmt.Sprintf("%s %s", methodStyle.Render(method), e.path.Path)
+	}
+
+	if match.field != "" {
+		return fmt.Sprintf("%s %s  %s %s",
+			methodStyle.Render(method),
+			e.path.Path,
+			styles.HelpStyle.UnsetPadding().Render(match.field+":"),
+			highlight(match.text, match.positions, 0),
+		)
+	}
+
+	methodLen := len([]rune(method))
+	for _, pos := range match.positions {
+		if pos < methodLen {
+			methodStyle = methodStyle.Underline(true)
+			break
+		}
+	}
+
+	return fmt.Sprintf("%s %s",
+		methodStyle.Render(method),
+		highlight(e.path.Path, match.positions, methodLen+1),
+	)
+}
+
+// highlight styles the runes of text whose index plus offset is in
+// positions.
+func highlight(text string, positions []int, offset int) string {
+	matched := make(map[int]bool, len(positions))
+	for _, pos := range positions {
+		matched[pos-offset] = true
+	}
+
+	var b strings.Builder
+	var plain []rune
+
+	flush := func() {
+		if len(plain) > 0 {
+			b.WriteString(string(plain))
+			plain = plain[:0]
+		}
+	}
+
+	for i, r := range []rune(text) {
+		if matched[i] {
+			flush()
+			b.WriteString(styles.MatchStyle.Render(string(r)))
+			continue
+		}
+		plain = append(plain, r)
+	}
+	flush()
+
+	return b.String()
+}
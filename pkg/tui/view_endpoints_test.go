@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/internal/styles"
 	"github.com/ksysoev/tapi/pkg/openapi"
 )
 
@@ -131,3 +133,115 @@ func TestRenderEndpointsWithScrolling(t *testing.T) {
 		t.Error("renderEndpoints() with scrolling returned empty string")
 	}
 }
+
+func typeSearch(m Model, query string) Model {
+	updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = updated.(Model)
+
+	for _, r := range query {
+		updated, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+
+	return m
+}
+
+func TestEndpointsSearch(t *testing.T) {
+	spec := createTestSpec()
+	spec.Paths[0].Operations[1].OperationID = "createUser"
+	spec.Paths[0].Operations[1].Tags = []string{"admin"}
+
+	tests := []struct {
+		name      string
+		query     string
+		wantFirst string
+		wantCount int
+	}{
+		{name: "path", query: "{id}", wantFirst: "GET /users/{id}", wantCount: 1},
+		{name: "method and path", query: "post us", wantFirst: "POST /users", wantCount: 1},
+		{name: "operationId", query: "createuser", wantFirst: "POST /users", wantCount: 1},
+		{name: "summary", query: "userby", wantFirst: "GET /users/{id}", wantCount: 1},
+		{name: "tag", query: "admin", wantFirst: "POST /users", wantCount: 1},
+		{name: "no match", query: "zzz", wantCount: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := typeSearch(NewModel(spec), tt.query)
+
+			if !m.searching {
+				t.Fatal("search mode should stay active while typing")
+			}
+
+			if len(m.endpointsList) != tt.wantCount {
+				t.Fatalf("filtered endpoints = %d, want %d", len(m.endpointsList), tt.wantCount)
+			}
+
+			if tt.wantCount > 0 {
+				if got := m.getCurrentEndpoint().String(); got != tt.wantFirst {
+					t.Errorf("selected endpoint = %q, want %q", got, tt.wantFirst)
+				}
+			} else if !strings.Contains(m.renderEndpoints(), "No endpoints match") {
+				t.Error("renderEndpoints() should say nothing matches")
+			}
+		})
+	}
+}
+
+func TestEndpointsSearchKeys(t *testing.T) {
+	m := typeSearch(NewModel(createTestSpec()), "users")
+
+	if len(m.endpointsList) != 3 {
+		t.Fatalf("filtered endpoints = %d, want 3", len(m.endpointsList))
+	}
+
+	// q and ? are typed into the query rather than quitting or opening help
+	updated, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	m = updated.(Model)
+	if m.searchInput.Value() != "usersq" {
+		t.Fatalf("q should be typed into the search, got %q", m.searchInput.Value())
+	}
+
+	updated, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyBackspace})
+	m = updated.(Model)
+
+	updated, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	if m.selectedEndpoint != 1 {
+		t.Errorf("down while searching: selectedEndpoint = %d, want 1", m.selectedEndpoint)
+	}
+
+	updated, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.searching || m.searchInput.Value() != "users" {
+		t.Fatalf("enter should leave search mode and keep the filter, got searching=%v query=%q", m.searching, m.searchInput.Value())
+	}
+
+	selected := m.getCurrentEndpoint().String()
+	updated, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.currentView != viewOperationDetails || m.getCurrentEndpoint().String() != selected {
+		t.Errorf("enter on the filtered list should open %q", selected)
+	}
+
+	m.currentView = viewEndpoints
+	updated, cmd := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if cmd != nil || m.searchInput.Value() != "" || len(m.endpointsList) != 3 {
+		t.Errorf("esc should clear the filter instead of quitting")
+	}
+}
+
+func TestRenderEndpointsHighlightsMatches(t *testing.T) {
+	m := typeSearch(NewModel(createTestSpec()), "id")
+
+	rendered := m.renderEndpoints()
+	if !strings.Contains(rendered, "1/3") {
+		t.Error("renderEndpoints() should show the match count")
+	}
+
+	line := renderEndpointLine(m.endpointsList[0], &m.searchMatches[0])
+	if !strings.Contains(line, styles.MatchStyle.Render("i")) {
+		t.Errorf("renderEndpointLine() should highlight matched characters: %q", line)
+	}
+}
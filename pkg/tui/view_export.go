@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ksysoev/tapi/internal/styles"
+	"github.com/ksysoev/tapi/pkg/request"
+	"github.com/ksysoev/tapi/pkg/snippet"
+)
+
+// clipboardOutput is where the OSC 52 sequence is written, the terminal
+// puts the text on the system clipboard, also over SSH.
+var clipboardOutput io.Writer = os.Stderr
+
+type clipboardMsg struct {
+	err error
+}
+
+func (msg clipboardMsg) status() string {
+	if msg.err != nil {
+		return "✗ failed to copy to clipboard: " + msg.err.Error()
+	}
+
+	return "✓ Copied to clipboard"
+}
+
+// copyToClipboard copies text to the clipboard through the terminal.
+func copyToClipboard(text string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := osc52.New(text).WriteTo(clipboardOutput)
+		return clipboardMsg{err: err}
+	}
+}
+
+// openExport shows req rendered as a snippet, starting with the last used
+// format. Credentials are shown as placeholders, so secrets don't end up
+// in copied snippets and no token has to be fetched to render them.
+func (m *Model) openExport(req request.Request) {
+	m.exportReturnView = m.currentView
+	m.currentView = viewExport
+	req.Credentials = request.Placeholders(req.Credentials)
+	m.exportRequest = req
+	m.exportStatus = ""
+
+	if m.exportViewport.Width == 0 {
+		m.exportViewport = viewport.New(m.viewport.Width, m.viewport.Height-2)
+	}
+	m.exportViewport.Style = styles.PanelStyle
+
+	m.renderExportSnippet()
+}
+
+func (m *Model) renderExportSnippet() {
+	text, err := m.exportSnippet()
+	if err != nil {
+		text = styles.ErrorStyle.Render(err.Error())
+	}
+
+	m.exportViewport.SetContent(text)
+	m.exportViewport.GotoTop()
+}
+
+func (m Model) exportSnippet() (string, error) {
+	return snippet.Render(snippet.Formats[m.exportFormat], m.exportRequest)
+}
+
+func (m Model) handleExportKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "tab", "l", "right":
+		m.exportFormat = (m.exportFormat + 1) % len(snippet.Formats)
+		m.exportStatus = ""
+		m.renderExportSnippet()
+	case "shift+tab", "left":
+		m.exportFormat = (m.exportFormat + len(snippet.Formats) - 1) % len(snippet.Formats)
+		m.exportStatus = ""
+		m.renderExportSnippet()
+	case "j", "down":
+		m.exportViewport.LineDown(1)
+	case "k", "up":
+		m.exportViewport.LineUp(1)
+	case "y", "c":
+		text, err := m.exportSnippet()
+		if err != nil {
+			m.exportStatus = "✗ " + err.Error()
+			return m, nil
+		}
+		return m, copyToClipboard(text)
+	case "h":
+		m.currentView = m.exportReturnView
+	}
+
+	return m, nil
+}
+
+func (m Model) renderExport() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render("Export"))
+	b.WriteString(styles.SubtitleStyle.Render(m.exportRequest.Method + " " + m.exportRequest.URL()))
+	b.WriteString("\n")
+
+	tabs := make([]string, 0, len(snippet.Formats))
+	for i, format := range snippet.Formats {
+		if i == m.exportFormat {
+			tabs = append(tabs, styles.SelectedItemStyle.Render(format))
+		} else {
+			tabs = append(tabs, styles.ItemStyle.Render(format))
+		}
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+	b.WriteString("\n")
+
+	b.WriteString(m.exportViewport.View())
+	b.WriteString("\n")
+
+	if m.exportStatus != "" {
+		if strings.HasPrefix(m.exportStatus, "✗") {
+			b.WriteString(styles.ErrorStyle.Render(m.exportStatus))
+		} else {
+			b.WriteString(styles.SuccessStyle.Render(m.exportStatus))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+func TestExportFromRequestBuilder(t *testing.T) {
+	m := NewModel(createBodyTestSpec("https://api.example.com"))
+	m.height = 40
+	m.width = 100
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEnter}, runes("e"))
+	m.bodyEditor.SetValue(`{"name":"rex"}`)
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyCtrlX})
+	if m.currentView != viewExport {
+		t.Fatalf("ctrl+x should open the export view, got view %v", m.currentView)
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"curl", `curl -X POST 'https://api.example.com/pets'`},
+		{"httpie", `http --raw '{"name":"rex"}' POST 'https://api.example.com/pets'`},
+		{"go", `http.NewRequest("POST", "https://api.example.com/pets", body)`},
+		{"python", `import requests`},
+		{"fetch", `await fetch("https://api.example.com/pets", {`},
+	}
+
+	for i, tt := range tests {
+		if i > 0 {
+			m = pressKeys(m, tea.KeyMsg{Type: tea.KeyTab})
+		}
+
+		text, err := m.exportSnippet()
+		if err != nil {
+			t.Fatalf("exportSnippet() error = %v", err)
+		}
+		if !strings.Contains(text, tt.want) {
+			t.Errorf("%s snippet missing %q:\n%s", tt.format, tt.want, text)
+		}
+	}
+
+	// tab wraps around to the first format, shift+tab goes back
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyShiftTab})
+	if m.exportFormat != len(tests)-1 {
+		t.Errorf("expected the last format, got %d", m.exportFormat)
+	}
+
+	m = pressKeys(m, runes("h"))
+	if m.currentView != viewRequestBuilder {
+		t.Errorf("h should return to the request builder, got view %v", m.currentView)
+	}
+}
+
+func TestExportFromResponse(t *testing.T) {
+	m := NewModel(createBodyTestSpec("https://api.example.com"))
+	m.height = 40
+	m.width = 100
+
+	updated, _ := m.Update(request.ResponseMsg{
+		StatusCode: 200,
+		Status:     "200 OK",
+		Request:    request.Request{BaseURL: "https://api.example.com", Path: "/pets", Method: "GET"},
+	})
+	m = updated.(Model)
+	response := m.viewport.View()
+
+	m = pressKeys(m, runes("x"))
+	if m.currentView != viewExport {
+		t.Fatalf("x should open the export view, got view %v", m.currentView)
+	}
+	if !strings.Contains(m.renderExport(), "curl 'https://api.example.com/pets'") {
+		t.Errorf("renderExport() should show the sent request:\n%s", m.renderExport())
+	}
+
+	m = pressKeys(m, runes("h"))
+	if m.currentView != viewResponse || m.viewport.View() != response {
+		t.Errorf("h should return to the unchanged response, got view %v", m.currentView)
+	}
+}
+
+func TestExportCopyToClipboard(t *testing.T) {
+	var out bytes.Buffer
+	orig := clipboardOutput
+	clipboardOutput = &out
+	t.Cleanup(func() { clipboardOutput = orig })
+
+	m := NewModel(createBodyTestSpec("https://api.example.com"))
+	m.height = 40
+	m.width = 100
+	m.openExport(request.Request{BaseURL: "https://api.example.com", Path: "/pets", Method: "GET"})
+
+	updated, cmd := m.handleKeyPress(runes("y"))
+	if cmd == nil {
+		t.Fatal("y should return a command copying the snippet")
+	}
+
+	updated, _ = updated.(Model).Update(cmd())
+	m = updated.(Model)
+
+	if !strings.HasPrefix(out.String(), "\x1b]52;c;") {
+		t.Errorf("expected an OSC 52 sequence, got %q", out.String())
+	}
+	if !strings.Contains(m.renderExport(), "Copied to clipboard") {
+		t.Errorf("renderExport() should confirm the copy, status %q", m.exportStatus)
+	}
+}
+
+func TestExportHidesCredentials(t *testing.T) {
+	tokenRequests := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+	}))
+	defer tokenServer.Close()
+
+	spec := createBodyTestSpec("https://api.example.com")
+	spec.SecuritySchemes = map[string]openapi.SecurityScheme{
+		"oauth": {Name: "oauth", Type: "oauth2", Flows: &openapi.OAuthFlows{
+			ClientCredentials: &openapi.OAuthFlow{TokenURL: tokenServer.URL},
+		}},
+		"key": {Name: "key", Type: "apiKey", In: "header", ParamName: "X-Key"},
+	}
+	spec.Paths[0].Operations[0].Security = []openapi.SecurityRequirement{{"oauth": nil, "key": nil}}
+
+	m := NewModel(spec)
+	m.height = 40
+	m.width = 100
+	m.authValues = map[string]map[string]string{
+		"oauth": {"client_id": "app", "client_secret": "s3cret"},
+		"key":   {"value": "live-key"},
+	}
+	m.buildCredentials()
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEnter}, runes("e"), tea.KeyMsg{Type: tea.KeyCtrlX})
+
+	text, err := m.exportSnippet()
+	if err != nil {
+		t.Fatalf("exportSnippet() error = %v", err)
+	}
+
+	if tokenRequests != 0 {
+		t.Errorf("exporting made %d token requests, want none", tokenRequests)
+	}
+
+	if strings.Contains(text, "live-key") || !strings.Contains(text, "X-Key: <api-key>") || !strings.Contains(text, "Authorization: Bearer <token>") {
+		t.Errorf("snippet should show placeholders for credentials:\n%s", text)
+	}
+}
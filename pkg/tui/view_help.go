@@ -15,11 +15,23 @@ Navigation:
   G             Go to bottom
   d             Scroll half page down
   u             Scroll half page up
+  /             Search endpoints
+  t             Group endpoints: flat, by tag, by path prefix
 
 Actions:
   Enter         Select / Confirm
   e             Execute API request
+  a             Authorize (enter credentials)
+  s             Select server
+  E             Switch environment
+  H             Request history (r to replay)
+  C             Saved requests collection
+  i             Import a curl command into the request builder
   Ctrl+S        Send request
+  Ctrl+G        Send anyway when the request doesn't match the spec
+  Ctrl+T        Switch request body content type
+  Ctrl+O        Save request to the collection
+  Ctrl+X, x     Export request as curl, HTTPie or code (y to copy)
   Tab           Next input field
   Shift+Tab     Previous input field
 
@@ -0,0 +1,271 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/internal/styles"
+	"github.com/ksysoev/tapi/pkg/history"
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+// WithHistory records every response in store and enables the history view.
+func WithHistory(store *history.Store) Option {
+	return func(m *Model) {
+		m.historyStore = store
+	}
+}
+
+// recordHistory stores resp along with the inputs it was sent with. Inputs
+// are only taken from the request builder when it still shows the operation
+// the request was sent for.
+func (m *Model) recordHistory(resp request.ResponseMsg) {
+	if m.historyStore == nil {
+		return
+	}
+
+	entry := history.NewEntry(resp)
+	entry.Spec = m.spec.Title
+
+	op, path := m.getCurrentOperation(), m.getCurrentPath()
+	if op != nil && path != nil && op.Method == resp.Request.Method && path.Path == resp.Request.Path {
+		entry.OperationID = op.OperationID
+
+		for i, input := range m.inputs {
+			if i < len(op.Parameters) {
+				param := op.Parameters[i]
+				entry.Inputs = append(entry.Inputs, history.Input{Name: param.Name, In: param.In, Value: input.Value()})
+			}
+		}
+
+		if m.hasBody {
+			entry.InputBody = m.bodyEditor.Value()
+		}
+	}
+
+	entry.Redact(m.spec.SecuritySchemes)
+
+	m.historyErr = m.historyStore.Add(entry)
+}
+
+func (m Model) handleHistoryKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	entries := m.visibleHistory()
+
+	switch msg.String() {
+	case "j", "down":
+		if m.historyCursor < len(entries)-1 {
+			m.historyCursor++
+		}
+	case "k", "up":
+		if m.historyCursor > 0 {
+			m.historyCursor--
+		}
+	case "g":
+		m.historyCursor = 0
+	case "G":
+		if len(entries) > 0 {
+			m.historyCursor = len(entries) - 1
+		}
+	case "o":
+		if m.historyOperation != "" {
+			m.historyOperation = ""
+		} else if op, path := m.getCurrentOperation(), m.getCurrentPath(); op != nil && path != nil {
+			m.historyOperation = op.Method + " " + path.Path
+		}
+		m.historyCursor = 0
+	case "enter", "l", "right":
+		if m.historyCursor < len(entries) {
+			m.openHistoryResponse(entries[m.historyCursor])
+		}
+	case "r":
+		if m.historyCursor < len(entries) {
+			return m, m.replay(entries[m.historyCursor])
+		}
+	case "h", "left":
+		m.currentView = m.historyReturnView
+	}
+
+	return m, nil
+}
+
+// openHistory loads the history of the current spec. Opened from an
+// operation it starts filtered to that operation.
+func (m *Model) openHistory() tea.Cmd {
+	if m.currentView == viewResponse && m.responseFromHistory {
+		m.currentView = viewHistory
+		return nil
+	}
+
+	m.historyReturnView = m.currentView
+	if m.currentView == viewResponse {
+		// Going back from the history shouldn't land on the response view,
+		// which may since show a past response.
+		m.historyReturnView = viewRequestBuilder
+	}
+	m.currentView = viewHistory
+	m.historyCursor = 0
+	m.historyOperation = ""
+
+	if m.historyReturnView != viewEndpoints {
+		if op, path := m.getCurrentOperation(), m.getCurrentPath(); op != nil && path != nil {
+			m.historyOperation = op.Method + " " + path.Path
+		}
+	}
+
+	m.historyEntries = nil
+	if m.historyStore == nil {
+		return nil
+	}
+
+	entries, err := m.historyStore.List()
+	m.historyErr = err
+
+	for _, e := range entries {
+		if e.Spec == m.spec.Title {
+			m.historyEntries = append(m.historyEntries, e)
+		}
+	}
+
+	return nil
+}
+
+func (m Model) visibleHistory() []history.Entry {
+	if m.historyOperation == "" {
+		return m.historyEntries
+	}
+
+	entries := make([]history.Entry, 0)
+	for _, e := range m.historyEntries {
+		if e.Operation == m.historyOperation {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+func (m *Model) openHistoryResponse(e history.Entry) {
+	resp := e.Response()
+	if !e.Truncated {
+		// Only complete bodies can be checked against the spec.
+		resp.Request.Method = e.Method
+		_, resp.Request.Path, _ = strings.Cut(e.Operation, " ")
+	}
+
+	content := m.formatResponse(resp)
+	if e.Truncated {
+		content = styles.HelpStyle.UnsetPadding().Render(
+			fmt.Sprintf("Bodies truncated to %d KiB in history", history.MaxBodySize>>10)) + "\n\n" + content
+	}
+
+	m.lastResponse = e.ResponseBody
+	// Credentials aren't kept in the history, so an export of a past request
+	// leaves them out.
+	m.lastRequest = &request.Request{
+		BaseURL:     e.URL,
+		Method:      e.Method,
+		Headers:     e.Headers,
+		Body:        e.Body,
+		ContentType: e.ContentType,
+	}
+	m.responseFromHistory = true
+	m.currentView = viewResponse
+	m.viewport.SetContent(content)
+	m.viewport.GotoTop()
+}
+
+// replay opens the request builder for the entry's operation with the
+// inputs it was sent with.
+func (m *Model) replay(e history.Entry) tea.Cmd {
+	_, op, err := m.spec.FindOperation(e.Operation)
+	if err != nil {
+		m.historyErr = fmt.Errorf("operation %s is no longer in the spec", e.Operation)
+		return nil
+	}
+
+	body := e.InputBody
+	if body == "" {
+		body = e.Body
+	}
+
+	values := func(param openapi.Parameter) (string, bool) {
+		return e.Input(param.Name, param.In)
+	}
+
+	m.historyErr = m.openRequestBuilder(op, values, body, e.ContentType, nil)
+
+	return nil
+}
+
+func (m Model) renderHistory() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render("History"))
+	if m.historyOperation != "" {
+		b.WriteString(styles.SubtitleStyle.Render(m.historyOperation))
+	} else {
+		b.WriteString(styles.SubtitleStyle.Render("all operations"))
+	}
+	b.WriteString("\n\n")
+
+	if m.historyErr != nil {
+		b.WriteString(styles.ErrorStyle.Render(m.historyErr.Error()))
+		b.WriteString("\n\n")
+	}
+
+	entries := m.visibleHistory()
+	if len(entries) == 0 {
+		b.WriteString(styles.HelpStyle.Render("No requests yet"))
+		b.WriteString("\n")
+	}
+
+	start := 0
+	end := len(entries)
+
+	maxVisible := m.height - 15
+	if end-start > maxVisible {
+		if m.historyCursor > maxVisible/2 {
+			start = m.historyCursor - maxVisible/2
+		}
+		if end-start > maxVisible {
+			end = start + maxVisible
+		}
+	}
+
+	for i := start; i < end && i < len(entries); i++ {
+		line := renderHistoryLine(entries[i])
+
+		if i == m.historyCursor {
+			b.WriteString(styles.SelectedItemStyle.Render("▶ " + line))
+		} else {
+			b.WriteString(styles.ItemStyle.Render(line))
+		}
+		b.WriteString("\n")
+	}
+
+	// Help to fix issue that content is not possible to scroll down fully
+	b.WriteString("\n\n\n\n")
+
+	return b.String()
+}
+
+func renderHistoryLine(e history.Entry) string {
+	status := styles.SuccessStyle.Render(fmt.Sprintf("%d", e.StatusCode))
+	switch {
+	case e.Error != "":
+		status = styles.ErrorStyle.Render("ERR")
+	case e.StatusCode >= 400:
+		status = styles.ErrorStyle.Render(fmt.Sprintf("%d", e.StatusCode))
+	}
+
+	return fmt.Sprintf("%s %s %s %s %s",
+		styles.HelpStyle.UnsetPadding().Render(e.Time.Local().Format("2006-01-02 15:04:05")),
+		styles.MethodStyle(e.Method).Render(e.Method),
+		status,
+		e.URL,
+		styles.HelpStyle.UnsetPadding().Render(e.Duration.Round(time.Millisecond).String()),
+	)
+}
@@ -0,0 +1,140 @@
+package tui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/pkg/history"
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+func createHistoryTestModel(t *testing.T) (Model, *history.Store) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"owner":"` + strings.TrimPrefix(r.URL.Path, "/pets/") + `"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	spec := createBodyTestSpec(server.URL)
+	spec.Title = "History API"
+	spec.Paths[0].Path = "/pets/{owner}"
+	spec.Paths[0].Operations[0].Parameters = []openapi.Parameter{
+		{Name: "owner", In: "path", Required: true, Schema: &openapi.Schema{Type: "string"}},
+	}
+	spec.Paths = append(spec.Paths, openapi.Path{Path: "/store", Operations: []openapi.Operation{{Method: "GET"}}})
+
+	store := history.NewStore(filepath.Join(t.TempDir(), "history.jsonl"))
+	m := NewModel(spec, WithHistory(store))
+	m.height = 40
+
+	return m, store
+}
+
+func sendFromBuilder(t *testing.T, m Model, owner, body string) Model {
+	t.Helper()
+
+	m.currentView = viewRequestBuilder
+	m.setupRequestBuilder()
+	m.inputs[0].SetValue(owner)
+	m.bodyEditor.SetValue(body)
+
+	updated, _ := m.Update(m.sendRequest()())
+
+	return updated.(Model)
+}
+
+func TestRecordHistory(t *testing.T) {
+	m, store := createHistoryTestModel(t)
+
+	m = sendFromBuilder(t, m, "alice", `{"name":"a"}`)
+
+	entries, err := store.List()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("List() = %v, %v", entries, err)
+	}
+
+	e := entries[0]
+	if e.Spec != "History API" || e.Operation != "POST /pets/{owner}" || !strings.HasSuffix(e.URL, "/pets/alice") {
+		t.Errorf("entry = %+v", e)
+	}
+
+	if value, _ := e.Input("owner", "path"); value != "alice" || e.InputBody != `{"name":"a"}` {
+		t.Errorf("inputs = %+v, body = %q", e.Inputs, e.InputBody)
+	}
+
+	if e.StatusCode != http.StatusOK || e.ResponseBody != `{"owner":"alice"}` || e.Duration <= 0 {
+		t.Errorf("response = %d %q in %v", e.StatusCode, e.ResponseBody, e.Duration)
+	}
+
+	if m.historyErr != nil {
+		t.Errorf("historyErr = %v", m.historyErr)
+	}
+}
+
+func TestHistoryViewReopenAndReplay(t *testing.T) {
+	m, _ := createHistoryTestModel(t)
+
+	m = sendFromBuilder(t, m, "alice", `{"name":"a"}`)
+	m = sendFromBuilder(t, m, "bob", `{"name":"b"}`)
+
+	m.currentView = viewEndpoints
+	m = pressKeys(m, runes("H"))
+	if m.currentView != viewHistory || len(m.visibleHistory()) != 2 {
+		t.Fatalf("H should open the history with both requests, got view %v and %d entries", m.currentView, len(m.visibleHistory()))
+	}
+
+	if view := m.renderHistory(); !strings.Contains(view, "/pets/bob") || !strings.Contains(view, "all operations") {
+		t.Errorf("renderHistory() = %q", view)
+	}
+
+	// filter to the selected operation, then to all again
+	m = pressKeys(m, runes("o"))
+	if m.historyOperation != "POST /pets/{owner}" || len(m.visibleHistory()) != 2 {
+		t.Errorf("o should filter by the current operation, got %q", m.historyOperation)
+	}
+	m.selectedEndpoint = 1
+	m = pressKeys(m, runes("o"), runes("o"))
+	if len(m.visibleHistory()) != 0 {
+		t.Errorf("GET /store has no history, got %d entries", len(m.visibleHistory()))
+	}
+	m = pressKeys(m, runes("o"))
+
+	// reopen the older response and go back to the list
+	m = pressKeys(m, runes("j"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.currentView != viewResponse || m.lastResponse != `{"owner":"alice"}` {
+		t.Fatalf("enter should reopen the response, got view %v and %q", m.currentView, m.lastResponse)
+	}
+	m = pressKeys(m, runes("h"))
+	if m.currentView != viewHistory {
+		t.Errorf("h should return to the history, got view %v", m.currentView)
+	}
+
+	m = pressKeys(m, runes("r"))
+	if m.currentView != viewRequestBuilder {
+		t.Fatalf("r should open the request builder, got view %v", m.currentView)
+	}
+
+	if op := m.getCurrentOperation(); op == nil || op.Method != "POST" {
+		t.Fatalf("replay should select the recorded operation, got %+v", op)
+	}
+
+	if m.inputs[0].Value() != "alice" || m.bodyEditor.Value() != `{"name":"a"}` {
+		t.Errorf("replay inputs = %q, body = %q", m.inputs[0].Value(), m.bodyEditor.Value())
+	}
+}
+
+func TestHistoryWithoutStore(t *testing.T) {
+	m := NewModel(createTestSpec())
+	m.height = 40
+
+	m = pressKeys(m, runes("H"))
+	if m.currentView != viewHistory || !strings.Contains(m.renderHistory(), "No requests yet") {
+		t.Error("history view should open empty without a store")
+	}
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/internal/styles"
+	"github.com/ksysoev/tapi/pkg/curl"
+)
+
+// openImportCurl shows an editor to paste a curl command into.
+func (m *Model) openImportCurl() tea.Cmd {
+	ta := textarea.New()
+	ta.Placeholder = "curl -X POST 'https://api.example.com/pets' -H 'Content-Type: application/json' -d '{...}'"
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.SetWidth(max(m.width-8, 40))
+	ta.SetHeight(10)
+
+	m.importInput = ta
+	m.importErr = nil
+	m.currentView = viewImportCurl
+
+	return m.importInput.Focus()
+}
+
+func (m Model) handleImportCurlKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+s" {
+		m.importErr = m.importCurl(m.importInput.Value())
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.importInput, cmd = m.importInput.Update(msg)
+
+	return m, cmd
+}
+
+// importCurl opens the request builder for the operation the curl command
+// calls, filled with its parameters, headers and body. The command's host
+// isn't used, requests still go to the selected server.
+func (m *Model) importCurl(cmdline string) error {
+	r, err := curl.Import(m.spec, cmdline)
+	if err != nil {
+		return err
+	}
+
+	if err := m.openRequestBuilder(r.Operation, r.Value, r.Body, r.ContentType, r.Headers); err != nil {
+		return err
+	}
+
+	m.builderStatus = "✓ Imported from curl"
+	if len(r.Ignored) > 0 {
+		m.builderStatus += ", ignored " + strings.Join(r.Ignored, ", ")
+	}
+
+	return nil
+}
+
+func (m Model) renderImportCurl() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render("Import curl command"))
+	b.WriteString("\n\n")
+	b.WriteString(styles.FocusedInputStyle.Render(m.importInput.View()))
+	b.WriteString("\n")
+
+	if m.importErr != nil {
+		b.WriteString(styles.ErrorStyle.Render("✗ " + m.importErr.Error()))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+func createImportTestModel() Model {
+	spec := createBodyTestSpec("https://api.example.com")
+	spec.Paths[0].Operations[0].Parameters = []openapi.Parameter{
+		{Name: "X-Tenant", In: "header", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "dryRun", In: "query", Schema: &openapi.Schema{Type: "boolean"}},
+	}
+	spec.Paths = append(spec.Paths, openapi.Path{Path: "/pets/{petId}", Operations: []openapi.Operation{{
+		Method:     "GET",
+		Parameters: []openapi.Parameter{{Name: "petId", In: "path", Required: true, Schema: &openapi.Schema{Type: "integer"}}},
+	}}})
+
+	m := NewModel(spec)
+	m.height = 40
+	m.width = 100
+
+	return m
+}
+
+func TestImportCurl(t *testing.T) {
+	m := createImportTestModel()
+
+	m = pressKeys(m, runes("i"))
+	if m.currentView != viewImportCurl {
+		t.Fatalf("i should open the curl import, got view %v", m.currentView)
+	}
+
+	cmdline := "curl -X POST 'https://prod.example.com/v1/pets?dryRun=true&trace=1' \\\n" +
+		"  -H 'X-Tenant: acme' -H 'Authorization: Bearer t' \\\n" +
+		"  -H 'Content-Type: application/x-www-form-urlencoded' -d 'name=rex'"
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(cmdline), Paste: true}, tea.KeyMsg{Type: tea.KeyCtrlS})
+
+	if m.currentView != viewRequestBuilder {
+		t.Fatalf("ctrl+s should open the request builder, got view %v, error %v", m.currentView, m.importErr)
+	}
+
+	if m.inputs[0].Value() != "acme" || m.inputs[1].Value() != "true" {
+		t.Errorf("inputs = %q, %q, want acme, true", m.inputs[0].Value(), m.inputs[1].Value())
+	}
+
+	if m.bodyEditor.Value() != "name=rex" || m.selectedContentType() != "application/x-www-form-urlencoded" {
+		t.Errorf("body = %q as %s", m.bodyEditor.Value(), m.selectedContentType())
+	}
+
+	if m.requestHeaders["Authorization"] != "Bearer t" {
+		t.Errorf("requestHeaders = %v", m.requestHeaders)
+	}
+
+	if !strings.Contains(m.renderRequestBuilder(), "ignored query parameter trace") {
+		t.Errorf("renderRequestBuilder() should list what wasn't imported, status %q", m.builderStatus)
+	}
+
+	// The request goes to the selected server, not the host in the command.
+	req, ok := m.buildRequest()
+	if !ok || req.URL() != "https://api.example.com/pets?dryRun=true" {
+		t.Errorf("buildRequest() URL = %q", req.URL())
+	}
+}
+
+func TestImportCurlErrors(t *testing.T) {
+	m := createImportTestModel()
+
+	// Keys that act globally elsewhere are typed into the editor.
+	m = pressKeys(m, runes("i"), runes("curl -X DELETE https://api.example.com/pets?q"), tea.KeyMsg{Type: tea.KeyCtrlS})
+	if m.currentView != viewImportCurl || m.showHelp {
+		t.Fatalf("expected to stay in the curl import, got view %v", m.currentView)
+	}
+
+	if !strings.Contains(m.renderImportCurl(), "path /pets has no DELETE operation") {
+		t.Errorf("renderImportCurl() should show the error, got %v", m.importErr)
+	}
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.currentView != viewEndpoints {
+		t.Errorf("esc should cancel the import, got view %v", m.currentView)
+	}
+}
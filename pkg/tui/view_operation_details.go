@@ -2,11 +2,13 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ksysoev/tapi/internal/styles"
+	"github.com/ksysoev/tapi/pkg/openapi"
 )
 
 func (m Model) handleOperationDetailsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -20,8 +22,22 @@ func (m Model) handleOperationDetailsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "u":
 		m.viewport.HalfViewUp()
 	case "e", "enter":
+		// Webhooks are requests the API sends, there's nothing to execute.
+		if e := m.getCurrentEndpoint(); e == nil || e.webhook {
+			return m, nil
+		}
 		m.currentView = viewRequestBuilder
 		m.setupRequestBuilder()
+	case "a":
+		return m, m.openAuthorize()
+	case "s":
+		return m, m.openServers()
+	case "E":
+		m.cycleEnvironment()
+	case "H":
+		return m, m.openHistory()
+	case "C":
+		return m, m.openCollection()
 	case "h", "left":
 		m.currentView = viewEndpoints
 	}
@@ -39,6 +55,11 @@ func (m Model) getOperationDetails() string {
 	b.WriteString(styles.TitleStyle.Render(fmt.Sprintf("%s %s", op.Method, m.getCurrentPath().Path)))
 	b.WriteString("\n\n")
 
+	if m.getCurrentEndpoint().webhook {
+		b.WriteString(styles.SubtitleStyle.Render("Webhook: sent by the API to subscribers"))
+		b.WriteString("\n\n")
+	}
+
 	if op.Summary != "" {
 		b.WriteString(styles.LabelStyle.Render("Summary: "))
 		b.WriteString(op.Summary)
@@ -60,7 +81,11 @@ func (m Model) getOperationDetails() string {
 			if param.Required {
 				required = lipgloss.NewStyle().Foreground(styles.Danger).Render(" *")
 			}
-			b.WriteString(fmt.Sprintf("  • %s (%s)%s - %s\n", param.Name, param.In, required, param.Description))
+			location := param.In
+			if param.Schema != nil {
+				location += ", " + schemaTypeLabel(param.Schema)
+			}
+			b.WriteString(fmt.Sprintf("  • %s (%s)%s - %s\n", param.Name, location, required, param.Description))
 		}
 		b.WriteString("\n")
 	}
@@ -68,8 +93,18 @@ func (m Model) getOperationDetails() string {
 	if op.RequestBody != nil {
 		b.WriteString(styles.LabelStyle.Render("Request Body:"))
 		b.WriteString("\n")
-		for contentType := range op.RequestBody.Content {
+		for contentType, mediaType := range op.RequestBody.Content {
 			b.WriteString(fmt.Sprintf("  • %s\n", contentType))
+			writeSchema(&b, mediaType.Schema, "    ", 0)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(op.Security) > 0 {
+		b.WriteString(styles.LabelStyle.Render("Security (any of):"))
+		b.WriteString("\n")
+		for _, req := range op.Security {
+			b.WriteString("  • " + m.describeSecurityRequirement(req) + "\n")
 		}
 		b.WriteString("\n")
 	}
@@ -88,3 +123,206 @@ func (m Model) getOperationDetails() string {
 
 	return b.String()
 }
+
+func (m Model) describeSecurityRequirement(req openapi.SecurityRequirement) string {
+	if len(req) == 0 {
+		return "none (optional)"
+	}
+
+	names := make([]string, 0, len(req))
+	for name := range req {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		part := name
+		if scopes := req[name]; len(scopes) > 0 {
+			part += " (" + strings.Join(scopes, ", ") + ")"
+		}
+		if _, ok := m.credentials[name]; ok {
+			part += styles.SuccessStyle.Render(" ✓")
+		}
+		parts = append(parts, part)
+	}
+
+	return strings.Join(parts, " + ")
+}
+
+const maxSchemaDepth = 6
+
+func schemaTypeLabel(schema *openapi.Schema) string {
+	var label string
+
+	switch {
+	case schema.Circular:
+		label = "↻ " + refName(schema.Ref)
+	case schema.Type == "array" && schema.Items != nil:
+		label = "array<" + schemaTypeLabel(schema.Items) + ">"
+	case len(schema.Types) > 1:
+		label = strings.Join(schema.Types, "|")
+	case schema.Type != "":
+		label = schema.Type
+	case len(schema.OneOf) > 0:
+		label = "oneOf"
+	case len(schema.AnyOf) > 0:
+		label = "anyOf"
+	case len(schema.AllOf) > 0:
+		label = "allOf"
+	case schema.Ref != "":
+		label = refName(schema.Ref)
+	default:
+		label = "any"
+	}
+
+	if schema.Format != "" {
+		label += "(" + schema.Format + ")"
+	}
+
+	if schema.Nullable {
+		label += "?"
+	}
+
+	return label
+}
+
+func schemaConstraints(schema *openapi.Schema) []string {
+	var notes []string
+
+	if len(schema.Enum) > 0 {
+		values := make([]string, 0, len(schema.Enum))
+		for _, v := range schema.Enum {
+			values = append(values, fmt.Sprint(v))
+		}
+		notes = append(notes, "enum: "+strings.Join(values, " | "))
+	}
+
+	if schema.Const != nil {
+		notes = append(notes, fmt.Sprintf("const: %v", schema.Const))
+	}
+
+	if schema.Default != nil {
+		notes = append(notes, fmt.Sprintf("default: %v", schema.Default))
+	}
+
+	if schema.Minimum != nil {
+		op := ">="
+		if schema.ExclusiveMinimum {
+			op = ">"
+		}
+		notes = append(notes, fmt.Sprintf("%s %v", op, *schema.Minimum))
+	}
+
+	if schema.Maximum != nil {
+		op := "<="
+		if schema.ExclusiveMaximum {
+			op = "<"
+		}
+		notes = append(notes, fmt.Sprintf("%s %v", op, *schema.Maximum))
+	}
+
+	if schema.MinLength > 0 || schema.MaxLength != nil {
+		notes = append(notes, "length "+rangeLabel(schema.MinLength, schema.MaxLength))
+	}
+
+	if schema.MinItems > 0 || schema.MaxItems != nil {
+		notes = append(notes, "items "+rangeLabel(schema.MinItems, schema.MaxItems))
+	}
+
+	if schema.Pattern != "" {
+		notes = append(notes, "pattern: "+schema.Pattern)
+	}
+
+	if schema.ReadOnly {
+		notes = append(notes, "read-only")
+	}
+
+	if schema.WriteOnly {
+		notes = append(notes, "write-only")
+	}
+
+	if schema.Deprecated {
+		notes = append(notes, "deprecated")
+	}
+
+	return notes
+}
+
+func rangeLabel(minimum uint64, maximum *uint64) string {
+	if maximum == nil {
+		return fmt.Sprintf("%d..", minimum)
+	}
+
+	return fmt.Sprintf("%d..%d", minimum, *maximum)
+}
+
+func refName(ref string) string {
+	if idx := strings.LastIndex(ref, "/"); idx != -1 {
+		return ref[idx+1:]
+	}
+
+	return ref
+}
+
+// writeSchema renders the schema as an indented property tree.
+func writeSchema(b *strings.Builder, schema *openapi.Schema, indent string, depth int) {
+	if schema == nil || schema.Circular || depth > maxSchemaDepth {
+		return
+	}
+
+	if schema.Type == "array" && schema.Items != nil {
+		writeSchema(b, schema.Items, indent, depth+1)
+		return
+	}
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	for _, name := range names {
+		prop := schema.Properties[name]
+		if prop == nil {
+			continue
+		}
+
+		marker := ""
+		if required[name] {
+			marker = lipgloss.NewStyle().Foreground(styles.Danger).Render(" *")
+		}
+
+		line := fmt.Sprintf("%s%s%s: %s", indent, name, marker, schemaTypeLabel(prop))
+		if notes := schemaConstraints(prop); len(notes) > 0 {
+			line += styles.HelpStyle.UnsetPadding().Render(" [" + strings.Join(notes, ", ") + "]")
+		}
+		b.WriteString(line + "\n")
+
+		writeSchema(b, prop, indent+"  ", depth+1)
+	}
+
+	for _, group := range []struct {
+		name    string
+		schemas []*openapi.Schema
+	}{
+		{"oneOf", schema.OneOf},
+		{"anyOf", schema.AnyOf},
+		{"allOf", schema.AllOf},
+	} {
+		for i, sub := range group.schemas {
+			b.WriteString(fmt.Sprintf("%s%s[%d]: %s\n", indent, group.name, i, schemaTypeLabel(sub)))
+			writeSchema(b, sub, indent+"  ", depth+1)
+		}
+	}
+
+	if ap := schema.AdditionalProperties; ap != nil && ap.Schema != nil {
+		b.WriteString(fmt.Sprintf("%s<key>: %s\n", indent, schemaTypeLabel(ap.Schema)))
+		writeSchema(b, ap.Schema, indent+"  ", depth+1)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/pkg/openapi"
 )
 
 func TestHandleOperationDetailsKeysNavigation(t *testing.T) {
@@ -161,3 +162,71 @@ func TestGetOperationDetailsWithResponses(t *testing.T) {
 		t.Error("getOperationDetails() missing 404 response")
 	}
 }
+
+func TestSchemaTypeLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   *openapi.Schema
+		expected string
+	}{
+		{"plain", &openapi.Schema{Type: "string"}, "string"},
+		{"format", &openapi.Schema{Type: "integer", Format: "int64"}, "integer(int64)"},
+		{"array", &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "string"}}, "array<string>"},
+		{"nullable", &openapi.Schema{Type: "string", Nullable: true}, "string?"},
+		{"multi type", &openapi.Schema{Type: "integer", Types: []string{"integer", "string"}}, "integer|string"},
+		{"oneOf", &openapi.Schema{OneOf: []*openapi.Schema{{Type: "string"}}}, "oneOf"},
+		{"circular", &openapi.Schema{Ref: "#/components/schemas/Node", Circular: true}, "↻ Node"},
+		{"untyped", &openapi.Schema{}, "any"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schemaTypeLabel(tt.schema); got != tt.expected {
+				t.Errorf("schemaTypeLabel() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteSchema(t *testing.T) {
+	maxLen := uint64(10)
+	schema := &openapi.Schema{
+		Type:     "object",
+		Required: []string{"name"},
+		Properties: map[string]*openapi.Schema{
+			"name":   {Type: "string", MaxLength: &maxLen},
+			"status": {Type: "string", Enum: []interface{}{"on", "off"}},
+			"parent": {Type: "object", Ref: "#/components/schemas/Node", Circular: true},
+		},
+	}
+
+	var b strings.Builder
+	writeSchema(&b, schema, "", 0)
+	out := b.String()
+
+	for _, want := range []string{"name", "length 0..10", "status", "enum: on | off", "↻ Node"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("writeSchema() missing %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestOperationDetailsWebhook(t *testing.T) {
+	spec := createTestSpec()
+	spec.Webhooks = []openapi.Path{
+		{Path: "userCreated", Operations: []openapi.Operation{{Method: "POST", Summary: "User created event"}}},
+	}
+
+	model := NewModel(spec)
+	model.currentView = viewOperationDetails
+	model.selectedEndpoint = 3
+
+	if details := model.getOperationDetails(); !strings.Contains(details, "Webhook") {
+		t.Errorf("getOperationDetails() should mark webhooks, got %q", details)
+	}
+
+	updatedModel, _ := model.handleOperationDetailsKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if m := updatedModel.(Model); m.currentView != viewOperationDetails {
+		t.Errorf("handleOperationDetailsKeys() should not open request builder for webhooks, got view %v", m.currentView)
+	}
+}
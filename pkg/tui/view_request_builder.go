@@ -1,29 +1,36 @@
 package tui
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ksysoev/tapi/internal/styles"
+	"github.com/ksysoev/tapi/pkg/openapi"
 	"github.com/ksysoev/tapi/pkg/request"
 )
 
 func (m Model) handleRequestBuilderKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	bodyFocused := m.bodyFocused()
+
 	switch msg.String() {
-	case "tab", "j", "down":
-		if len(m.inputs) > 0 {
-			m.focusedInput = (m.focusedInput + 1) % len(m.inputs)
-			return m, m.inputs[m.focusedInput].Focus()
+	case "tab":
+		return m, m.moveFocus(1)
+	case "shift+tab":
+		return m, m.moveFocus(-1)
+	case "j", "down":
+		if !bodyFocused {
+			return m, m.moveFocus(1)
 		}
-	case "shift+tab", "k", "up":
-		if len(m.inputs) > 0 {
-			m.focusedInput--
-			if m.focusedInput < 0 {
-				m.focusedInput = len(m.inputs) - 1
-			}
-			return m, m.inputs[m.focusedInput].Focus()
+	case "k", "up":
+		if !bodyFocused {
+			return m, m.moveFocus(-1)
 		}
 	case "enter":
 		if msg.Alt {
@@ -31,6 +38,34 @@ func (m Model) handleRequestBuilderKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case "ctrl+s":
 		return m, m.sendRequest()
+	case "ctrl+g":
+		if err := m.bodyError(); err != nil {
+			m.builderStatus = "✗ " + err.Error()
+			return m, nil
+		}
+		return m, m.sendRequestAnyway()
+	case "ctrl+o":
+		return m, m.startSaveRequest()
+	case "ctrl+x":
+		if err := m.bodyError(); err != nil {
+			m.builderStatus = "✗ " + err.Error()
+			return m, nil
+		}
+		if req, ok := m.buildRequest(); ok {
+			m.openExport(req)
+		}
+		return m, nil
+	case "ctrl+t":
+		if len(m.contentTypes) > 1 {
+			m.switchContentType()
+			return m, nil
+		}
+	}
+
+	if bodyFocused {
+		var cmd tea.Cmd
+		m.bodyEditor, cmd = m.bodyEditor.Update(msg)
+		return m, cmd
 	}
 
 	if len(m.inputs) > 0 {
@@ -42,6 +77,45 @@ func (m Model) handleRequestBuilderKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// fieldCount is the number of focusable fields: one per parameter plus the
+// body editor, which always comes last.
+func (m Model) fieldCount() int {
+	if m.hasBody {
+		return len(m.inputs) + 1
+	}
+	return len(m.inputs)
+}
+
+func (m Model) bodyFocused() bool {
+	return m.hasBody && m.focusedInput == len(m.inputs)
+}
+
+func (m *Model) moveFocus(delta int) tea.Cmd {
+	count := m.fieldCount()
+	if count == 0 {
+		return nil
+	}
+
+	m.focusedInput = (m.focusedInput + delta + count) % count
+
+	var cmd tea.Cmd
+	for i := range m.inputs {
+		if i == m.focusedInput {
+			cmd = m.inputs[i].Focus()
+		} else {
+			m.inputs[i].Blur()
+		}
+	}
+
+	if m.bodyFocused() {
+		cmd = m.bodyEditor.Focus()
+	} else {
+		m.bodyEditor.Blur()
+	}
+
+	return cmd
+}
+
 func (m *Model) setupRequestBuilder() {
 	op := m.getCurrentOperation()
 	if op == nil {
@@ -49,6 +123,15 @@ func (m *Model) setupRequestBuilder() {
 	}
 
 	m.inputs = make([]textinput.Model, 0)
+	m.focusedInput = 0
+	m.hasBody = false
+	m.contentTypes = nil
+	m.contentTypeIndex = 0
+	m.bodies = make(map[string]string)
+	m.requestHeaders = nil
+	m.savingRequest = false
+	m.builderStatus = ""
+	m.showViolations = false
 
 	for _, param := range op.Parameters {
 		ti := textinput.New()
@@ -59,19 +142,142 @@ func (m *Model) setupRequestBuilder() {
 		m.inputs = append(m.inputs, ti)
 	}
 
-	if op.RequestBody != nil && op.RequestBody.Required {
-		ti := textinput.New()
-		ti.Placeholder = "Request body (JSON)"
-		ti.CharLimit = 2048
-		ti.Width = 50
-		ti.Prompt = "Body: "
-		m.inputs = append(m.inputs, ti)
+	if op.RequestBody != nil {
+		m.hasBody = true
+		m.contentTypes = openapi.SortedContentTypes(op.RequestBody.Content)
+
+		ta := textarea.New()
+		ta.Placeholder = "Request body"
+		ta.CharLimit = 0
+		ta.MaxHeight = 1000
+		ta.SetWidth(70)
+		ta.SetHeight(12)
+		ta.ShowLineNumbers = true
+		m.bodyEditor = ta
+		m.bodyEditor.SetValue(m.exampleBody())
 	}
 
-	if len(m.inputs) > 0 {
-		m.inputs[0].Focus()
-		m.focusedInput = 0
+	m.moveFocus(0)
+}
+
+// openRequestBuilder selects op in the endpoints list and opens the request
+// builder filled with the given values, as when replaying a past request or
+// loading a saved one.
+func (m *Model) openRequestBuilder(op *openapi.Operation, values func(openapi.Parameter) (string, bool), body, contentType string, headers map[string]string) error {
+	m.clearSearch()
+	m.collapsed = nil
+	m.buildRows()
+
+	index := slices.IndexFunc(m.rows, func(row listRow) bool {
+		return row.endpoint != nil && row.endpoint.operation == op
+	})
+	if index < 0 {
+		return fmt.Errorf("operation %s is not in the endpoints list", op.Method)
+	}
+
+	m.selectedEndpoint = index
+	m.currentView = viewRequestBuilder
+	m.setupRequestBuilder()
+	m.requestHeaders = headers
+
+	for i, param := range op.Parameters {
+		if value, ok := values(param); ok {
+			m.inputs[i].SetValue(value)
+		}
+	}
+
+	if m.hasBody {
+		if i := slices.Index(m.contentTypes, contentType); i >= 0 {
+			m.contentTypeIndex = i
+		}
+		m.bodyEditor.SetValue(body)
+	}
+
+	return nil
+}
+
+// switchContentType selects the next media type of the body. What was typed
+// for a media type is kept and comes back when it's selected again, the
+// others start from an example.
+func (m *Model) switchContentType() {
+	m.bodies[m.selectedContentType()] = m.bodyEditor.Value()
+	m.contentTypeIndex = (m.contentTypeIndex + 1) % len(m.contentTypes)
+
+	body, ok := m.bodies[m.selectedContentType()]
+	if !ok {
+		body = m.exampleBody()
 	}
+	m.bodyEditor.SetValue(body)
+}
+
+func (m Model) selectedContentType() string {
+	if m.contentTypeIndex < len(m.contentTypes) {
+		return m.contentTypes[m.contentTypeIndex]
+	}
+	return ""
+}
+
+// exampleBody renders an example for the selected media type in a form that
+// matches it: indented JSON, a urlencoded form, or plain text.
+func (m Model) exampleBody() string {
+	op := m.getCurrentOperation()
+	if op == nil || op.RequestBody == nil {
+		return ""
+	}
+
+	contentType := m.selectedContentType()
+	mediaType := op.RequestBody.Content[contentType]
+
+	example := mediaType.Example
+	if example == nil {
+		example = openapi.GenerateExample(mediaType.Schema, openapi.ExampleRequest)
+	}
+
+	body, err := openapi.FormatExample(contentType, example)
+	if err != nil {
+		return ""
+	}
+
+	return body
+}
+
+// bodyError reports JSON syntax errors in the body editor with the line and
+// column they occur at. Bodies of other media types aren't checked.
+func (m Model) bodyError() error {
+	body := m.interpolate(m.bodyEditor.Value())
+	if !m.hasBody || strings.TrimSpace(body) == "" {
+		return nil
+	}
+
+	if contentType := m.selectedContentType(); contentType != "" && !openapi.IsJSON(contentType) {
+		return nil
+	}
+
+	var v interface{}
+	err := json.Unmarshal([]byte(body), &v)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		line, col := lineAndColumn(body, syntaxErr.Offset)
+		return fmt.Errorf("invalid JSON at line %d, column %d: %s", line, col, syntaxErr.Error())
+	}
+
+	return fmt.Errorf("invalid JSON: %w", err)
+}
+
+func lineAndColumn(s string, offset int64) (int, int) {
+	if offset > int64(len(s)) {
+		offset = int64(len(s))
+	}
+
+	before := s[:offset]
+	line := strings.Count(before, "\n") + 1
+	col := len(before) - strings.LastIndex(before, "\n")
+
+	return line, col
 }
 
 func (m Model) renderRequestBuilder() string {
@@ -87,19 +293,60 @@ func (m Model) renderRequestBuilder() string {
 	b.WriteString(styles.SubtitleStyle.Render(fmt.Sprintf("%s %s", op.Method, m.getCurrentPath().Path)))
 	b.WriteString("\n\n")
 
-	if len(m.inputs) == 0 {
+	violations := m.visibleViolations()
+
+	if m.fieldCount() == 0 {
 		b.WriteString(styles.SuccessStyle.Render("No parameters required"))
 		b.WriteString("\n\n")
 		b.WriteString(styles.HelpStyle.Render("Press Ctrl+S to send request"))
 	} else {
 		for i, input := range m.inputs {
+			var invalid string
+			if i < len(op.Parameters) {
+				invalid = renderFieldViolations(violations, op.Parameters[i].In, op.Parameters[i].Name)
+			}
+
+			style := styles.InputStyle
 			if i == m.focusedInput {
-				b.WriteString(styles.FocusedInputStyle.Render(input.View()))
-			} else {
-				b.WriteString(styles.InputStyle.Render(input.View()))
+				style = styles.FocusedInputStyle
+			}
+			if invalid != "" {
+				style = style.BorderForeground(styles.Danger)
 			}
-			b.WriteString("\n\n")
+
+			b.WriteString(style.Render(input.View()))
+			b.WriteString("\n")
+			b.WriteString(invalid)
+			b.WriteString("\n")
+		}
+	}
+
+	if len(m.requestHeaders) > 0 {
+		b.WriteString(styles.LabelStyle.Render("Headers"))
+		b.WriteString("\n")
+		for _, name := range slices.Sorted(maps.Keys(m.requestHeaders)) {
+			b.WriteString(fmt.Sprintf("  %s: %s\n", name, m.requestHeaders[name]))
 		}
+		b.WriteString("\n")
+	}
+
+	if m.hasBody {
+		b.WriteString(m.renderBodyEditor(op.RequestBody, violations))
+	}
+
+	if m.showViolations {
+		b.WriteString("\n")
+		b.WriteString(renderValidationStatus(violations))
+	}
+
+	if m.savingRequest {
+		b.WriteString("\n")
+		b.WriteString(styles.FocusedInputStyle.Render(m.saveInput.View()))
+		b.WriteString("\n")
+	} else if m.builderStatus != "" {
+		b.WriteString("\n")
+		b.WriteString(styles.SuccessStyle.Render(m.builderStatus))
+		b.WriteString("\n")
 	}
 
 	// Help to fix issue that content is not possible to scroll down fully
@@ -108,30 +355,101 @@ func (m Model) renderRequestBuilder() string {
 	return b.String()
 }
 
+func (m Model) renderBodyEditor(body *openapi.RequestBody, violations []openapi.Violation) string {
+	var b strings.Builder
+
+	label := "Body"
+	if body.Required {
+		label += " (required)"
+	}
+	b.WriteString(styles.LabelStyle.Render(label))
+
+	if contentType := m.selectedContentType(); contentType != "" {
+		b.WriteString(styles.SubtitleStyle.Render(contentType))
+		if len(m.contentTypes) > 1 {
+			b.WriteString(styles.HelpStyle.Render(fmt.Sprintf(" (%d/%d, ctrl+t to switch)", m.contentTypeIndex+1, len(m.contentTypes))))
+		}
+	}
+	b.WriteString("\n")
+
+	invalid := renderFieldViolations(violations, "body", "")
+
+	style := styles.InputStyle
+	if m.bodyFocused() {
+		style = styles.FocusedInputStyle
+	}
+	if invalid != "" {
+		style = style.BorderForeground(styles.Danger)
+	}
+
+	b.WriteString(style.Render(m.bodyEditor.View()))
+	b.WriteString("\n")
+
+	if err := m.bodyError(); err != nil {
+		b.WriteString(styles.ErrorStyle.Render("✗ " + err.Error()))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(invalid)
+
+	return b.String()
+}
+
+// sendRequest sends the request built from the form once it matches the
+// spec, otherwise the violations are shown in the form. It returns nil while
+// the body has a syntax error, which is shown under the editor instead.
 func (m Model) sendRequest() tea.Cmd {
+	req, ok := m.buildRequest()
+	if !ok {
+		return nil
+	}
+
+	if len(m.requestViolations(req)) > 0 {
+		return func() tea.Msg { return requestInvalidMsg{} }
+	}
+
+	return request.Send(req)
+}
+
+// buildRequest builds the request from the form, reporting false when there
+// is no operation or the body has a syntax error.
+func (m Model) buildRequest() (request.Request, bool) {
 	op := m.getCurrentOperation()
 	path := m.getCurrentPath()
 
 	if op == nil || path == nil {
-		return nil
+		return request.Request{}, false
+	}
+
+	if m.bodyError() != nil {
+		return request.Request{}, false
+	}
+
+	req := request.Request{
+		BaseURL:     m.baseURL(op),
+		Path:        path.Path,
+		Method:      op.Method,
+		Credentials: request.ResolveCredentials(op.Security, m.credentials),
 	}
 
-	params := make(map[string]string)
 	for i, input := range m.inputs {
 		if i < len(op.Parameters) {
-			params[op.Parameters[i].Name] = input.Value()
+			param := op.Parameters[i]
+			req.SetParam(param, m.interpolate(input.Value()))
 		}
 	}
 
-	var body string
-	if op.RequestBody != nil && len(m.inputs) > len(op.Parameters) {
-		body = m.inputs[len(m.inputs)-1].Value()
+	if m.hasBody {
+		req.Body = m.interpolate(m.bodyEditor.Value())
+		req.ContentType = m.selectedContentType()
 	}
 
-	server := ""
-	if len(m.spec.Servers) > 0 {
-		server = m.spec.Servers[0].URL
+	for name, value := range m.requestHeaders {
+		if req.Headers == nil {
+			req.Headers = make(map[string]string)
+		}
+		req.Headers[name] = m.interpolate(value)
 	}
 
-	return request.Send(server, path.Path, op.Method, params, body)
+	return req, true
 }
@@ -1,11 +1,14 @@
 package tui
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
 )
 
 func TestHandleRequestBuilderKeysNavigation(t *testing.T) {
@@ -97,7 +100,7 @@ func TestSetupRequestBuilder(t *testing.T) {
 		expectedInputs   int
 	}{
 		{"GET with one param", 0, 1},
-		{"POST with request body", 1, 1},
+		{"POST with request body", 1, 0},
 		{"GET with path param", 2, 1},
 	}
 
@@ -219,7 +222,7 @@ func TestSendRequestWithBody(t *testing.T) {
 	model.selectedEndpoint = 1
 	model.setupRequestBuilder()
 
-	model.inputs[0].SetValue(`{"name":"John"}`)
+	model.bodyEditor.SetValue(`{"name":"John"}`)
 
 	cmd := model.sendRequest()
 
@@ -253,3 +256,220 @@ func TestSendRequestNoServer(t *testing.T) {
 		t.Error("sendRequest() should return command even without server")
 	}
 }
+
+func TestSendRequestParameterLocations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/42" {
+			t.Errorf("path = %q, want /users/42", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("verbose"); got != "true" {
+			t.Errorf("query verbose = %q, want true", got)
+		}
+		if got := r.Header.Get("X-Request-Id"); got != "req-1" {
+			t.Errorf("X-Request-Id header = %q, want req-1", got)
+		}
+		if c, err := r.Cookie("session"); err != nil || c.Value != "abc" {
+			t.Errorf("session cookie = %v, %v", c, err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	spec := &openapi.Spec{
+		Servers: []openapi.Server{{URL: server.URL}},
+		Paths: []openapi.Path{{
+			Path: "/users/{id}",
+			Operations: []openapi.Operation{{
+				Method: "GET",
+				Parameters: []openapi.Parameter{
+					{Name: "id", In: "path", Required: true},
+					{Name: "verbose", In: "query"},
+					{Name: "X-Request-Id", In: "header"},
+					{Name: "session", In: "cookie"},
+				},
+			}},
+		}},
+	}
+
+	model := NewModel(spec)
+	model.setupRequestBuilder()
+	for i, value := range []string{"42", "true", "req-1", "abc"} {
+		model.inputs[i].SetValue(value)
+	}
+
+	msg := model.sendRequest()()
+	if resp, ok := msg.(request.ResponseMsg); !ok || resp.Error != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("sendRequest() = %+v", msg)
+	}
+}
+
+func createBodyTestSpec(serverURL string) *openapi.Spec {
+	return &openapi.Spec{
+		Servers: []openapi.Server{{URL: serverURL}},
+		Paths: []openapi.Path{{
+			Path: "/pets",
+			Operations: []openapi.Operation{{
+				Method: "POST",
+				RequestBody: &openapi.RequestBody{
+					Content: map[string]openapi.MediaType{
+						"application/x-www-form-urlencoded": {
+							Schema: &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{
+								"name": {Type: "string", Example: "rex"},
+							}},
+						},
+						"application/json": {
+							Schema: &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{
+								"id":   {Type: "integer", ReadOnly: true},
+								"name": {Type: "string", Example: "rex"},
+								"tags": {Type: "array", Items: &openapi.Schema{Type: "string", Enum: []interface{}{"cute"}}},
+							}},
+						},
+					},
+				},
+			}},
+		}},
+	}
+}
+
+func TestSetupRequestBuilderBodyEditor(t *testing.T) {
+	model := NewModel(createBodyTestSpec("https://api.example.com"))
+	model.setupRequestBuilder()
+
+	if !model.hasBody {
+		t.Fatal("setupRequestBuilder() should show the body editor for optional bodies")
+	}
+
+	if !model.bodyFocused() {
+		t.Error("body editor should be focused when there are no parameters")
+	}
+
+	if got := model.selectedContentType(); got != "application/json" {
+		t.Errorf("selectedContentType() = %q, want application/json first", got)
+	}
+
+	expected := "{\n  \"name\": \"rex\",\n  \"tags\": [\n    \"cute\"\n  ]\n}"
+	if got := model.bodyEditor.Value(); got != expected {
+		t.Errorf("body = %q, want %q", got, expected)
+	}
+
+	updated, _ := model.handleRequestBuilderKeys(tea.KeyMsg{Type: tea.KeyCtrlT})
+	model = updated.(Model)
+
+	if got := model.selectedContentType(); got != "application/x-www-form-urlencoded" {
+		t.Errorf("selectedContentType() after ctrl+t = %q", got)
+	}
+
+	if got := model.bodyEditor.Value(); got != "name=rex" {
+		t.Errorf("form body = %q, want name=rex", got)
+	}
+
+	if !strings.Contains(model.renderRequestBuilder(), "2/2") {
+		t.Error("renderRequestBuilder() should show the content type picker")
+	}
+}
+
+func TestSwitchContentTypeKeepsEditedBody(t *testing.T) {
+	model := NewModel(createBodyTestSpec("https://api.example.com"))
+	model.setupRequestBuilder()
+
+	model.bodyEditor.SetValue(`{"name": "typed"}`)
+
+	updated, _ := model.handleRequestBuilderKeys(tea.KeyMsg{Type: tea.KeyCtrlT})
+	model = updated.(Model)
+
+	if got := model.bodyEditor.Value(); got != "name=rex" {
+		t.Errorf("form body = %q, want an example", got)
+	}
+
+	model.bodyEditor.SetValue("name=typed")
+
+	updated, _ = model.handleRequestBuilderKeys(tea.KeyMsg{Type: tea.KeyCtrlT})
+	model = updated.(Model)
+
+	if got := model.bodyEditor.Value(); got != `{"name": "typed"}` {
+		t.Errorf("JSON body = %q, want what was typed", got)
+	}
+
+	updated, _ = model.handleRequestBuilderKeys(tea.KeyMsg{Type: tea.KeyCtrlT})
+	model = updated.(Model)
+
+	if got := model.bodyEditor.Value(); got != "name=typed" {
+		t.Errorf("form body = %q, want what was typed", got)
+	}
+}
+
+func TestRequestBuilderBodyEditorTyping(t *testing.T) {
+	model := NewModel(createBodyTestSpec("https://api.example.com"))
+	model.setupRequestBuilder()
+	model.bodyEditor.SetValue("")
+
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("jk")},
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune("x")},
+	} {
+		updated, _ := model.handleRequestBuilderKeys(key)
+		model = updated.(Model)
+	}
+
+	if got := model.bodyEditor.Value(); got != "jk\nx" {
+		t.Errorf("body = %q, keys should be typed into the editor", got)
+	}
+}
+
+func TestRequestBuilderBodyJSONError(t *testing.T) {
+	model := NewModel(createBodyTestSpec("https://api.example.com"))
+	model.setupRequestBuilder()
+	model.bodyEditor.SetValue("{\n  \"name\": \"rex\",\n}")
+
+	err := model.bodyError()
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("bodyError() = %v, want an error on line 3", err)
+	}
+
+	if !strings.Contains(model.renderRequestBuilder(), "invalid JSON") {
+		t.Error("renderRequestBuilder() should show the JSON error")
+	}
+
+	if cmd := model.sendRequest(); cmd != nil {
+		t.Error("sendRequest() should not send a body with invalid JSON")
+	}
+
+	for _, key := range []tea.KeyType{tea.KeyCtrlG, tea.KeyCtrlX} {
+		model.builderStatus = ""
+
+		updated, cmd := model.handleRequestBuilderKeys(tea.KeyMsg{Type: key})
+		m := updated.(Model)
+
+		if cmd != nil || m.currentView != model.currentView {
+			t.Errorf("%s should do nothing with invalid JSON", tea.KeyMsg{Type: key})
+		}
+
+		if !strings.Contains(m.builderStatus, "invalid JSON at line 3") {
+			t.Errorf("%s status = %q, want the JSON error", tea.KeyMsg{Type: key}, m.builderStatus)
+		}
+	}
+}
+
+func TestSendRequestBodyContentType(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Content-Type"); got != "application/x-www-form-urlencoded" {
+			t.Errorf("Content-Type = %q", got)
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("name") != "rex" {
+			t.Errorf("form = %v, %v", r.PostForm, err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	model := NewModel(createBodyTestSpec(server.URL))
+	model.setupRequestBuilder()
+	model.contentTypeIndex = 1
+	model.bodyEditor.SetValue(model.exampleBody())
+
+	msg := model.sendRequest()()
+	if resp, ok := msg.(request.ResponseMsg); !ok || resp.Error != nil {
+		t.Errorf("sendRequest() = %+v", msg)
+	}
+}
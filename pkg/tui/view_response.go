@@ -3,10 +3,12 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ksysoev/tapi/internal/styles"
 	"github.com/ksysoev/tapi/pkg/formatter"
+	"github.com/ksysoev/tapi/pkg/openapi"
 	"github.com/ksysoev/tapi/pkg/request"
 )
 
@@ -20,7 +22,17 @@ func (m Model) handleResponseKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.viewport.HalfViewDown()
 	case "u":
 		m.viewport.HalfViewUp()
+	case "x":
+		if m.lastRequest != nil {
+			m.openExport(*m.lastRequest)
+		}
+	case "H":
+		return m, m.openHistory()
 	case "h", "left":
+		if m.responseFromHistory {
+			m.currentView = viewHistory
+			return m, nil
+		}
 		m.currentView = viewRequestBuilder
 	}
 	return m, nil
@@ -36,8 +48,19 @@ func (m Model) formatResponse(resp request.ResponseMsg) string {
 	}
 
 	b.WriteString(styles.SuccessStyle.Render(fmt.Sprintf("Response: %d %s", resp.StatusCode, resp.Status)))
+	if resp.Duration > 0 {
+		b.WriteString(styles.HelpStyle.UnsetPadding().Render(fmt.Sprintf("  %s", resp.Duration.Round(time.Millisecond))))
+	}
 	b.WriteString("\n\n")
 
+	if resp.Request.Path != "" {
+		violations, checked := m.spec.ValidateResponse(resp.Request.Path, resp.Request.Method, resp.StatusCode, resp.Headers, []byte(resp.Body))
+		if checked {
+			b.WriteString(renderViolations("Response", violations))
+			b.WriteString("\n")
+		}
+	}
+
 	b.WriteString(styles.LabelStyle.Render("Headers:"))
 	b.WriteString("\n")
 	for key, values := range resp.Headers {
@@ -57,3 +80,26 @@ func (m Model) formatResponse(resp request.ResponseMsg) string {
 
 	return b.String()
 }
+
+// renderViolations shows the contract check of a request or response, one
+// violation per line.
+func renderViolations(subject string, violations []openapi.Violation) string {
+	if len(violations) == 0 {
+		return styles.SuccessStyle.Render("✓ "+subject+" matches the spec") + "\n"
+	}
+
+	var b strings.Builder
+
+	b.WriteString(styles.ErrorStyle.Render(fmt.Sprintf("✗ %s doesn't match the spec:", subject)))
+	b.WriteString("\n")
+
+	for _, v := range violations {
+		if v.Path != "" {
+			b.WriteString("  " + styles.LabelStyle.UnsetMarginRight().Render(v.Path+":") + " " + v.Message + "\n")
+		} else {
+			b.WriteString("  " + v.Message + "\n")
+		}
+	}
+
+	return b.String()
+}
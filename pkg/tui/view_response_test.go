@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/pkg/openapi"
 	"github.com/ksysoev/tapi/pkg/request"
 )
 
@@ -194,3 +195,88 @@ func TestFormatResponseEmptyBody(t *testing.T) {
 		t.Error("formatResponse() missing status line")
 	}
 }
+
+func TestFormatResponseValidation(t *testing.T) {
+	spec, err := openapi.LoadFromFile("../../example-petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := NewModel(spec)
+
+	sent := request.Request{Method: "GET", Path: "/pet/{petId}"}
+	jsonHeader := http.Header{"Content-Type": []string{"application/json"}}
+
+	tests := []struct {
+		name string
+		resp request.ResponseMsg
+		want []string
+	}{
+		{
+			name: "matches",
+			resp: request.ResponseMsg{StatusCode: 200, Status: "200 OK", Headers: jsonHeader, Body: `{"id":1,"name":"rex"}`, Request: sent},
+			want: []string{"✓ Response matches the spec"},
+		},
+		{
+			name: "schema violation",
+			resp: request.ResponseMsg{StatusCode: 200, Status: "200 OK", Headers: jsonHeader, Body: `{"id":"one"}`, Request: sent},
+			want: []string{"✗ Response doesn't match the spec", "$.id", "value must be an integer"},
+		},
+		{
+			name: "undeclared status",
+			resp: request.ResponseMsg{StatusCode: 500, Status: "500 Internal Server Error", Request: sent},
+			want: []string{"status 500 is not declared for GET /pet/{petId}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatted := model.formatResponse(tt.resp)
+			for _, want := range tt.want {
+				if !strings.Contains(formatted, want) {
+					t.Errorf("formatResponse() missing %q in output:\n%s", want, formatted)
+				}
+			}
+		})
+	}
+
+	// Responses that aren't tied to an operation aren't checked.
+	if formatted := model.formatResponse(request.ResponseMsg{StatusCode: 200, Status: "200 OK"}); strings.Contains(formatted, "the spec") {
+		t.Errorf("formatResponse() shouldn't check a response without a request:\n%s", formatted)
+	}
+}
+
+func TestRenderViolations(t *testing.T) {
+	tests := []struct {
+		name       string
+		violations []openapi.Violation
+		want       []string
+	}{
+		{
+			name: "none",
+			want: []string{"✓ Response matches the spec\n"},
+		},
+		{
+			name: "with and without path",
+			violations: []openapi.Violation{
+				{Path: "$.name", Message: "value must be a string"},
+				{Message: "status 500 is not declared"},
+			},
+			want: []string{
+				"✗ Response doesn't match the spec:\n",
+				"  $.name: value must be a string\n",
+				"  status 500 is not declared\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rendered := renderViolations("Response", tt.violations)
+			for _, want := range tt.want {
+				if !strings.Contains(rendered, want) {
+					t.Errorf("renderViolations() missing %q in output:\n%s", want, rendered)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/internal/styles"
+	"github.com/ksysoev/tapi/pkg/environment"
+	"github.com/ksysoev/tapi/pkg/openapi"
+)
+
+// serverInput is an editable field on the server picker: a server variable,
+// or the custom URL when name is empty.
+type serverInput struct {
+	name  string
+	input textinput.Model
+}
+
+// Option customizes a Model created by NewModel.
+type Option func(*Model)
+
+// WithServer sends requests to url instead of the servers declared in the
+// spec.
+func WithServer(url string) Option {
+	return func(m *Model) {
+		m.customServer = url
+	}
+}
+
+// baseURL resolves the server a request for op is sent to. A custom server
+// always wins, then the active environment's baseUrl, then the server picked
+// from the operation's own servers or the document's. Variables are filled
+// from the values entered on the picker.
+func (m Model) baseURL(op *openapi.Operation) string {
+	if m.customServer != "" {
+		return m.interpolate(m.customServer)
+	}
+
+	if envURL := m.envVars()[environment.BaseURLVar]; envURL != "" {
+		return m.interpolate(envURL)
+	}
+
+	index := m.serverIndex(op)
+	servers := m.spec.ServersFor(op)
+	if index >= len(servers) {
+		return ""
+	}
+
+	return servers[index].ResolveURL(m.serverVars)
+}
+
+// serverIndex is the server picked for op: one of its own servers when it
+// declares any, which is remembered per operation, or a document server.
+func (m Model) serverIndex(op *openapi.Operation) int {
+	if op != nil && len(op.Servers) > 0 {
+		return m.operationServers[op]
+	}
+	return m.selectedServer
+}
+
+// pickerServers are the servers the picker offers for the current operation.
+func (m Model) pickerServers() []openapi.Server {
+	return m.spec.ServersFor(m.getCurrentOperation())
+}
+
+func (m Model) handleServersKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	inList := m.serverFocus < 0
+
+	switch msg.String() {
+	case "ctrl+s":
+		m.applyServer()
+		return m, nil
+	case "enter":
+		if inList && len(m.serverInputs) > 0 {
+			return m, m.focusServerInput(0)
+		}
+		m.applyServer()
+		return m, nil
+	case "tab":
+		if len(m.serverInputs) > 0 {
+			return m, m.focusServerInput(m.serverFocus + 1)
+		}
+	case "shift+tab":
+		if len(m.serverInputs) > 0 {
+			return m, m.focusServerInput(m.serverFocus - 1)
+		}
+	case "j", "down":
+		if inList && m.serverCursor < len(m.pickerServers()) {
+			m.serverCursor++
+			m.setupServerInputs()
+			return m, nil
+		}
+	case "k", "up":
+		if inList && m.serverCursor > 0 {
+			m.serverCursor--
+			m.setupServerInputs()
+			return m, nil
+		}
+	case "esc":
+		m.currentView = m.serverReturnView
+		m.showHelp = false
+		return m, nil
+	}
+
+	if !inList {
+		var cmd tea.Cmd
+		field := &m.serverInputs[m.serverFocus]
+		field.input, cmd = field.input.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// openServers switches to the server picker with the active server under
+// the cursor.
+func (m *Model) openServers() tea.Cmd {
+	m.serverReturnView = m.currentView
+	m.currentView = viewServers
+
+	m.serverCursor = m.serverIndex(m.getCurrentOperation())
+	if m.customServer != "" {
+		m.serverCursor = len(m.pickerServers())
+	}
+	m.setupServerInputs()
+
+	return nil
+}
+
+// setupServerInputs creates inputs for the variables of the server under the
+// cursor, or for the URL when the custom entry is selected.
+func (m *Model) setupServerInputs() {
+	m.serverInputs = make([]serverInput, 0)
+	m.serverFocus = -1
+
+	servers := m.pickerServers()
+	if m.serverCursor >= len(servers) {
+		ti := textinput.New()
+		ti.Prompt = "URL: "
+		ti.Placeholder = "http://localhost:8080"
+		ti.CharLimit = 2048
+		ti.Width = 60
+		ti.SetValue(m.customServer)
+		m.serverInputs = append(m.serverInputs, serverInput{input: ti})
+		return
+	}
+
+	server := servers[m.serverCursor]
+	for _, name := range server.VariableNames() {
+		variable := server.Variables[name]
+
+		ti := textinput.New()
+		ti.Prompt = fmt.Sprintf("%s: ", name)
+		ti.Placeholder = variable.Default
+		ti.CharLimit = 256
+		ti.Width = 40
+		ti.SetValue(m.serverVars[name])
+		m.serverInputs = append(m.serverInputs, serverInput{name: name, input: ti})
+	}
+}
+
+// focusServerInput focuses the input at index, wrapping back to the server
+// list (-1) past either end.
+func (m *Model) focusServerInput(index int) tea.Cmd {
+	switch {
+	case index >= len(m.serverInputs):
+		index = -1
+	case index < -1:
+		index = len(m.serverInputs) - 1
+	}
+
+	m.serverFocus = index
+
+	var cmd tea.Cmd
+	for i := range m.serverInputs {
+		if i == index {
+			cmd = m.serverInputs[i].input.Focus()
+		} else {
+			m.serverInputs[i].input.Blur()
+		}
+	}
+
+	return cmd
+}
+
+// applyServer makes the server under the cursor active and stores the
+// entered variables, then returns to the previous view.
+func (m *Model) applyServer() {
+	op := m.getCurrentOperation()
+
+	if m.serverCursor >= len(m.spec.ServersFor(op)) {
+		url := ""
+		if len(m.serverInputs) > 0 {
+			url = strings.TrimSpace(m.serverInputs[0].input.Value())
+		}
+		// An empty custom URL falls back to the spec's servers.
+		m.customServer = url
+	} else {
+		m.customServer = ""
+		if op != nil && len(op.Servers) > 0 {
+			if m.operationServers == nil {
+				m.operationServers = make(map[*openapi.Operation]int)
+			}
+			m.operationServers[op] = m.serverCursor
+		} else {
+			m.selectedServer = m.serverCursor
+		}
+
+		if m.serverVars == nil {
+			m.serverVars = make(map[string]string)
+		}
+		for _, field := range m.serverInputs {
+			m.serverVars[field.name] = strings.TrimSpace(field.input.Value())
+		}
+	}
+
+	m.currentView = m.serverReturnView
+}
+
+func (m Model) renderServers() string {
+	var b strings.Builder
+
+	b.WriteString(styles.TitleStyle.Render("Servers"))
+	b.WriteString("\n\n")
+
+	envURL := m.envVars()[environment.BaseURLVar]
+	if envURL != "" && m.customServer == "" {
+		b.WriteString(styles.HelpStyle.UnsetPadding().Render(
+			fmt.Sprintf("Environment %s sets %s to %s", m.activeEnv, environment.BaseURLVar, envURL)))
+		b.WriteString("\n\n")
+	} else if op := m.getCurrentOperation(); op != nil && len(op.Servers) > 0 {
+		b.WriteString(styles.HelpStyle.UnsetPadding().Render(
+			fmt.Sprintf("%s %s declares its own servers", op.Method, m.getCurrentPath().Path)))
+		b.WriteString("\n\n")
+	}
+
+	servers := m.pickerServers()
+	selected := m.serverIndex(m.getCurrentOperation())
+
+	for i := 0; i <= len(servers); i++ {
+		active := i == selected && m.customServer == ""
+
+		var line string
+		if i < len(servers) {
+			server := servers[i]
+			line = server.ResolveURL(m.serverVars)
+			if server.Description != "" {
+				line += " " + styles.HelpStyle.UnsetPadding().Render(server.Description)
+			}
+		} else {
+			active = m.customServer != ""
+			line = "Custom URL"
+			if m.customServer != "" {
+				line += ": " + m.customServer
+			}
+		}
+
+		if active {
+			line += styles.SuccessStyle.Render(" ✓")
+		}
+
+		if i == m.serverCursor {
+			b.WriteString(styles.SelectedItemStyle.Render("▶ " + line))
+		} else {
+			b.WriteString(styles.ItemStyle.Render(line))
+		}
+		b.WriteString("\n")
+
+		if i == m.serverCursor {
+			b.WriteString(m.renderServerInputs())
+		}
+	}
+
+	// Help to fix issue that content is not possible to scroll down fully
+	b.WriteString("\n\n\n\n")
+
+	return b.String()
+}
+
+func (m Model) renderServerInputs() string {
+	var b strings.Builder
+
+	var server *openapi.Server
+	if servers := m.pickerServers(); m.serverCursor < len(servers) {
+		server = &servers[m.serverCursor]
+	}
+
+	for i, field := range m.serverInputs {
+		if i == m.serverFocus {
+			b.WriteString(styles.FocusedInputStyle.Render(field.input.View()))
+		} else {
+			b.WriteString(styles.InputStyle.Render(field.input.View()))
+		}
+		b.WriteString("\n")
+
+		if server == nil {
+			continue
+		}
+
+		variable := server.Variables[field.name]
+		hint := variable.Description
+		if len(variable.Enum) > 0 {
+			value := field.input.Value()
+			if value != "" && !slices.Contains(variable.Enum, value) {
+				b.WriteString(styles.ErrorStyle.Render(fmt.Sprintf("  %q is not one of: %s", value, strings.Join(variable.Enum, ", "))))
+				b.WriteString("\n")
+				continue
+			}
+			hint = strings.TrimSpace(hint + " one of: " + strings.Join(variable.Enum, ", "))
+		}
+
+		if hint != "" {
+			b.WriteString(styles.HelpStyle.UnsetPadding().Render("  " + hint))
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
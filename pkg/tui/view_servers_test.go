@@ -0,0 +1,199 @@
+package tui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ksysoev/tapi/pkg/openapi"
+	"github.com/ksysoev/tapi/pkg/request"
+)
+
+func createServersTestSpec() *openapi.Spec {
+	return &openapi.Spec{
+		Title:   "Servers API",
+		Version: "1.0.0",
+		Servers: []openapi.Server{
+			{URL: "https://api.example.com", Description: "Production"},
+			{
+				URL:         "https://{region}.example.com/{version}",
+				Description: "Regional",
+				Variables: map[string]openapi.ServerVariable{
+					"region":  {Default: "eu", Enum: []string{"eu", "us"}},
+					"version": {Default: "v1"},
+				},
+			},
+		},
+		Paths: []openapi.Path{
+			{Path: "/pets", Operations: []openapi.Operation{{Method: "GET"}}},
+			{Path: "/uploads", Operations: []openapi.Operation{{
+				Method:  "POST",
+				Servers: []openapi.Server{{URL: "https://uploads.example.com"}},
+			}}},
+		},
+	}
+}
+
+func pressKeys(m Model, keys ...tea.KeyMsg) Model {
+	for _, key := range keys {
+		updated, _ := m.handleKeyPress(key)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestBaseURL(t *testing.T) {
+	m := NewModel(createServersTestSpec())
+	pets := &m.spec.Paths[0].Operations[0]
+	uploads := &m.spec.Paths[1].Operations[0]
+
+	if got := m.baseURL(pets); got != "https://api.example.com" {
+		t.Errorf("baseURL() = %q, want the first server", got)
+	}
+
+	m.selectedServer = 1
+	m.serverVars = map[string]string{"region": "us"}
+	if got := m.baseURL(pets); got != "https://us.example.com/v1" {
+		t.Errorf("baseURL() = %q, want variables substituted", got)
+	}
+
+	if got := m.baseURL(uploads); got != "https://uploads.example.com" {
+		t.Errorf("baseURL() = %q, want the operation override", got)
+	}
+
+	m = NewModel(createServersTestSpec(), WithServer("http://localhost:8080"))
+	if got := m.baseURL(uploads); got != "http://localhost:8080" {
+		t.Errorf("baseURL() = %q, want the custom server", got)
+	}
+}
+
+func TestServerPickerSelectWithVariables(t *testing.T) {
+	m := NewModel(createServersTestSpec())
+	m.height = 40
+
+	m = pressKeys(m, runes("s"))
+	if m.currentView != viewServers {
+		t.Fatalf("s should open the server picker, got view %v", m.currentView)
+	}
+
+	// move to the regional server and edit its first variable (region)
+	m = pressKeys(m, runes("j"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.serverFocus != 0 || len(m.serverInputs) != 2 {
+		t.Fatalf("enter should focus the first variable, focus=%d inputs=%d", m.serverFocus, len(m.serverInputs))
+	}
+
+	m = pressKeys(m, runes("xx"))
+	if !strings.Contains(m.renderServers(), `"xx" is not one of: eu, us`) {
+		t.Error("renderServers() should flag values outside the enum")
+	}
+
+	m = pressKeys(m,
+		tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace},
+		runes("us"), tea.KeyMsg{Type: tea.KeyEnter},
+	)
+
+	if m.currentView != viewEndpoints {
+		t.Errorf("enter in a variable should apply and go back, got view %v", m.currentView)
+	}
+
+	if got := m.baseURL(m.getCurrentOperation()); got != "https://us.example.com/v1" {
+		t.Errorf("baseURL() = %q after picking the regional server", got)
+	}
+
+	if !strings.Contains(m.renderHeader(), "https://us.example.com/v1") {
+		t.Error("renderHeader() should show the active server")
+	}
+}
+
+func TestServerPickerCustomURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	m := NewModel(createServersTestSpec())
+	m = pressKeys(m, runes("s"), runes("j"), runes("j"), tea.KeyMsg{Type: tea.KeyEnter}, runes(server.URL), tea.KeyMsg{Type: tea.KeyCtrlS})
+
+	if m.customServer != server.URL {
+		t.Fatalf("customServer = %q, want %q", m.customServer, server.URL)
+	}
+
+	m.setupRequestBuilder()
+	msg := m.sendRequest()()
+	if resp, ok := msg.(request.ResponseMsg); !ok || resp.StatusCode != http.StatusNoContent {
+		t.Errorf("sendRequest() = %+v, want the request to reach the custom server", msg)
+	}
+
+	// clearing the custom URL falls back to the spec servers
+	m = pressKeys(m, runes("s"), tea.KeyMsg{Type: tea.KeyEnter}, tea.KeyMsg{Type: tea.KeyCtrlU}, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.customServer != "" || m.baseURL(nil) != "https://api.example.com" {
+		t.Errorf("empty custom URL should fall back to the spec servers, got %q", m.customServer)
+	}
+}
+
+func TestServerPickerOperationServers(t *testing.T) {
+	spec := createServersTestSpec()
+	spec.Paths[1].Operations[0].Servers = []openapi.Server{
+		{URL: "https://uploads.example.com"},
+		{
+			URL: "https://{bucket}.storage.example.com",
+			Variables: map[string]openapi.ServerVariable{
+				"bucket": {Default: "media"},
+			},
+		},
+	}
+
+	m := NewModel(spec)
+	m.height = 40
+	uploads := &m.spec.Paths[1].Operations[0]
+
+	m = pressKeys(m, runes("j"), runes("s"))
+	if m.getCurrentOperation() != uploads {
+		t.Fatalf("current operation = %+v, want POST /uploads", m.getCurrentOperation())
+	}
+
+	out := m.renderServers()
+	if !strings.Contains(out, "https://media.storage.example.com") || strings.Contains(out, "https://api.example.com") {
+		t.Errorf("renderServers() should list the operation's servers instead of the document's:\n%s", out)
+	}
+
+	m = pressKeys(m, runes("j"), tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.serverInputs) != 1 || m.serverInputs[0].name != "bucket" {
+		t.Fatalf("serverInputs = %+v, want the bucket variable", m.serverInputs)
+	}
+
+	m = pressKeys(m, runes("avatars"), tea.KeyMsg{Type: tea.KeyEnter})
+
+	if got := m.baseURL(uploads); got != "https://avatars.storage.example.com" {
+		t.Errorf("baseURL() = %q, want the picked operation server", got)
+	}
+
+	if got := m.baseURL(&m.spec.Paths[0].Operations[0]); got != "https://api.example.com" {
+		t.Errorf("baseURL() = %q, other operations should keep the document server", got)
+	}
+}
+
+func TestServerPickerCancel(t *testing.T) {
+	m := NewModel(createServersTestSpec())
+	m.height = 40
+
+	m = pressKeys(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.currentView != viewOperationDetails {
+		t.Fatalf("enter should open the operation details, got view %v", m.currentView)
+	}
+
+	m = pressKeys(m, runes("s"), runes("j"), tea.KeyMsg{Type: tea.KeyEsc})
+
+	if m.currentView != viewOperationDetails {
+		t.Errorf("esc should return to the operation details, got view %v", m.currentView)
+	}
+	if m.selectedServer != 0 {
+		t.Errorf("cancelling should keep the server, got %d", m.selectedServer)
+	}
+}